@@ -0,0 +1,169 @@
+// Command catalog imports and exports the project catalog in the shape of the
+// client's Project type (client/src/data/projects.ts).
+//
+// Usage:
+//
+//	go run ./cmd/catalog import [-format json|yaml] [-dry-run] FILE
+//	go run ./cmd/catalog export [-format json|yaml] [-o FILE]
+//
+// FILE may be "-" for stdin; the format defaults to the file extension.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/catalog"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+)
+
+// importCatalog imports items in one transaction, together with the
+// projects.imported event that lets running servers drop what they cached.
+// A write error rolls the whole import back.
+func importCatalog(ctx context.Context, conn *sql.DB, queries *db.Queries, items []catalog.Item) (catalog.Report, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return catalog.Report{}, err
+	}
+	q := queries.WithTx(tx)
+	report := catalog.Import(ctx, q, items, false)
+	if n := report.Counts[catalog.ActionError]; n > 0 {
+		_ = tx.Rollback()
+		return report, fmt.Errorf("%d item(s) could not be written", n)
+	}
+	if err := bus.New(queries).Outbox(q).Add(ctx, bus.ProjectsImported, bus.Change{}); err != nil {
+		_ = tx.Rollback()
+		return report, err
+	}
+	return report, tx.Commit()
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: catalog <import|export> [flags]")
+	fmt.Fprintln(os.Stderr, "  import  upsert projects from a JSON/YAML file (by slug)")
+	fmt.Fprintln(os.Stderr, "  export  write all projects as JSON/YAML")
+	fmt.Fprintln(os.Stderr, "run 'catalog <command> -h' for flags")
+}
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	format := fs.String("format", "", "json or yaml (default: from file extension, else json)")
+	dryRun := fs.Bool("dry-run", false, "validate and report without writing (import only)")
+	out := fs.String("o", "-", "output file for export, - for stdout")
+
+	switch cmd {
+	case "import", "export":
+		_ = fs.Parse(args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		log.Fatal("DATABASE_URL is not set")
+	}
+	conn, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer conn.Close()
+	queries := db.New(conn)
+	ctx := context.Background()
+
+	if cmd == "export" {
+		f := resolveFormat(*format, *out)
+		items, err := catalog.Export(ctx, queries)
+		if err != nil {
+			log.Fatalf("❌ export failed: %v", err)
+		}
+		data, err := catalog.Encode(items, f)
+		if err != nil {
+			log.Fatalf("❌ export failed: %v", err)
+		}
+		if *out == "-" {
+			_, _ = os.Stdout.Write(data)
+			return
+		}
+		if err := os.WriteFile(*out, data, 0o644); err != nil {
+			log.Fatalf("❌ export failed: %v", err)
+		}
+		log.Printf("✅ Exported %d project(s) to %s", len(items), *out)
+		return
+	}
+
+	if fs.NArg() != 1 {
+		log.Fatal("import needs exactly one FILE argument (or - for stdin)")
+	}
+	path := fs.Arg(0)
+	var data []byte
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		log.Fatalf("❌ Failed to read %s: %v", path, err)
+	}
+
+	items, err := catalog.Decode(data, resolveFormat(*format, path))
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	var report catalog.Report
+	if *dryRun {
+		report = catalog.Import(ctx, queries, items, true)
+	} else if report, err = importCatalog(ctx, conn, queries, items); err != nil {
+		log.Printf("❌ Import rolled back, nothing was written: %v", err)
+	}
+	for _, r := range report.Results {
+		log.Printf("%-9s #%-3d %s", r.Action, r.Index, r.Slug)
+		for _, e := range r.Errors {
+			log.Printf("          ↳ %s", e)
+		}
+	}
+	log.Printf("created=%d updated=%d unchanged=%d invalid=%d error=%d",
+		report.Counts[catalog.ActionCreated],
+		report.Counts[catalog.ActionUpdated],
+		report.Counts[catalog.ActionUnchanged],
+		report.Counts[catalog.ActionInvalid],
+		report.Counts[catalog.ActionError])
+	if report.Failed() {
+		os.Exit(1)
+	}
+}
+
+// resolveFormat prefers an explicit -format, then the file extension.
+func resolveFormat(flagValue, path string) string {
+	if flagValue != "" {
+		f, err := catalog.ParseFormat(flagValue)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return f
+	}
+	if path == "-" {
+		return catalog.FormatJSON
+	}
+	return catalog.FormatFromPath(path)
+}
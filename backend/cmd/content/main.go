@@ -0,0 +1,135 @@
+// Command content syncs blog posts between client/src/blog MDX files and the
+// posts table.
+//
+// Usage:
+//
+//	go run ./cmd/content import [-dir DIR] [-force] [-dry-run] [-watch] [-interval 2s]
+//	go run ./cmd/content export [-dir DIR] [-force] [-dry-run]
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/content"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+)
+
+const defaultDir = "../client/src/blog"
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: content <import|export> [flags]")
+	fmt.Fprintln(os.Stderr, "  import  upsert MDX files into posts (by slug)")
+	fmt.Fprintln(os.Stderr, "  export  write posts back to MDX files")
+	fmt.Fprintln(os.Stderr, "run 'content <command> -h' for flags")
+}
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	dir := fs.String("dir", defaultDir, "directory containing .mdx posts")
+	force := fs.Bool("force", false, "overwrite the destination even when both sides changed")
+	dryRun := fs.Bool("dry-run", false, "report what would change without writing")
+	watch := fs.Bool("watch", false, "keep running and import files as they change (import only)")
+	interval := fs.Duration("interval", 2*time.Second, "polling interval for -watch")
+
+	switch cmd {
+	case "import", "export":
+		_ = fs.Parse(args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		log.Fatal("DATABASE_URL is not set")
+	}
+	conn, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer conn.Close()
+
+	queries := db.New(conn)
+	syncer := &content.Syncer{
+		Queries: queries,
+		Conn:    conn,
+		Dir:     *dir,
+		Bus:     bus.New(queries),
+		Force:   *force,
+		DryRun:  *dryRun,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var results []content.Result
+	switch {
+	case cmd == "export":
+		results, err = syncer.Export(ctx)
+	case *watch:
+		log.Printf("👀 Watching %s (every %s, Ctrl+C to stop)", *dir, *interval)
+		syncer.Watch(ctx, *interval, func(results []content.Result, err error) {
+			if err != nil {
+				log.Printf("❌ Sync failed: %v", err)
+			}
+			printResults(results)
+		})
+		return
+	default:
+		results, err = syncer.Import(ctx)
+	}
+	if err != nil {
+		log.Fatalf("❌ %s failed: %v", cmd, err)
+	}
+
+	printResults(results)
+	if conflicts := countAction(results, content.ActionConflict); conflicts > 0 {
+		log.Printf("⚠️  %d conflict(s); resolve them by hand or re-run with -force", conflicts)
+		os.Exit(1)
+	}
+	if countAction(results, content.ActionError) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printResults(results []content.Result) {
+	for _, r := range results {
+		line := fmt.Sprintf("%-9s %-40s %s", r.Action, r.Slug, r.File)
+		if r.Detail != "" {
+			line += " — " + r.Detail
+		}
+		log.Println(line)
+	}
+}
+
+func countAction(results []content.Result, action string) int {
+	n := 0
+	for _, r := range results {
+		if r.Action == action {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,72 @@
+// Command export renders the public API to static JSON/XML files so the
+// client can be hosted on GitHub Pages without the Go server.
+//
+// Usage:
+//
+//	go run ./cmd/export [-out DIR] [-page-size 10] [-dry-run]
+//
+// Point the client at the output with VITE_API_STATIC=true (see client/src/utils/api.ts).
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/gorilla/mux"
+	"github.com/joho/godotenv"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/api/handlers"
+	"github.com/onnwee/onnwee.github.io/backend/internal/export"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	out := flag.String("out", "../client/dist/api", "output directory (the client's API base)")
+	pageSize := flag.Int("page-size", 10, "posts per page file (1-100)")
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	flag.Parse()
+
+	if *pageSize < 1 || *pageSize > 100 {
+		log.Fatal("-page-size must be between 1 and 100")
+	}
+	if os.Getenv("DATABASE_URL") == "" {
+		log.Fatal("DATABASE_URL is not set")
+	}
+
+	conn, err := server.InitDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to DB: %v", err)
+	}
+	s := server.NewServer(conn)
+
+	// Only the public read routes, without middleware: exporting must not
+	// record analytics or trip the rate limiter.
+	r := mux.NewRouter()
+	handlers.RegisterPublicProjectRoutes(r, s)
+	handlers.RegisterProjectUpdateRoutes(r, s)
+	handlers.RegisterPostRoutes(r, s)
+	handlers.RegisterTagRoutes(r, s)
+	handlers.RegisterSeriesRoutes(r, s)
+	handlers.RegisterFeedRoutes(r, s)
+
+	w, err := export.NewWriter(*out, *dryRun)
+	if err != nil {
+		log.Fatalf("❌ Failed to read previous export: %v", err)
+	}
+	e := &export.Exporter{Handler: r, Out: w, PageSize: *pageSize}
+	if err := e.Run(); err != nil {
+		log.Fatalf("❌ Export failed: %v", err)
+	}
+
+	verb := "Exported"
+	if *dryRun {
+		verb = "Would export"
+	}
+	log.Printf("✅ %s %d file(s) to %s (%d written, %d unchanged, %d removed)",
+		verb, len(w.Paths()), *out, w.Stats.Written, w.Stats.Unchanged, w.Stats.Removed)
+}
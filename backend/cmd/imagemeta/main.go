@@ -0,0 +1,104 @@
+// Command imagemeta computes the placeholder metadata of project images and
+// post covers whose metadata is missing or was computed for another URL. The
+// API does this on every save; run it after a catalog import, a content sync
+// or a migration.
+//
+// Usage:
+//
+//	go run ./cmd/imagemeta [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	dryRun := flag.Bool("dry-run", false, "list stale images without fetching them")
+	flag.Parse()
+
+	if os.Getenv("DATABASE_URL") == "" {
+		log.Fatal("DATABASE_URL is not set")
+	}
+	conn, err := server.InitDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to DB: %v", err)
+	}
+	defer conn.Close()
+	s := server.NewServer(conn)
+	ctx := context.Background()
+
+	projects, err := s.DB.ListProjectsWithStaleImageMeta(ctx)
+	if err != nil {
+		log.Fatalf("❌ Failed to list projects: %v", err)
+	}
+	posts, err := s.DB.ListPostsWithStaleCoverImageMeta(ctx)
+	if err != nil {
+		log.Fatalf("❌ Failed to list posts: %v", err)
+	}
+
+	// Each row is stored with the event that lets running servers drop
+	// their cached responses for it
+	failed := 0
+	for _, p := range projects {
+		if !refresh(ctx, s, "project", p.Slug, p.Image.String, *dryRun, func(meta []byte) error {
+			return s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+				if _, err := q.SetProjectImageMeta(ctx, db.SetProjectImageMetaParams{ID: p.ID, ImageMeta: meta}); err != nil {
+					return err
+				}
+				return out.Add(ctx, bus.ProjectUpdated, bus.Change{ProjectIDs: []int32{p.ID}})
+			})
+		}) {
+			failed++
+		}
+	}
+	for _, p := range posts {
+		if !refresh(ctx, s, "post", p.Slug, p.CoverImage, *dryRun, func(meta []byte) error {
+			return s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+				if _, err := q.SetPostCoverImageMeta(ctx, db.SetPostCoverImageMetaParams{ID: p.ID, CoverImageMeta: meta}); err != nil {
+					return err
+				}
+				return out.Add(ctx, bus.PostUpdated, bus.Change{PostIDs: []int32{p.ID}})
+			})
+		}) {
+			failed++
+		}
+	}
+
+	log.Printf("✅ %d stale image(s), %d failed", len(projects)+len(posts), failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// refresh computes and stores the metadata of one image, reporting whether it
+// succeeded. An image that cannot be loaded has its stale metadata cleared.
+func refresh(ctx context.Context, s *server.Server, kind, slug, src string, dryRun bool, store func([]byte) error) bool {
+	if dryRun {
+		log.Printf("stale    %-7s %-40s %s", kind, slug, src)
+		return true
+	}
+	meta, loadErr := s.Images.LoadJSON(ctx, src)
+	if err := store(meta); err != nil {
+		log.Printf("error    %-7s %-40s %v", kind, slug, err)
+		return false
+	}
+	if loadErr != nil {
+		log.Printf("failed   %-7s %-40s %s — %v", kind, slug, src, loadErr)
+		return false
+	}
+	log.Printf("updated  %-7s %-40s %s", kind, slug, src)
+	return true
+}
@@ -116,6 +116,7 @@ func main() {
 			RepoUrl:     sql.NullString{String: repoURL, Valid: true},
 			LiveUrl:     sql.NullString{String: liveURL, Valid: true},
 			UserID:      sql.NullInt32{Int32: userID, Valid: true},
+			Status:      "published",
 		})
 		if err != nil {
 			log.Printf("CreateProject error: %v", err)
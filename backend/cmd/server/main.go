@@ -13,6 +13,8 @@ import (
 	"github.com/onnwee/onnwee.github.io/backend/internal/api"
 	"github.com/onnwee/onnwee.github.io/backend/internal/observability"
 	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"github.com/onnwee/onnwee.github.io/backend/internal/spa"
+	"github.com/onnwee/onnwee.github.io/backend/web"
 )
 
 func main() {
@@ -30,18 +32,33 @@ func main() {
 	log.Println("OpenTelemetry initialized successfully")
 
 	// Init DB
-	queries, err := server.InitDB()
+	conn, err := server.InitDB()
 	if err != nil {
 		log.Fatalf("Failed to connect to DB: %v", err)
 	}
 
 	// Build your application router
-	appRouter := api.NewRouter(queries)
+	s := server.NewServer(conn)
+	appRouter := api.NewRouter(s)
+
+	// Receive domain events published by other instances
+	go s.Bus.Run(ctx, os.Getenv("DATABASE_URL"))
 
 	// Create a new ServeMux that includes /metrics and your app's router
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler()) // Expose Prometheus metrics
-	mux.Handle("/", appRouter)                 // Your main app routes
+	mux.Handle(server.APIPrefix+"/", http.StripPrefix(server.APIPrefix, appRouter))
+
+	// Every other path is the client: the embedded build, or a directory on
+	// disk during development
+	client := web.Dist()
+	if dir := os.Getenv("CLIENT_DIST_DIR"); dir != "" {
+		log.Printf("Serving the client from %s", dir)
+		client = os.DirFS(dir)
+	} else if !web.Built(client) {
+		log.Println("No client embedded; run `make client` to build it in")
+	}
+	mux.Handle("/", api.NewClientHandler(s, spa.New(client, s.LoadPage)))
 
 	// Start the server
 	port := os.Getenv("PORT")
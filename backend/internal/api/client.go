@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"github.com/onnwee/onnwee.github.io/backend/pkg/middleware"
+)
+
+// NewClientHandler wraps the handler serving the built client with the
+// middleware that applies to site paths: admin redirects and 404 tracking
+// match client URLs, not API ones. Pages and assets are not rate limited, so
+// loading the site does not use up a visitor's API requests; only the hits
+// redirects and 404 tracking record are, each visitor's against its own
+// budget. Analytics is left out, as it would count every asset as a page
+// view.
+func NewClientHandler(s *server.Server, client http.Handler) http.Handler {
+	writes := middleware.NewRateLimiter(middleware.RateLimitOptions{})
+	base := middleware.Chain(client, middleware.Logging, middleware.Recovery, middleware.Compress, middleware.RealIP, middleware.Redirects(s.DB, s.Redirects, writes), middleware.TrackNotFound(s.DB, writes), middleware.Metrics)
+	return otelhttp.NewHandler(base, "ClientHandler")
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/reactions"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"github.com/onnwee/onnwee.github.io/backend/pkg/middleware"
+)
+
+// newSharedRateLimiter returns a rate limiter that tells every instance
+// about a visitor it rate limited, and blocks the visitors they did, so
+// spreading requests across replicas does not multiply the limit. Tokens are
+// still counted per limiter. Visitors are identified by a hash salted with
+// REACTION_SALT, so instances only match each other's visitors when they
+// share it.
+func newSharedRateLimiter(s *server.Server) *middleware.RateLimiter {
+	salt := reactions.Salt()
+	blockKey := func(ip string) string {
+		sum := sha256.Sum256([]byte(salt + "|rate-limit:" + ip))
+		return hex.EncodeToString(sum[:])
+	}
+	limiter := middleware.NewRateLimiter(middleware.RateLimitOptions{
+		BlockKey: blockKey,
+		RateLimited: func(ip string, until time.Time) {
+			rl := bus.RateLimit{Visitor: blockKey(ip), Until: until}
+			if err := s.Bus.Publish(context.Background(), bus.VisitorRateLimited, rl); err != nil {
+				log.Printf("Failed to publish %s: %v", bus.VisitorRateLimited, err)
+			}
+		},
+	})
+	s.Bus.Subscribe(bus.VisitorRateLimited, func(_ context.Context, e bus.Event) error {
+		var rl bus.RateLimit
+		if err := e.Decode(&rl); err != nil {
+			return err
+		}
+		limiter.Block(rl.Visitor, rl.Until)
+		return nil
+	})
+	return limiter
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import "strconv"
+
+// Response cache tags. A cached response is tagged with every record it was
+// rendered from; the domain events of a write name the records it touched,
+// and SubscribeCacheInvalidation maps them back to tags.
+const (
+	// postsTag and projectsTag cover responses built from a whole
+	// collection: lists, tags and feeds.
+	postsTag    = "posts"
+	projectsTag = "projects"
+)
+
+func postTag(id int32) string    { return "post:" + strconv.Itoa(int(id)) }
+func projectTag(id int32) string { return "project:" + strconv.Itoa(int(id)) }
+func seriesTag(id int32) string  { return "series:" + strconv.Itoa(int(id)) }
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/catalog"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"go.opentelemetry.io/otel"
+)
+
+// maxCatalogBytes caps the size of an imported catalog.
+const maxCatalogBytes = 5 << 20
+
+// errImportFailed rolls an import back when an item could not be written.
+var errImportFailed = errors.New("catalog: import failed")
+
+// registerProjectCatalogRoutes registers the bulk import/export routes for the
+// project catalog under the admin router.
+func registerProjectCatalogRoutes(r *mux.Router, s *server.Server) {
+	// POST /admin/projects/import - Upsert projects by slug (?format=json|yaml&dry_run=true)
+	r.HandleFunc("/projects/import", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("projects-handler")
+		ctx, span := tracer.Start(r.Context(), "ImportProjects")
+		defer span.End()
+
+		format := catalog.FormatFromContentType(r.Header.Get("Content-Type"))
+		if raw := r.URL.Query().Get("format"); raw != "" {
+			f, err := catalog.ParseFormat(raw)
+			if err != nil {
+				http.Error(w, `{"error":"Unsupported format"}`, http.StatusBadRequest)
+				return
+			}
+			format = f
+		}
+
+		dryRun := false
+		if raw := r.URL.Query().Get("dry_run"); raw != "" {
+			v, err := strconv.ParseBool(raw)
+			if err != nil {
+				http.Error(w, `{"error":"Invalid dry_run"}`, http.StatusBadRequest)
+				return
+			}
+			dryRun = v
+		}
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCatalogBytes))
+		if err != nil {
+			http.Error(w, `{"error":"Catalog too large"}`, http.StatusRequestEntityTooLarge)
+			return
+		}
+		items, err := catalog.Decode(data, format)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid catalog"}`, http.StatusBadRequest)
+			return
+		}
+
+		var report catalog.Report
+		start := time.Now()
+		if dryRun {
+			report = catalog.Import(ctx, s.DB, items, true)
+		} else {
+			// One transaction, so the event exists exactly when the import does
+			err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+				report = catalog.Import(ctx, q, items, false)
+				if report.Counts[catalog.ActionError] > 0 {
+					return errImportFailed
+				}
+				return out.Add(ctx, bus.ProjectsImported, bus.Change{})
+			})
+		}
+		metrics.ObserveDBQueryDuration("import_projects", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to import catalog; nothing was written"}`, http.StatusInternalServerError)
+			return
+		}
+
+		out, err := catalog.Marshal(report, format)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to encode report"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", catalog.ContentType(format))
+		_, _ = w.Write(out)
+	}).Methods("POST")
+
+	// GET /admin/projects/export - Export the catalog (?format=json|yaml)
+	r.HandleFunc("/projects/export", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("projects-handler")
+		ctx, span := tracer.Start(r.Context(), "ExportProjects")
+		defer span.End()
+
+		format, err := catalog.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			http.Error(w, `{"error":"Unsupported format"}`, http.StatusBadRequest)
+			return
+		}
+
+		start := time.Now()
+		items, err := catalog.Export(ctx, s.DB)
+		metrics.ObserveDBQueryDuration("export_projects", time.Since(start).Seconds())
+
+		if err != nil {
+			http.Error(w, `{"error":"Failed to export projects"}`, http.StatusInternalServerError)
+			return
+		}
+
+		data, err := catalog.Encode(items, format)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to encode projects"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", catalog.ContentType(format))
+		w.Header().Set("Content-Disposition", `attachment; filename="projects.`+format+`"`)
+		_, _ = w.Write(data)
+	}).Methods("GET")
+}
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/listquery"
+	"github.com/onnwee/onnwee.github.io/backend/internal/markdown"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/moderation"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
+	"github.com/onnwee/onnwee.github.io/backend/pkg/middleware"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	maxCommentBody   = 5000
+	maxCommentAuthor = 100
+	maxCommentEmail  = 254
+)
+
+// commentListSpec is the sort/filter allowlist for GET /admin/comments.
+var commentListSpec = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"id":         {Column: "id", Type: listquery.Int, Sortable: true, Filterable: true},
+		"post_id":    {Column: "post_id", Type: listquery.Int, Filterable: true},
+		"parent_id":  {Column: "parent_id", Type: listquery.Int, Filterable: true},
+		"user_id":    {Column: "user_id", Type: listquery.Int, Filterable: true},
+		"status":     {Column: "status", Type: listquery.Text, Filterable: true},
+		"ip_hash":    {Column: "ip_hash", Type: listquery.Text, Filterable: true},
+		"created_at": {Column: "created_at", Type: listquery.Time, Sortable: true, Filterable: true},
+	},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 50,
+	MaxLimit:     200,
+}
+
+// commentInput is the body of POST /posts/{slug}/comments. Website is a
+// honeypot: the client renders it hidden, so only bots fill it in.
+type commentInput struct {
+	ParentID    *int32 `json:"parent_id"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Body        string `json:"body"`
+	Website     string `json:"website"`
+}
+
+// publicComment is an approved comment as shown to readers, with its replies.
+type publicComment struct {
+	ID         int32            `json:"id"`
+	ParentID   *int32           `json:"parent_id"`
+	AuthorName string           `json:"author_name"`
+	BodyHTML   string           `json:"body_html"`
+	CreatedAt  string           `json:"created_at"`
+	Replies    []*publicComment `json:"replies"`
+}
+
+// threadComments nests approved comments under their parents. Replies whose
+// parent is not visible are shown at the top level rather than dropped.
+func threadComments(comments []db.Comment, authors map[int32]*publicUser) []*publicComment {
+	byID := make(map[int32]*publicComment, len(comments))
+	for _, c := range comments {
+		pc := &publicComment{
+			ID:         c.ID,
+			AuthorName: c.AuthorName.String,
+			BodyHTML:   c.BodyHtml,
+			CreatedAt:  toTimeString(c.CreatedAt),
+			Replies:    []*publicComment{},
+		}
+		if c.ParentID.Valid {
+			parent := c.ParentID.Int32
+			pc.ParentID = &parent
+		}
+		if a := authorOf(authors, c.UserID); a != nil {
+			pc.AuthorName = a.Username
+		}
+		byID[c.ID] = pc
+	}
+
+	roots := []*publicComment{}
+	for _, c := range comments {
+		pc := byID[c.ID]
+		if parent, ok := byID[c.ParentID.Int32]; c.ParentID.Valid && ok {
+			parent.Replies = append(parent.Replies, pc)
+			continue
+		}
+		roots = append(roots, pc)
+	}
+	return roots
+}
+
+func validateComment(in *commentInput, authenticated bool) string {
+	in.AuthorName = strings.TrimSpace(in.AuthorName)
+	in.AuthorEmail = strings.TrimSpace(in.AuthorEmail)
+	in.Body = strings.TrimSpace(in.Body)
+
+	switch {
+	case in.Body == "":
+		return "body is required"
+	case utf8.RuneCountInString(in.Body) > maxCommentBody:
+		return "body is too long"
+	case !authenticated && in.AuthorName == "":
+		return "author_name is required"
+	case utf8.RuneCountInString(in.AuthorName) > maxCommentAuthor:
+		return "author_name is too long"
+	case len(in.AuthorEmail) > maxCommentEmail:
+		return "author_email is too long"
+	case in.AuthorEmail != "" && !strings.Contains(in.AuthorEmail, "@"):
+		return "author_email is invalid"
+	}
+	return ""
+}
+
+// RegisterCommentRoutes registers public comment routes
+func RegisterCommentRoutes(r *mux.Router, s *server.Server) {
+	// GET /posts/{slug}/comments - Approved comments for a post, threaded
+	r.HandleFunc("/posts/{slug}/comments", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("comments-handler")
+		ctx, span := tracer.Start(r.Context(), "ListComments")
+		defer span.End()
+
+		post, ok := publishedPost(w, r, s)
+		if !ok {
+			return
+		}
+
+		start := time.Now()
+		comments, err := s.DB.ListApprovedCommentsByPost(ctx, post.ID)
+		metrics.ObserveDBQueryDuration("list_approved_comments", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list comments"}`, http.StatusInternalServerError)
+			return
+		}
+
+		userIDs := make([]sql.NullInt32, len(comments))
+		for i, c := range comments {
+			userIDs[i] = c.UserID
+		}
+		authors, err := loadAuthors(ctx, s, userIDs)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to fetch authors"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(threadComments(comments, authors))
+	}).Methods("GET")
+
+	// POST /posts/{slug}/comments - Submit a comment (held for moderation unless signed in)
+	r.Handle("/posts/{slug}/comments", middleware.OptionalAuth(s.DB)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("comments-handler")
+		ctx, span := tracer.Start(r.Context(), "CreateComment")
+		defer span.End()
+
+		post, ok := publishedPost(w, r, s)
+		if !ok {
+			return
+		}
+
+		var in commentInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+		userID, authenticated := middleware.GetUserIDFromContext(ctx)
+		if msg := validateComment(&in, authenticated); msg != "" {
+			body, _ := json.Marshal(map[string]string{"error": msg})
+			http.Error(w, string(body), http.StatusBadRequest)
+			return
+		}
+
+		// Per-IP rate limit, counted in the database so it holds across restarts
+		ipHash := sql.NullString{String: utils.HashIP(utils.GetIP(r)), Valid: true}
+		start := time.Now()
+		recent, err := s.DB.CountRecentCommentsByIP(ctx, db.CountRecentCommentsByIPParams{
+			IpHash:    ipHash,
+			CreatedAt: sql.NullTime{Time: time.Now().Add(-moderation.RateWindow), Valid: true},
+		})
+		metrics.ObserveDBQueryDuration("count_recent_comments", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to create comment"}`, http.StatusInternalServerError)
+			return
+		}
+		if recent >= moderation.RateLimit {
+			http.Error(w, `{"error":"Too many comments, try again later"}`, http.StatusTooManyRequests)
+			return
+		}
+
+		var parentID sql.NullInt32
+		if in.ParentID != nil {
+			parent, err := s.DB.GetCommentByID(ctx, *in.ParentID)
+			if err == sql.ErrNoRows || (err == nil && (parent.PostID != post.ID || parent.Status != moderation.StatusApproved)) {
+				http.Error(w, `{"error":"Parent comment not found"}`, http.StatusBadRequest)
+				return
+			} else if err != nil {
+				http.Error(w, `{"error":"Failed to create comment"}`, http.StatusInternalServerError)
+				return
+			}
+			parentID = sql.NullInt32{Int32: parent.ID, Valid: true}
+		}
+
+		verdict := moderation.Check(moderation.Input{Body: in.Body, Honeypot: in.Website, Authenticated: authenticated})
+		html, err := markdown.Render(in.Body)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to render comment"}`, http.StatusInternalServerError)
+			return
+		}
+
+		params := db.CreateCommentParams{
+			PostID:      post.ID,
+			ParentID:    parentID,
+			AuthorName:  sql.NullString{String: in.AuthorName, Valid: in.AuthorName != ""},
+			AuthorEmail: sql.NullString{String: in.AuthorEmail, Valid: in.AuthorEmail != ""},
+			Body:        in.Body,
+			BodyHtml:    html,
+			Status:      verdict.Status,
+			SpamReasons: verdict.Reasons,
+			IpHash:      ipHash,
+			UserAgent:   sql.NullString{String: r.UserAgent(), Valid: r.UserAgent() != ""},
+		}
+		if authenticated {
+			params.UserID = sql.NullInt32{Int32: userID, Valid: true}
+		}
+
+		start = time.Now()
+		comment, err := s.DB.CreateComment(ctx, params)
+		metrics.ObserveDBQueryDuration("create_comment", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to create comment"}`, http.StatusInternalServerError)
+			return
+		}
+		metrics.IncrementComment(comment.Status)
+
+		// Spam is reported as pending so bots learn nothing from the response
+		status := comment.Status
+		if status == moderation.StatusSpam {
+			status = moderation.StatusPending
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":     comment.ID,
+			"status": status,
+		})
+	}))).Methods("POST")
+}
+
+// publishedPost loads the post named by {slug}, writing a 404 for missing or
+// draft posts.
+func publishedPost(w http.ResponseWriter, r *http.Request, s *server.Server) (db.Post, bool) {
+	start := time.Now()
+	post, err := s.DB.GetPostBySlug(r.Context(), mux.Vars(r)["slug"])
+	metrics.ObserveDBQueryDuration("get_post_by_slug", time.Since(start).Seconds())
+
+	if err == sql.ErrNoRows || (err == nil && post.IsDraft.Valid && post.IsDraft.Bool) {
+		http.Error(w, `{"error":"Post not found"}`, http.StatusNotFound)
+		return db.Post{}, false
+	} else if err != nil {
+		http.Error(w, `{"error":"Failed to get post"}`, http.StatusInternalServerError)
+		return db.Post{}, false
+	}
+	return post, true
+}
+
+// RegisterAdminCommentRoutes registers the moderation queue routes
+func RegisterAdminCommentRoutes(r *mux.Router, s *server.Server) {
+	// GET /admin/comments - List comments (?filter[status][eq]=pending&sort=-created_at)
+	r.HandleFunc("/comments", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("comments-handler")
+		ctx, span := tracer.Start(r.Context(), "AdminListComments")
+		defer span.End()
+
+		lq, err := listquery.Parse(r.URL.Query(), commentListSpec)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+		query, args := lq.Build("SELECT "+db.CommentColumns+" FROM comments", nil, nil)
+
+		start := time.Now()
+		comments, err := s.DB.QueryComments(ctx, query, args...)
+		metrics.ObserveDBQueryDuration("list_comments", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list comments"}`, http.StatusInternalServerError)
+			return
+		}
+		if comments == nil {
+			comments = []db.Comment{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(comments)
+	}).Methods("GET")
+
+	// POST /admin/comments/moderate - Set the status of many comments at once
+	r.HandleFunc("/comments/moderate", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("comments-handler")
+		ctx, span := tracer.Start(r.Context(), "ModerateComments")
+		defer span.End()
+
+		var body struct {
+			IDs    []int32 `json:"ids"`
+			Status string  `json:"status"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+		if len(body.IDs) == 0 {
+			http.Error(w, `{"error":"ids is required"}`, http.StatusBadRequest)
+			return
+		}
+		if !moderation.ValidStatus(body.Status) {
+			http.Error(w, `{"error":"status must be one of pending, approved, spam, deleted"}`, http.StatusBadRequest)
+			return
+		}
+
+		start := time.Now()
+		updated, err := s.DB.SetCommentStatus(ctx, db.SetCommentStatusParams{Status: body.Status, Ids: body.IDs})
+		metrics.ObserveDBQueryDuration("set_comment_status", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to moderate comments"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  body.Status,
+			"updated": updated,
+		})
+	}).Methods("POST")
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+)
+
+// addPostEvent adds the event for a saved post to out: post.published when
+// this save made it public, post.updated otherwise.
+func addPostEvent(ctx context.Context, out *bus.Outbox, post db.Post, wasPublished bool) error {
+	name := bus.PostUpdated
+	if !post.IsDraft.Bool && !wasPublished {
+		name = bus.PostPublished
+	}
+	return out.Add(ctx, name, bus.Change{PostIDs: []int32{post.ID}})
+}
+
+// SubscribeCacheInvalidation drops cached responses when content changes on
+// this or any other instance.
+func SubscribeCacheInvalidation(s *server.Server) {
+	if s.Cache == nil {
+		return
+	}
+	s.Bus.Subscribe(bus.All, func(ctx context.Context, e bus.Event) error {
+		var tags []string
+		switch e.Name {
+		case bus.ProjectsImported:
+			// An import may touch any project, so start over
+			s.Cache.Purge()
+			return nil
+		case bus.PostPublished, bus.PostUpdated, bus.PostDeleted:
+			tags = append(tags, postsTag)
+		case bus.ProjectUpdated, bus.ProjectDeleted:
+			tags = append(tags, projectsTag)
+		case bus.SeriesUpdated:
+		case bus.ReactionToggled:
+			// Lists carry counts too but are left to expire rather than
+			// dropped on every reaction
+		default:
+			return nil
+		}
+
+		var c bus.Change
+		if err := e.Decode(&c); err != nil {
+			return err
+		}
+		for _, id := range c.PostIDs {
+			tags = append(tags, postTag(id))
+			if e.Name == bus.PostDeleted || e.Name == bus.ReactionToggled {
+				continue
+			}
+			// A save may have just detected a link whose project page
+			// never mentioned the post
+			projects, err := s.DB.ListProjectsByPost(ctx, id)
+			if err != nil {
+				return err
+			}
+			for _, p := range projects {
+				tags = append(tags, projectTag(p.ID))
+			}
+		}
+		for _, id := range c.ProjectIDs {
+			tags = append(tags, projectTag(id))
+		}
+		for _, id := range c.SeriesIDs {
+			tags = append(tags, seriesTag(id))
+		}
+		s.Cache.Invalidate(tags...)
+		return nil
+	})
+}
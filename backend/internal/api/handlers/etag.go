@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/httpcache"
+)
+
+// projectETag and postETag are the strong entity tags sent with write
+// responses and the admin reads, and checked against If-Match. They move with updated_at, so any
+// edit invalidates them. Public reads hash their body instead, as it embeds
+// counts and links that change without an edit.
+func projectETag(p db.Project) string {
+	return httpcache.ETag("project", strconv.Itoa(int(p.ID)), p.UpdatedAt.Time.UTC().Format(time.RFC3339Nano))
+}
+
+func postETag(p db.Post) string {
+	return httpcache.ETag("post", strconv.Itoa(int(p.ID)), p.UpdatedAt.Time.UTC().Format(time.RFC3339Nano))
+}
+
+// staleError is returned from an update transaction when If-Match no longer
+// matches. current is the representation the client should merge against.
+type staleError struct {
+	etag    string
+	current interface{}
+}
+
+func (e *staleError) Error() string { return "precondition failed" }
+
+// requireIfMatch returns the If-Match header, answering 428 when it is missing.
+func requireIfMatch(w http.ResponseWriter, r *http.Request) (string, bool) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		http.Error(w, `{"error":"If-Match header is required"}`, http.StatusPreconditionRequired)
+		return "", false
+	}
+	return ifMatch, true
+}
+
+// writeStaleError answers 412 with the current representation and its ETag;
+// it reports whether err was a staleError.
+func writeStaleError(w http.ResponseWriter, err error) bool {
+	var stale *staleError
+	if !errors.As(err, &stale) {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", stale.etag)
+	w.WriteHeader(http.StatusPreconditionFailed)
+	_ = json.NewEncoder(w).Encode(stale.current)
+	return true
+}
+
+// Default Cache-Control policies for the public content routes. Each can be
+// replaced with the CACHE_CONTROL_* variable named next to its route.
+var (
+	defaultListPolicy = httpcache.Policy{
+		Public:               true,
+		MaxAge:               30 * time.Second,
+		SMaxAge:              time.Minute,
+		StaleWhileRevalidate: 5 * time.Minute,
+	}
+	defaultDetailPolicy = httpcache.Policy{
+		Public:               true,
+		MaxAge:               time.Minute,
+		SMaxAge:              5 * time.Minute,
+		StaleWhileRevalidate: time.Hour,
+	}
+)
+
+// writeCached writes a public JSON response with its validators and
+// Cache-Control policy, or a bare 304 when the client's copy is current. An
+// empty etag is derived from the encoded body; a zero modified sends no
+// Last-Modified.
+func writeCached(w http.ResponseWriter, r *http.Request, policy httpcache.Policy, etag string, modified time.Time, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, `{"error":"Failed to encode response"}`, http.StatusInternalServerError)
+		return
+	}
+	body = append(body, '\n')
+	if etag == "" {
+		etag = httpcache.ETag(string(body))
+	}
+
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set("Cache-Control", policy.String())
+	if !modified.IsZero() {
+		h.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	if httpcache.NotModified(r, etag, modified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// setListModified sets Last-Modified on a list response to its newest
+// updated_at. It is informational only: removing a row does not move it, so
+// lists are revalidated by ETag and If-Modified-Since is not honoured.
+func setListModified(w http.ResponseWriter, times []time.Time) {
+	if newest := latest(times...); !newest.IsZero() {
+		w.Header().Set("Last-Modified", newest.UTC().Format(http.TimeFormat))
+	}
+}
+
+// latest returns the newest of times, or the zero time when there are none.
+func latest(times ...time.Time) time.Time {
+	var newest time.Time
+	for _, t := range times {
+		if t.After(newest) {
+			newest = t
+		}
+	}
+	return newest
+}
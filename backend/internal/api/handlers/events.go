@@ -1,19 +1,32 @@
 package handlers
 
 import (
-	"database/sql"
 	"encoding/json"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/listquery"
 	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
 	"github.com/onnwee/onnwee.github.io/backend/internal/server"
 	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
 )
 
+// eventListSpec is the sort/filter allowlist for GET /events.
+var eventListSpec = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"id":         {Column: "id", Type: listquery.Int, Sortable: true, Filterable: true},
+		"event_name": {Column: "event_name", Type: listquery.Text, Sortable: true, Filterable: true},
+		"session_id": {Column: "session_id", Type: listquery.Text, Filterable: true},
+		"referrer":   {Column: "referrer", Type: listquery.Text, Filterable: true},
+		"user_id":    {Column: "user_id", Type: listquery.Int, Filterable: true},
+		"viewed_at":  {Column: "viewed_at", Type: listquery.Time, Sortable: true, Filterable: true},
+	},
+	DefaultSort:  "-viewed_at",
+	DefaultLimit: 20,
+}
+
 func RegisterEventRoutes(r *mux.Router, s *server.Server) {
 	// POST /events - create an event
 	r.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
@@ -54,44 +67,25 @@ func RegisterEventRoutes(r *mux.Router, s *server.Server) {
 		w.WriteHeader(http.StatusCreated)
 	}).Methods("POST")
 
-	// GET /events - list events with optional filters
+	// GET /events - list events (?sort=&filter[field][op]=&limit=&offset=)
 	r.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
-		query := r.URL.Query()
-
-		// Pagination defaults
-		limit := int32(20) // default
-		if limitStr := query.Get("limit"); limitStr != "" {
-			if limit64, err := strconv.ParseInt(limitStr, 10, 32); err == nil && limit64 > 0 {
-				limit = int32(limit64)
-			}
-		}
+		values := r.URL.Query()
 
-		offset := int32(0) // default
-		if offsetStr := query.Get("offset"); offsetStr != "" {
-			if offset64, err := strconv.ParseInt(offsetStr, 10, 32); err == nil && offset64 >= 0 {
-				offset = int32(offset64)
+		// Legacy ?event_name=&session_id= params map onto equality filters
+		for _, name := range []string{"event_name", "session_id"} {
+			if v := values.Get(name); v != "" {
+				values.Add("filter["+name+"]", v)
 			}
 		}
 
-		// Optional filters
-		eventName := sql.NullString{}
-		if v := query.Get("event_name"); v != "" {
-			eventName = utils.ToNullString(&v)
-		}
-
-		sessionID := sql.NullString{}
-		if v := query.Get("session_id"); v != "" {
-			sessionID = utils.ToNullString(&v)
-		}
-
-		params := db.ListEventsParams{
-			EventName: eventName,
-			SessionID: sessionID,
-			Limit:     limit,
-			Offset:    offset,
+		lq, err := listquery.Parse(values, eventListSpec)
+		if err != nil {
+			writeQueryError(w, err)
+			return
 		}
+		query, args := lq.Build("SELECT "+db.EventColumns+" FROM events", nil, nil)
 
-		events, err := s.DB.ListEvents(r.Context(), params)
+		events, err := s.DB.QueryEvents(r.Context(), query, args...)
 		if err != nil {
 			http.Error(w, `{"error":"Failed to fetch events"}`, http.StatusInternalServerError)
 			return
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/feed"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/respcache"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"go.opentelemetry.io/otel"
+)
+
+// feedLimit caps the number of entries in the RSS and JSON feeds.
+const feedLimit = 20
+
+// apiBase is the path the client reaches the API under (see client/src/utils/api.ts).
+const apiBase = "/api"
+
+// RegisterFeedRoutes registers the RSS/JSON feeds and the sitemap
+func RegisterFeedRoutes(r *mux.Router, s *server.Server) {
+	// GET /feed.xml - RSS 2.0 feed of published posts and project updates
+	r.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("feeds-handler")
+		ctx, span := tracer.Start(r.Context(), "RSSFeed")
+		defer span.End()
+
+		f, err := loadFeed(ctx, s, "/feed.xml")
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list posts"}`, http.StatusInternalServerError)
+			return
+		}
+		out, err := feed.RSS(f)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to render feed"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		_, _ = w.Write(out)
+	}).Methods("GET")
+
+	// GET /feed.json - JSON Feed 1.1 of published posts and project updates
+	r.HandleFunc("/feed.json", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("feeds-handler")
+		ctx, span := tracer.Start(r.Context(), "JSONFeed")
+		defer span.End()
+
+		f, err := loadFeed(ctx, s, "/feed.json")
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list posts"}`, http.StatusInternalServerError)
+			return
+		}
+		out, err := feed.JSON(f)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to render feed"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/feed+json")
+		_, _ = w.Write(out)
+	}).Methods("GET")
+
+	// GET /sitemap.xml - Client routes for every public project and published post
+	r.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("feeds-handler")
+		ctx, span := tracer.Start(r.Context(), "Sitemap")
+		defer span.End()
+
+		start := time.Now()
+		posts, err := s.DB.ListPublishedPosts(ctx)
+		metrics.ObserveDBQueryDuration("list_published_posts", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list posts"}`, http.StatusInternalServerError)
+			return
+		}
+
+		start = time.Now()
+		projects, err := s.DB.ListProjects(ctx)
+		metrics.ObserveDBQueryDuration("list_projects", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list projects"}`, http.StatusInternalServerError)
+			return
+		}
+
+		respcache.Tag(ctx, postsTag, projectsTag)
+		site := feed.SiteURL()
+		urls := []feed.URL{{Loc: site + "/"}, {Loc: site + "/projects"}}
+		for _, p := range projects {
+			if p.Status == projectDraft {
+				continue
+			}
+			urls = append(urls, feed.URL{Loc: feed.ProjectURL(site, p.Slug), LastMod: p.UpdatedAt.Time})
+		}
+		for _, p := range posts {
+			urls = append(urls, feed.URL{Loc: feed.PostURL(site, p.Slug), LastMod: p.UpdatedAt.Time})
+		}
+
+		out, err := feed.Sitemap(urls)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to render sitemap"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		_, _ = w.Write(out)
+	}).Methods("GET")
+}
+
+// loadFeed merges the latest published posts and project updates, newest first.
+func loadFeed(ctx context.Context, s *server.Server, path string) (feed.Feed, error) {
+	start := time.Now()
+	posts, err := s.DB.ListPublishedPosts(ctx)
+	metrics.ObserveDBQueryDuration("list_published_posts", time.Since(start).Seconds())
+	if err != nil {
+		return feed.Feed{}, err
+	}
+	if len(posts) > feedLimit {
+		posts = posts[:feedLimit]
+	}
+
+	start = time.Now()
+	updates, err := s.DB.ListRecentProjectUpdates(ctx, feedLimit)
+	metrics.ObserveDBQueryDuration("list_recent_project_updates", time.Since(start).Seconds())
+	if err != nil {
+		return feed.Feed{}, err
+	}
+	respcache.Tag(ctx, postsTag, projectsTag)
+	return buildFeed(posts, updates, path), nil
+}
+
+func buildFeed(posts []db.Post, updates []db.ListRecentProjectUpdatesRow, path string) feed.Feed {
+	site := feed.SiteURL()
+	f := feed.Feed{
+		Title:       feed.SiteName(),
+		Description: "Posts and project updates from " + feed.SiteName(),
+		SiteURL:     site,
+		FeedURL:     site + apiBase + path,
+	}
+	for _, p := range posts {
+		published := p.CreatedAt.Time
+		if p.PublishedAt.Valid {
+			published = p.PublishedAt.Time
+		}
+		url := feed.PostURL(site, p.Slug)
+		f.Items = append(f.Items, feed.Item{
+			ID:        url,
+			Title:     p.Title,
+			URL:       url,
+			Summary:   p.Summary.String,
+			Tags:      p.Tags,
+			Published: published,
+			Updated:   p.UpdatedAt.Time,
+		})
+	}
+	for _, u := range updates {
+		title := u.ProjectTitle
+		if u.Version.Valid && u.Version.String != "" {
+			title += " " + u.Version.String
+		}
+		url := feed.ProjectURL(site, u.ProjectSlug)
+		f.Items = append(f.Items, feed.Item{
+			ID:        url + "#update-" + strconv.Itoa(int(u.ID)),
+			Title:     title + ": " + u.Title,
+			URL:       url,
+			Summary:   u.Body,
+			Published: u.PublishedAt,
+			Updated:   u.UpdatedAt.Time,
+		})
+	}
+	sort.SliceStable(f.Items, func(i, j int) bool {
+		return f.Items[i].Published.After(f.Items[j].Published)
+	})
+	if len(f.Items) > feedLimit {
+		f.Items = f.Items[:feedLimit]
+	}
+	return f
+}
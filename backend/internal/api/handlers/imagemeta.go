@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/imagemeta"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+)
+
+// refreshProjectImageMeta recomputes the placeholder metadata of a saved
+// project whose image changed. An image that cannot be loaded is stored
+// without metadata and logged; the save itself still succeeds. updated_at is
+// left alone, so the ETag the editor got back from the save stays valid.
+func refreshProjectImageMeta(ctx context.Context, s *server.Server, p db.Project) db.Project {
+	src := p.Image.String
+	if !imagemeta.Stale(p.ImageMeta, src) {
+		return p
+	}
+	meta, err := s.Images.LoadJSON(ctx, src)
+	if err != nil {
+		log.Printf("Failed to compute image metadata for project %d (%s): %v", p.ID, src, err)
+		if len(p.ImageMeta) == 0 {
+			return p
+		}
+	}
+
+	var updated db.Project
+	start := time.Now()
+	err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+		if updated, err = q.SetProjectImageMeta(ctx, db.SetProjectImageMetaParams{ID: p.ID, ImageMeta: meta}); err != nil {
+			return err
+		}
+		return out.Add(ctx, bus.ProjectUpdated, bus.Change{ProjectIDs: []int32{p.ID}})
+	})
+	metrics.ObserveDBQueryDuration("set_project_image_meta", time.Since(start).Seconds())
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Failed to store image metadata for project %d: %v", p.ID, err)
+		}
+		return p
+	}
+	return updated
+}
+
+// refreshPostCoverMeta is refreshProjectImageMeta for a post's cover image.
+func refreshPostCoverMeta(ctx context.Context, s *server.Server, p db.Post) db.Post {
+	if !imagemeta.Stale(p.CoverImageMeta, p.CoverImage) {
+		return p
+	}
+	meta, err := s.Images.LoadJSON(ctx, p.CoverImage)
+	if err != nil {
+		log.Printf("Failed to compute cover image metadata for post %d (%s): %v", p.ID, p.CoverImage, err)
+		if len(p.CoverImageMeta) == 0 {
+			return p
+		}
+	}
+
+	var updated db.Post
+	start := time.Now()
+	err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+		if updated, err = q.SetPostCoverImageMeta(ctx, db.SetPostCoverImageMetaParams{ID: p.ID, CoverImageMeta: meta}); err != nil {
+			return err
+		}
+		return out.Add(ctx, bus.PostUpdated, bus.Change{PostIDs: []int32{p.ID}})
+	})
+	metrics.ObserveDBQueryDuration("set_post_cover_image_meta", time.Since(start).Seconds())
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Failed to store cover image metadata for post %d: %v", p.ID, err)
+		}
+		return p
+	}
+	return updated
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/listquery"
+)
+
+// writeQueryError reports an invalid list query as a 400 naming the offending parameter.
+func writeQueryError(w http.ResponseWriter, err error) {
+	var qe *listquery.Error
+	if !errors.As(err, &qe) {
+		http.Error(w, `{"error":"Invalid query"}`, http.StatusBadRequest)
+		return
+	}
+	body, _ := json.Marshal(map[string]string{
+		"error": qe.Message,
+		"param": qe.Param,
+	})
+	http.Error(w, string(body), http.StatusBadRequest)
+}
+
+// parseShape reads ?fields= and ?include=author for content reads. A requested
+// author is always kept, even when the fieldset does not name it.
+func parseShape(r *http.Request, allowed []string) ([]string, []string, error) {
+	include, err := listquery.ParseInclude(r.URL.Query(), []string{"author"})
+	if err != nil {
+		return nil, nil, err
+	}
+	fields, err := listquery.ParseFields(r.URL.Query(), allowed)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fields != nil && listquery.Includes(include, "author") {
+		fields = append(fields, "author")
+	}
+	return fields, include, nil
+}
@@ -9,10 +9,24 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/listquery"
 	"github.com/onnwee/onnwee.github.io/backend/internal/server"
 	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
 )
 
+// logListSpec is the sort/filter allowlist for GET /logs.
+var logListSpec = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"id":         {Column: "id", Type: listquery.Int, Sortable: true, Filterable: true},
+		"level":      {Column: "level", Type: listquery.Text, Sortable: true, Filterable: true},
+		"message":    {Column: "message", Type: listquery.Text, Filterable: true},
+		"ip_address": {Column: "ip_address", Type: listquery.Text, Filterable: true},
+		"created_at": {Column: "created_at", Type: listquery.Time, Sortable: true, Filterable: true},
+	},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 10,
+}
+
 func RegisterLogRoutes(r *mux.Router, s *server.Server) {
 	// POST /logs - Create a log
 	r.HandleFunc("/logs", func(w http.ResponseWriter, r *http.Request) {
@@ -50,29 +64,16 @@ func RegisterLogRoutes(r *mux.Router, s *server.Server) {
 		_ = json.NewEncoder(w).Encode(log)
 	}).Methods("POST")
 
-	// GET /logs - List logs with pagination
+	// GET /logs - List logs (?sort=&filter[field][op]=&limit=&offset=)
 	r.HandleFunc("/logs", func(w http.ResponseWriter, r *http.Request) {
-		query := r.URL.Query()
-		limit := int32(10) // default
-		if limitStr := query.Get("limit"); limitStr != "" {
-			if limit64, err := strconv.ParseInt(limitStr, 10, 32); err == nil && limit64 > 0 {
-				limit = int32(limit64)
-			}
-		}
-
-		offset := int32(0) // default
-		if offsetStr := query.Get("offset"); offsetStr != "" {
-			if offset64, err := strconv.ParseInt(offsetStr, 10, 32); err == nil && offset64 >= 0 {
-				offset = int32(offset64)
-			}
-		}
-
-		params := db.ListLogsParams{
-			Limit:  limit,
-			Offset: offset,
+		lq, err := listquery.Parse(r.URL.Query(), logListSpec)
+		if err != nil {
+			writeQueryError(w, err)
+			return
 		}
+		query, args := lq.Build("SELECT "+db.LogColumns+" FROM logs", nil, nil)
 
-		logs, err := s.DB.ListLogs(r.Context(), params)
+		logs, err := s.DB.QueryLogs(r.Context(), query, args...)
 		if err != nil {
 			http.Error(w, `{"error":"Failed to fetch logs"}`, http.StatusInternalServerError)
 			return
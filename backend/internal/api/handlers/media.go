@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/media"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+)
+
+// immutableCacheControl is sent with media objects. Keys are derived from the
+// upload's hash, so an object never changes once written.
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
+// multipartOverhead is allowed on top of the upload limit for the form's
+// boundaries and other fields.
+const multipartOverhead = 64 << 10
+
+type mediaVariant struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	SizeBytes   int64  `json:"size_bytes"`
+}
+
+type mediaResponse struct {
+	ID          int32          `json:"id"`
+	URL         string         `json:"url"`
+	Filename    string         `json:"filename"`
+	ContentType string         `json:"content_type"`
+	SizeBytes   int64          `json:"size_bytes"`
+	Width       int32          `json:"width"`
+	Height      int32          `json:"height"`
+	AltText     string         `json:"alt_text"`
+	Variants    []mediaVariant `json:"variants"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+}
+
+func toMediaResponse(s *server.Server, m db.Media) mediaResponse {
+	var variants []media.Variant
+	_ = json.Unmarshal(m.Variants, &variants)
+	out := mediaResponse{
+		ID:          m.ID,
+		URL:         s.Media.URL(m.Key),
+		Filename:    m.Filename,
+		ContentType: m.ContentType,
+		SizeBytes:   m.SizeBytes,
+		Width:       m.Width,
+		Height:      m.Height,
+		AltText:     m.AltText,
+		Variants:    make([]mediaVariant, 0, len(variants)),
+		CreatedAt:   toTimeString(m.CreatedAt),
+		UpdatedAt:   toTimeString(m.UpdatedAt),
+	}
+	for _, v := range variants {
+		out.Variants = append(out.Variants, mediaVariant{
+			URL:         s.Media.URL(v.Key),
+			ContentType: v.ContentType,
+			Width:       v.Width,
+			Height:      v.Height,
+			SizeBytes:   v.SizeBytes,
+		})
+	}
+	return out
+}
+
+// RegisterMediaRoutes registers the route that serves stored media objects
+func RegisterMediaRoutes(r *mux.Router, s *server.Server) {
+	// GET /media/{key} - An original or variant, e.g. /media/3f2a9c0d1e4b5a67/w640.webp
+	r.HandleFunc("/media/{key:.+}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("media-handler")
+		ctx, span := tracer.Start(r.Context(), "ServeMedia")
+		defer span.End()
+
+		key := mux.Vars(r)["key"]
+		obj, err := s.Media.Storage.Open(ctx, key)
+		if errors.Is(err, media.ErrNotFound) {
+			http.Error(w, `{"error":"Media not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to read media"}`, http.StatusInternalServerError)
+			return
+		}
+		defer obj.Close()
+
+		w.Header().Set("Content-Type", media.ContentType(key))
+		w.Header().Set("Cache-Control", immutableCacheControl)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if rs, ok := obj.(io.ReadSeeker); ok {
+			http.ServeContent(w, r, path.Base(key), time.Time{}, rs)
+			return
+		}
+		_, _ = io.Copy(w, obj)
+	}).Methods("GET", "HEAD")
+}
+
+// RegisterAdminMediaRoutes registers the media library routes
+func RegisterAdminMediaRoutes(r *mux.Router, s *server.Server) {
+	// GET /admin/media - Every asset, newest first
+	r.HandleFunc("/media", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("media-handler")
+		ctx, span := tracer.Start(r.Context(), "ListMedia")
+		defer span.End()
+
+		start := time.Now()
+		rows, err := s.DB.ListMedia(ctx)
+		metrics.ObserveDBQueryDuration("list_media", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list media"}`, http.StatusInternalServerError)
+			return
+		}
+
+		out := make([]mediaResponse, 0, len(rows))
+		for _, m := range rows {
+			out = append(out, toMediaResponse(s, m))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(out)
+	}).Methods("GET")
+
+	// POST /admin/media - Upload an image (multipart/form-data: file, alt_text)
+	r.HandleFunc("/media", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("media-handler")
+		ctx, span := tracer.Start(r.Context(), "UploadMedia")
+		defer span.End()
+
+		r.Body = http.MaxBytesReader(w, r.Body, s.Media.MaxBytes+multipartOverhead)
+		file, header, err := r.FormFile("file")
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, `{"error":"File is too large"}`, http.StatusRequestEntityTooLarge)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Expected a multipart form with a file field"}`, http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, s.Media.MaxBytes+1))
+		if err != nil {
+			http.Error(w, `{"error":"Failed to read upload"}`, http.StatusBadRequest)
+			return
+		}
+		if int64(len(data)) > s.Media.MaxBytes {
+			http.Error(w, `{"error":"File is too large"}`, http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		asset, err := media.Process(data)
+		if errors.Is(err, media.ErrUnsupportedType) {
+			http.Error(w, `{"error":"Unsupported image type: use JPEG, PNG or WebP"}`, http.StatusUnsupportedMediaType)
+			return
+		} else if errors.Is(err, media.ErrInvalidImage) || errors.Is(err, media.ErrTooManyPixels) {
+			writeMediaError(w, err)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to process image"}`, http.StatusInternalServerError)
+			return
+		}
+
+		// The same file uploaded twice maps to the same keys
+		start := time.Now()
+		existing, err := s.DB.GetMediaByKey(ctx, asset.Original.Key)
+		metrics.ObserveDBQueryDuration("get_media_by_key", time.Since(start).Seconds())
+		if err == nil {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(toMediaResponse(s, existing))
+			return
+		} else if err != sql.ErrNoRows {
+			http.Error(w, `{"error":"Failed to check media"}`, http.StatusInternalServerError)
+			return
+		}
+
+		if err := storeAsset(ctx, s, asset); err != nil {
+			log.Printf("Failed to store media %s: %v", asset.Original.Key, err)
+			http.Error(w, `{"error":"Failed to store media"}`, http.StatusInternalServerError)
+			return
+		}
+
+		variants := make([]media.Variant, 0, len(asset.Variants))
+		for _, v := range asset.Variants {
+			variants = append(variants, v.Variant)
+		}
+		variantsJSON, _ := json.Marshal(variants)
+
+		start = time.Now()
+		created, err := s.DB.CreateMedia(ctx, db.CreateMediaParams{
+			Key:         asset.Original.Key,
+			Filename:    path.Base(header.Filename),
+			ContentType: asset.Original.ContentType,
+			SizeBytes:   asset.Original.SizeBytes,
+			Width:       int32(asset.Original.Width),
+			Height:      int32(asset.Original.Height),
+			AltText:     r.FormValue("alt_text"),
+			Variants:    variantsJSON,
+		})
+		metrics.ObserveDBQueryDuration("create_media", time.Since(start).Seconds())
+		if isUniqueViolation(err) {
+			// A concurrent upload of the same file won; its objects are ours
+			created, err = s.DB.GetMediaByKey(ctx, asset.Original.Key)
+		}
+		if err != nil {
+			http.Error(w, `{"error":"Failed to create media"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(toMediaResponse(s, created))
+	}).Methods("POST")
+
+	// GET /admin/media/{id} - A single asset
+	r.HandleFunc("/media/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("media-handler")
+		ctx, span := tracer.Start(r.Context(), "GetMedia")
+		defer span.End()
+
+		id, ok := parseMediaID(w, r)
+		if !ok {
+			return
+		}
+
+		start := time.Now()
+		m, err := s.DB.GetMedia(ctx, id)
+		metrics.ObserveDBQueryDuration("get_media", time.Since(start).Seconds())
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Media not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to fetch media"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(toMediaResponse(s, m))
+	}).Methods("GET")
+
+	// PUT /admin/media/{id} - Set the alt text {"alt_text": "…"}
+	r.HandleFunc("/media/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("media-handler")
+		ctx, span := tracer.Start(r.Context(), "UpdateMedia")
+		defer span.End()
+
+		id, ok := parseMediaID(w, r)
+		if !ok {
+			return
+		}
+		var in struct {
+			AltText string `json:"alt_text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+
+		start := time.Now()
+		m, err := s.DB.UpdateMediaAltText(ctx, db.UpdateMediaAltTextParams{ID: id, AltText: in.AltText})
+		metrics.ObserveDBQueryDuration("update_media_alt_text", time.Since(start).Seconds())
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Media not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to update media"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(toMediaResponse(s, m))
+	}).Methods("PUT")
+
+	// DELETE /admin/media/{id} - Delete an asset and its stored objects
+	r.HandleFunc("/media/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("media-handler")
+		ctx, span := tracer.Start(r.Context(), "DeleteMedia")
+		defer span.End()
+
+		id, ok := parseMediaID(w, r)
+		if !ok {
+			return
+		}
+
+		start := time.Now()
+		m, err := s.DB.DeleteMedia(ctx, id)
+		metrics.ObserveDBQueryDuration("delete_media", time.Since(start).Seconds())
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Media not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to delete media"}`, http.StatusInternalServerError)
+			return
+		}
+
+		// The row is gone, so leftover objects are only wasted space
+		var variants []media.Variant
+		_ = json.Unmarshal(m.Variants, &variants)
+		keys := []string{m.Key}
+		for _, v := range variants {
+			keys = append(keys, v.Key)
+		}
+		for _, key := range keys {
+			if err := s.Media.Storage.Delete(ctx, key); err != nil {
+				log.Printf("Failed to delete media object %s: %v", key, err)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+}
+
+// storeAsset writes every object of an asset, removing the ones already
+// written if one fails.
+func storeAsset(ctx context.Context, s *server.Server, asset *media.Asset) error {
+	var written []string
+	for _, obj := range asset.Objects() {
+		if err := s.Media.Storage.Put(ctx, obj.Key, bytes.NewReader(obj.Data), obj.ContentType); err != nil {
+			for _, key := range written {
+				_ = s.Media.Storage.Delete(ctx, key)
+			}
+			return err
+		}
+		written = append(written, obj.Key)
+	}
+	return nil
+}
+
+func parseMediaID(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid media ID"}`, http.StatusBadRequest)
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func writeMediaError(w http.ResponseWriter, err error) {
+	body, _ := json.Marshal(map[string]string{"error": err.Error()})
+	http.Error(w, string(body), http.StatusBadRequest)
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/feed"
+	"github.com/onnwee/onnwee.github.io/backend/internal/httpcache"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/ogcard"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+)
+
+// Card cache kinds, named after the routes.
+const (
+	projectCards = "projects"
+	postCards    = "posts"
+)
+
+// postCardColor is the accent of post cards; posts have no color of their own.
+const postCardColor = "cyan"
+
+// defaultCardPolicy lets unfurlers and CDNs keep a card for an hour and
+// serve it stale for a day while they refetch it.
+var defaultCardPolicy = httpcache.Policy{
+	Public:               true,
+	MaxAge:               time.Hour,
+	StaleWhileRevalidate: 24 * time.Hour,
+}
+
+// RegisterOGCardRoutes registers the Open Graph image routes
+func RegisterOGCardRoutes(r *mux.Router, s *server.Server) {
+	policy := httpcache.PolicyFromEnv("CACHE_CONTROL_OG", defaultCardPolicy)
+
+	// GET /og/projects/{slug}.png - Preview card of a published or archived project
+	r.HandleFunc("/og/projects/{slug}.png", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("ogcards-handler")
+		ctx, span := tracer.Start(r.Context(), "ProjectCard")
+		defer span.End()
+
+		slug := mux.Vars(r)["slug"]
+		start := time.Now()
+		project, err := s.DB.GetProjectBySlug(ctx, slug)
+		metrics.ObserveDBQueryDuration("get_project_by_slug", time.Since(start).Seconds())
+
+		if err == sql.ErrNoRows {
+			redirectCard(w, r, s, projectSlugs, slug, `{"error":"Project not found"}`)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to fetch project"}`, http.StatusInternalServerError)
+			return
+		}
+		if project.Status == projectDraft {
+			http.Error(w, `{"error":"Project not found"}`, http.StatusNotFound)
+			return
+		}
+
+		writeCard(w, r, s, policy, projectCards, project.ID, project.UpdatedAt.Time, ogcard.Card{
+			Path:  "~/projects/" + project.Slug,
+			Title: project.Title,
+			Emoji: project.Emoji.String,
+			Tags:  project.Tags,
+			Color: project.Color.String,
+			Site:  siteHost(),
+		})
+	}).Methods("GET", "HEAD")
+
+	// GET /og/posts/{slug}.png - Preview card of a published post
+	r.HandleFunc("/og/posts/{slug}.png", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("ogcards-handler")
+		ctx, span := tracer.Start(r.Context(), "PostCard")
+		defer span.End()
+
+		slug := mux.Vars(r)["slug"]
+		start := time.Now()
+		post, err := s.DB.GetPostBySlug(ctx, slug)
+		metrics.ObserveDBQueryDuration("get_post_by_slug", time.Since(start).Seconds())
+
+		if err == sql.ErrNoRows {
+			redirectCard(w, r, s, postSlugs, slug, `{"error":"Post not found"}`)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to get post"}`, http.StatusInternalServerError)
+			return
+		}
+		if post.IsDraft.Bool {
+			http.Error(w, `{"error":"Post not found"}`, http.StatusNotFound)
+			return
+		}
+
+		writeCard(w, r, s, policy, postCards, post.ID, post.UpdatedAt.Time, ogcard.Card{
+			Path:  "~/blog/" + post.Slug,
+			Title: post.Title,
+			Tags:  post.Tags,
+			Color: postCardColor,
+			Site:  siteHost(),
+		})
+	}).Methods("GET", "HEAD")
+}
+
+// redirectCard answers a card request for a former slug with a 301 to the
+// card of the current one, or a 404.
+func redirectCard(w http.ResponseWriter, r *http.Request, s *server.Server, st slugStore, slug, notFound string) {
+	start := time.Now()
+	canonical, err := st.resolve(r.Context(), s.DB, slug)
+	metrics.ObserveDBQueryDuration("resolve_slug_redirect", time.Since(start).Seconds())
+	if err == sql.ErrNoRows {
+		http.Error(w, notFound, http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, `{"error":"Failed to resolve slug"}`, http.StatusInternalServerError)
+		return
+	}
+	// Relative, so it resolves correctly behind any path prefix
+	w.Header().Set("Location", url.PathEscape(canonical)+".png")
+	w.WriteHeader(http.StatusMovedPermanently)
+}
+
+// writeCard serves the card of a record, rendering it on a cache miss.
+func writeCard(w http.ResponseWriter, r *http.Request, s *server.Server, policy httpcache.Policy, kind string, id int32, modified time.Time, card ogcard.Card) {
+	data, fingerprint, err := s.Cards.PNG(kind, id, card)
+	if err != nil {
+		http.Error(w, `{"error":"Failed to render card"}`, http.StatusInternalServerError)
+		return
+	}
+
+	etag := httpcache.ETag("card", fingerprint)
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set("Cache-Control", policy.String())
+	h.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	if httpcache.NotModified(r, etag, modified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", "image/png")
+	h.Set("X-Content-Type-Options", "nosniff")
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+// siteHost is the host shown in a card's window title.
+func siteHost() string {
+	site := feed.SiteURL()
+	if u, err := url.Parse(site); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(site, "https://"), "http://")
+}
+
+// SubscribeCardInvalidation deletes the cached cards of records changed on
+// this or any other instance. Cards are also keyed by their content, so a
+// missed event only leaves an unused file behind.
+func SubscribeCardInvalidation(s *server.Server) {
+	s.Bus.Subscribe(bus.All, func(ctx context.Context, e bus.Event) error {
+		switch e.Name {
+		case bus.ProjectsImported:
+			return s.Cards.Cache.Purge(projectCards)
+		case bus.PostPublished, bus.PostUpdated, bus.PostDeleted, bus.ProjectUpdated, bus.ProjectDeleted:
+		default:
+			return nil
+		}
+
+		var c bus.Change
+		if err := e.Decode(&c); err != nil {
+			return err
+		}
+		for _, id := range c.ProjectIDs {
+			if err := s.Cards.Cache.Invalidate(projectCards, id); err != nil {
+				log.Printf("Failed to drop project card %d: %v", id, err)
+			}
+		}
+		for _, id := range c.PostIDs {
+			if err := s.Cards.Cache.Invalidate(postCards, id); err != nil {
+				log.Printf("Failed to drop post card %d: %v", id, err)
+			}
+		}
+		return nil
+	})
+}
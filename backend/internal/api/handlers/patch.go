@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/mergepatch"
+)
+
+// maxPatchBytes bounds the size of a merge patch body.
+const maxPatchBytes = 1 << 20
+
+// patchError is a merge patch whose merged result failed validation.
+type patchError string
+
+func (e patchError) Error() string { return string(e) }
+
+// readMergePatch reads a JSON Merge Patch body. application/json is accepted
+// as well, since most clients send it by default.
+func readMergePatch(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mt != mergepatch.ContentType && mt != "application/json") {
+			http.Error(w, `{"error":"Content-Type must be application/merge-patch+json"}`, http.StatusUnsupportedMediaType)
+			return nil, false
+		}
+	}
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchBytes))
+	if err != nil || !json.Valid(patch) {
+		http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+		return nil, false
+	}
+	return patch, true
+}
+
+// applyMergePatch merges patch into the JSON form of current and decodes the
+// result into out, which should be a pointer to a zero value so that removed
+// members stay unset.
+func applyMergePatch(current interface{}, patch []byte, out interface{}) error {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	merged, err := mergepatch.Apply(doc, patch)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(merged, out); err != nil {
+		return patchError("patch sets a field to a value of the wrong type")
+	}
+	return nil
+}
+
+// writePatchError writes a 400 for validation failures; it reports whether
+// err was one.
+func writePatchError(w http.ResponseWriter, err error) bool {
+	var pe patchError
+	if !errors.As(err, &pe) {
+		return false
+	}
+	body, _ := json.Marshal(map[string]string{"error": string(pe)})
+	http.Error(w, string(body), http.StatusBadRequest)
+	return true
+}
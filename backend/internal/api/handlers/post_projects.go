@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/postlinks"
+	"github.com/onnwee/onnwee.github.io/backend/internal/respcache"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+)
+
+// linkedProject is a project referenced by a post.
+type linkedProject struct {
+	Slug    string  `json:"slug"`
+	Title   string  `json:"title"`
+	Summary *string `json:"summary"`
+	Emoji   *string `json:"emoji"`
+	Image   *string `json:"image"`
+}
+
+// linkedPost is a published post that writes about a project.
+type linkedPost struct {
+	Slug        string  `json:"slug"`
+	Title       string  `json:"title"`
+	Summary     *string `json:"summary"`
+	PublishedAt string  `json:"published_at"`
+}
+
+// loadLinkedProjects returns the projects a post references and the newest
+// of their updated_at.
+func loadLinkedProjects(ctx context.Context, s *server.Server, postID int32) ([]linkedProject, time.Time, error) {
+	start := time.Now()
+	projects, err := s.DB.ListProjectsByPost(ctx, postID)
+	metrics.ObserveDBQueryDuration("list_projects_by_post", time.Since(start).Seconds())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var modified time.Time
+	out := make([]linkedProject, 0, len(projects))
+	for _, p := range projects {
+		respcache.Tag(ctx, projectTag(p.ID))
+		modified = latest(modified, p.UpdatedAt.Time)
+		out = append(out, linkedProject{
+			Slug:    p.Slug,
+			Title:   p.Title,
+			Summary: toPtr(p.Summary),
+			Emoji:   toPtr(p.Emoji),
+			Image:   toPtr(p.Image),
+		})
+	}
+	return out, modified, nil
+}
+
+// loadLinkedPosts returns the published posts linked to a project, newest
+// first, and the newest of their updated_at.
+func loadLinkedPosts(ctx context.Context, s *server.Server, projectID int32) ([]linkedPost, time.Time, error) {
+	start := time.Now()
+	posts, err := s.DB.ListPublishedPostsByProject(ctx, projectID)
+	metrics.ObserveDBQueryDuration("list_posts_by_project", time.Since(start).Seconds())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var modified time.Time
+	out := make([]linkedPost, 0, len(posts))
+	for _, p := range posts {
+		respcache.Tag(ctx, postTag(p.ID))
+		modified = latest(modified, p.UpdatedAt.Time)
+		out = append(out, linkedPost{
+			Slug:        p.Slug,
+			Title:       p.Title,
+			Summary:     toPtr(p.Summary),
+			PublishedAt: toTimeString(p.PublishedAt),
+		})
+	}
+	return out, modified, nil
+}
+
+// RegisterAdminPostProjectRoutes registers the routes that attach projects to
+// posts by hand. Links found in post content are kept in sync on save.
+func RegisterAdminPostProjectRoutes(r *mux.Router, s *server.Server) {
+	// PUT /admin/posts/{id}/projects/{projectID} - Attach a project to a post
+	r.HandleFunc("/posts/{id}/projects/{projectID}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("post-projects-handler")
+		ctx, span := tracer.Start(r.Context(), "AttachPostProject")
+		defer span.End()
+
+		arg, ok := parsePostProjectIDs(w, r)
+		if !ok {
+			return
+		}
+
+		start := time.Now()
+		err := s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			err := q.AttachPostProject(ctx, db.AttachPostProjectParams{
+				PostID:    arg.PostID,
+				ProjectID: arg.ProjectID,
+				Source:    postlinks.SourceManual,
+			})
+			if err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.PostUpdated, bus.Change{PostIDs: []int32{arg.PostID}, ProjectIDs: []int32{arg.ProjectID}})
+		})
+		metrics.ObserveDBQueryDuration("attach_post_project", time.Since(start).Seconds())
+
+		if isForeignKeyViolation(err) {
+			http.Error(w, `{"error":"Post or project not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to attach project"}`, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("PUT")
+
+	// DELETE /admin/posts/{id}/projects/{projectID} - Detach a project
+	r.HandleFunc("/posts/{id}/projects/{projectID}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("post-projects-handler")
+		ctx, span := tracer.Start(r.Context(), "DetachPostProject")
+		defer span.End()
+
+		arg, ok := parsePostProjectIDs(w, r)
+		if !ok {
+			return
+		}
+
+		start := time.Now()
+		err := s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			n, err := q.DetachPostProject(ctx, arg)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return sql.ErrNoRows
+			}
+			return out.Add(ctx, bus.PostUpdated, bus.Change{PostIDs: []int32{arg.PostID}, ProjectIDs: []int32{arg.ProjectID}})
+		})
+		metrics.ObserveDBQueryDuration("detach_post_project", time.Since(start).Seconds())
+
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Link not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to detach project"}`, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+}
+
+func parsePostProjectIDs(w http.ResponseWriter, r *http.Request) (db.DetachPostProjectParams, bool) {
+	vars := mux.Vars(r)
+	postID, err := strconv.ParseInt(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid post ID"}`, http.StatusBadRequest)
+		return db.DetachPostProjectParams{}, false
+	}
+	projectID, err := strconv.ParseInt(vars["projectID"], 10, 32)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid project ID"}`, http.StatusBadRequest)
+		return db.DetachPostProjectParams{}, false
+	}
+	return db.DetachPostProjectParams{PostID: int32(postID), ProjectID: int32(projectID)}, true
+}
@@ -1,52 +1,124 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
 	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/feed"
+	"github.com/onnwee/onnwee.github.io/backend/internal/httpcache"
+	"github.com/onnwee/onnwee.github.io/backend/internal/listquery"
 	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/postlinks"
+	"github.com/onnwee/onnwee.github.io/backend/internal/respcache"
 	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
+	"github.com/onnwee/onnwee.github.io/backend/pkg/middleware"
 	"go.opentelemetry.io/otel"
 )
 
+// postListSpec is the sort/filter allowlist for GET /posts.
+var postListSpec = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"id":           {Column: "id", Type: listquery.Int, Sortable: true, Filterable: true},
+		"title":        {Column: "title", Type: listquery.Text, Sortable: true, Filterable: true},
+		"slug":         {Column: "slug", Type: listquery.Text, Sortable: true, Filterable: true},
+		"tags":         {Column: "tags", Type: listquery.TextArray, Filterable: true},
+		"user_id":      {Column: "user_id", Type: listquery.Int, Filterable: true},
+		"created_at":   {Column: "created_at", Type: listquery.Time, Sortable: true, Filterable: true},
+		"updated_at":   {Column: "updated_at", Type: listquery.Time, Sortable: true, Filterable: true},
+		"published_at": {Column: "published_at", Type: listquery.Time, Sortable: true, Filterable: true},
+	},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 10,
+}
+
+// postResponse is a post as returned by the public read endpoints.
+type postResponse struct {
+	db.Post
+	Author   *publicUser     `json:"author,omitempty"`
+	Series   *seriesContext  `json:"series,omitempty"`
+	Projects []linkedProject `json:"projects,omitempty"`
+}
+
+// postFields is the ?fields= allowlist for post responses.
+var postFields = listquery.JSONFields(postResponse{})
+
+// postLazyColumns are the heavy columns a list leaves unread when ?fields=
+// does not ask for them.
+var postLazyColumns = map[string]string{"content": "''"}
+
 func RegisterPostRoutes(r *mux.Router, s *server.Server) {
-	// GET /posts - List published posts with pagination
+	listPolicy := httpcache.PolicyFromEnv("CACHE_CONTROL_POSTS", defaultListPolicy)
+	detailPolicy := httpcache.PolicyFromEnv("CACHE_CONTROL_POST", defaultDetailPolicy)
+
+	// GET /posts - List published posts (?sort=&filter[field][op]=&limit=&offset=)
 	r.HandleFunc("/posts", func(w http.ResponseWriter, r *http.Request) {
 		tracer := otel.Tracer("posts-handler")
 		ctx, span := tracer.Start(r.Context(), "ListPosts")
 		defer span.End()
 
-		query := r.URL.Query()
-		limit := int32(10) // default
-		if limitStr := query.Get("limit"); limitStr != "" {
-			if limit64, err := strconv.ParseInt(limitStr, 10, 32); err == nil && limit64 > 0 {
-				limit = int32(limit64)
-			}
+		lq, err := listquery.Parse(r.URL.Query(), postListSpec)
+		if err != nil {
+			writeQueryError(w, err)
+			return
 		}
-
-		offset := int32(0) // default
-		if offsetStr := query.Get("offset"); offsetStr != "" {
-			if offset64, err := strconv.ParseInt(offsetStr, 10, 32); err == nil && offset64 >= 0 {
-				offset = int32(offset64)
-			}
+		fields, include, err := parseShape(r, postFields)
+		if err != nil {
+			writeQueryError(w, err)
+			return
 		}
-		params := db.ListPostsParams{Limit: limit, Offset: offset}
+		query, args := lq.Build("SELECT "+listquery.Columns(db.PostColumns, fields, postLazyColumns)+" FROM posts", []string{"is_draft = FALSE"}, nil)
 
 		start := time.Now()
-		posts, err := s.DB.ListPosts(ctx, params)
+		posts, err := s.DB.QueryPosts(ctx, query, args...)
 		metrics.ObserveDBQueryDuration("list_posts", time.Since(start).Seconds())
 
 		if err != nil {
 			http.Error(w, `{"error":"Failed to list posts"}`, http.StatusInternalServerError)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(posts)
+
+		var authors map[int32]*publicUser
+		if listquery.Includes(include, "author") {
+			userIDs := make([]sql.NullInt32, len(posts))
+			for i, p := range posts {
+				userIDs[i] = p.UserID
+			}
+			if authors, err = loadAuthors(ctx, s, userIDs); err != nil {
+				http.Error(w, `{"error":"Failed to fetch authors"}`, http.StatusInternalServerError)
+				return
+			}
+		}
+
+		resp := make([]postResponse, 0, len(posts))
+		for _, p := range posts {
+			resp = append(resp, postResponse{Post: p, Author: authorOf(authors, p.UserID)})
+		}
+
+		out, err := listquery.Select(resp, fields)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to encode posts"}`, http.StatusInternalServerError)
+			return
+		}
+		// Lists are capped at the spec's limit; say where the rest is
+		if link := lq.NextLink(r.URL.Query(), len(posts)); link != "" {
+			w.Header().Set("Link", link)
+		}
+		modified := make([]time.Time, len(posts))
+		for i, p := range posts {
+			modified[i] = p.UpdatedAt.Time
+		}
+		setListModified(w, modified)
+		respcache.Tag(ctx, postsTag)
+		writeCached(w, r, listPolicy, "", time.Time{}, out)
 	}).Methods("GET")
 
 	// GET /posts/{slug} - Get post by slug
@@ -57,19 +129,60 @@ func RegisterPostRoutes(r *mux.Router, s *server.Server) {
 
 		slug := mux.Vars(r)["slug"]
 
+		fields, include, err := parseShape(r, postFields)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+
 		start := time.Now()
 		post, err := s.DB.GetPostBySlug(ctx, slug)
 		metrics.ObserveDBQueryDuration("get_post_by_slug", time.Since(start).Seconds())
 
 		if err == sql.ErrNoRows {
-			http.Error(w, `{"error":"Post not found"}`, http.StatusNotFound)
+			// Fall back to the slug history before giving up
+			if moved, rerr := postSlugs.redirect(w, r, s.DB, slug); rerr != nil {
+				http.Error(w, `{"error":"Failed to get post"}`, http.StatusInternalServerError)
+			} else if !moved {
+				http.Error(w, `{"error":"Post not found"}`, http.StatusNotFound)
+			}
 			return
 		} else if err != nil {
 			http.Error(w, `{"error":"Failed to get post"}`, http.StatusInternalServerError)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(post)
+
+		resp := postResponse{Post: post}
+		modified := post.UpdatedAt.Time
+		if listquery.Includes(include, "author") {
+			authors, err := loadAuthors(ctx, s, []sql.NullInt32{post.UserID})
+			if err != nil {
+				http.Error(w, `{"error":"Failed to fetch author"}`, http.StatusInternalServerError)
+				return
+			}
+			if resp.Author = authorOf(authors, post.UserID); resp.Author != nil {
+				modified = latest(modified, resp.Author.updatedAt)
+			}
+		}
+		var seriesModified, projectsModified time.Time
+		if resp.Series, seriesModified, err = loadSeriesContext(ctx, s, post); err != nil {
+			http.Error(w, `{"error":"Failed to fetch series"}`, http.StatusInternalServerError)
+			return
+		}
+		if resp.Projects, projectsModified, err = loadLinkedProjects(ctx, s, post.ID); err != nil {
+			http.Error(w, `{"error":"Failed to fetch projects"}`, http.StatusInternalServerError)
+			return
+		}
+		modified = latest(modified, seriesModified, projectsModified)
+
+		out, err := listquery.Select(resp, fields)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to encode post"}`, http.StatusInternalServerError)
+			return
+		}
+		respcache.Tag(ctx, postTag(post.ID))
+		// Hashed from the body: counts, links and ?fields change it, not just edits
+		writeCached(w, r, detailPolicy, "", modified, out)
 	}).Methods("GET")
 
 	// POST /posts - Create a new post
@@ -84,21 +197,39 @@ func RegisterPostRoutes(r *mux.Router, s *server.Server) {
 			return
 		}
 
+		var post db.Post
 		start := time.Now()
-		post, err := s.DB.CreatePost(ctx, input)
+		err := s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			slug, err := postSlugs.forCreate(ctx, q, input.Slug, input.Title)
+			if err != nil {
+				return err
+			}
+			input.Slug = slug
+			if post, err = q.CreatePost(ctx, input); err != nil {
+				return err
+			}
+			if err := postlinks.Sync(ctx, q, post.ID, post.Content, feed.SiteURL()); err != nil {
+				return err
+			}
+			return addPostEvent(ctx, out, post, false)
+		})
 		metrics.ObserveDBQueryDuration("create_post", time.Since(start).Seconds())
 
-		if err != nil {
+		if writeSlugError(w, err) {
+			return
+		} else if err != nil {
 			http.Error(w, `{"error":"Failed to create post"}`, http.StatusInternalServerError)
 			return
 		}
+		post = refreshPostCoverMeta(ctx, s, post)
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", postETag(post))
 		w.WriteHeader(http.StatusCreated)
 		_ = json.NewEncoder(w).Encode(post)
 	}).Methods("POST")
 
-	// PUT /posts/{id} - Update an existing post
-	r.HandleFunc("/posts/{id}", func(w http.ResponseWriter, r *http.Request) {
+	// PUT /posts/{id} - Update an existing post, signed in only
+	r.Handle("/posts/{id}", middleware.RequireAuth(s.DB)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		tracer := otel.Tracer("posts-handler")
 		ctx, span := tracer.Start(r.Context(), "UpdatePost")
 		defer span.End()
@@ -110,6 +241,11 @@ func RegisterPostRoutes(r *mux.Router, s *server.Server) {
 			return
 		}
 		id := int32(id64)
+
+		ifMatch, ok := requireIfMatch(w, r)
+		if !ok {
+			return
+		}
 		var input db.UpdatePostParams
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
@@ -117,20 +253,123 @@ func RegisterPostRoutes(r *mux.Router, s *server.Server) {
 		}
 		input.ID = id
 
+		var post db.Post
 		start := time.Now()
-		post, err := s.DB.UpdatePost(ctx, input)
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			current, err := q.GetPostByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if !httpcache.MatchStrong(ifMatch, postETag(current)) {
+				return &staleError{etag: postETag(current), current: current}
+			}
+			if input.Slug == "" {
+				input.Slug = current.Slug
+			}
+			if post, err = savePost(ctx, q, current, input); err != nil {
+				return err
+			}
+			return addPostEvent(ctx, out, post, !current.IsDraft.Bool)
+		})
 		metrics.ObserveDBQueryDuration("update_post", time.Since(start).Seconds())
 
 		if err == sql.ErrNoRows {
 			http.Error(w, `{"error":"Post not found"}`, http.StatusNotFound)
 			return
+		} else if writeStaleError(w, err) || writeSlugError(w, err) {
+			return
 		} else if err != nil {
 			http.Error(w, `{"error":"Failed to update post"}`, http.StatusInternalServerError)
 			return
 		}
+		post = refreshPostCoverMeta(ctx, s, post)
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", postETag(post))
 		_ = json.NewEncoder(w).Encode(post)
-	}).Methods("PUT")
+	}))).Methods("PUT")
+
+	// PATCH /posts/{id} - Partial update (JSON Merge Patch), signed in only
+	r.Handle("/posts/{id}", middleware.RequireAuth(s.DB)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("posts-handler")
+		ctx, span := tracer.Start(r.Context(), "PatchPost")
+		defer span.End()
+
+		idStr := mux.Vars(r)["id"]
+		id64, err := strconv.ParseInt(idStr, 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid ID"}`, http.StatusBadRequest)
+			return
+		}
+		id := int32(id64)
+
+		ifMatch, ok := requireIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		patch, ok := readMergePatch(w, r)
+		if !ok {
+			return
+		}
+
+		var post db.Post
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			current, err := q.GetPostByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if !httpcache.MatchStrong(ifMatch, postETag(current)) {
+				return &staleError{etag: postETag(current), current: current}
+			}
+			var doc postDocument
+			if err := applyMergePatch(postDocumentOf(current), patch, &doc); err != nil {
+				return err
+			}
+			// Validate the merged document, not the patch
+			switch {
+			case strings.TrimSpace(doc.Title) == "":
+				return patchError("title is required")
+			case doc.Slug == "":
+				return patchError("slug cannot be null")
+			case doc.IsDraft == nil:
+				return patchError("is_draft cannot be null")
+			}
+			tags := doc.Tags
+			if tags == nil {
+				tags = []string{}
+			}
+			post, err = savePost(ctx, q, current, db.UpdatePostParams{
+				ID:         id,
+				Title:      doc.Title,
+				Slug:       doc.Slug,
+				Summary:    utils.ToNullString(doc.Summary),
+				Content:    doc.Content,
+				Tags:       tags,
+				IsDraft:    sql.NullBool{Bool: *doc.IsDraft, Valid: true},
+				CoverImage: doc.CoverImage,
+			})
+			if err != nil {
+				return err
+			}
+			return addPostEvent(ctx, out, post, !current.IsDraft.Bool)
+		})
+		metrics.ObserveDBQueryDuration("patch_post", time.Since(start).Seconds())
+
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Post not found"}`, http.StatusNotFound)
+			return
+		} else if writeStaleError(w, err) || writePatchError(w, err) || writeSlugError(w, err) {
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to update post"}`, http.StatusInternalServerError)
+			return
+		}
+		post = refreshPostCoverMeta(ctx, s, post)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", postETag(post))
+		_ = json.NewEncoder(w).Encode(post)
+	}))).Methods("PATCH")
 
 	// DELETE /posts/{id} - Delete a post
 	r.HandleFunc("/posts/{id}", func(w http.ResponseWriter, r *http.Request) {
@@ -147,7 +386,12 @@ func RegisterPostRoutes(r *mux.Router, s *server.Server) {
 		id := int32(id64)
 
 		start := time.Now()
-		err = s.DB.DeletePost(ctx, id)
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			if err := q.DeletePost(ctx, id); err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.PostDeleted, bus.Change{PostIDs: []int32{id}})
+		})
 		metrics.ObserveDBQueryDuration("delete_post", time.Since(start).Seconds())
 
 		if err == sql.ErrNoRows {
@@ -160,3 +404,87 @@ func RegisterPostRoutes(r *mux.Router, s *server.Server) {
 		w.WriteHeader(http.StatusNoContent)
 	}).Methods("DELETE")
 }
+
+// postDocument is the editable part of a post that a PATCH merge patch
+// applies to.
+type postDocument struct {
+	Title      string   `json:"title"`
+	Slug       string   `json:"slug"`
+	Summary    *string  `json:"summary"`
+	Content    string   `json:"content"`
+	Tags       []string `json:"tags"`
+	IsDraft    *bool    `json:"is_draft"`
+	CoverImage string   `json:"cover_image"`
+}
+
+func postDocumentOf(p db.Post) postDocument {
+	doc := postDocument{
+		Title:      p.Title,
+		Slug:       p.Slug,
+		Summary:    toPtr(p.Summary),
+		Content:    p.Content,
+		Tags:       p.Tags,
+		CoverImage: p.CoverImage,
+	}
+	if p.IsDraft.Valid {
+		doc.IsDraft = &p.IsDraft.Bool
+	} else {
+		doc.IsDraft = new(bool)
+	}
+	return doc
+}
+
+// RegisterAdminPostRoutes registers post routes for editors
+func RegisterAdminPostRoutes(r *mux.Router, s *server.Server) {
+	// GET /admin/posts/{id} - Get any post by ID, drafts included, with the
+	// ETag that PUT and PATCH /posts/{id} expect in If-Match
+	r.HandleFunc("/posts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("posts-handler")
+		ctx, span := tracer.Start(r.Context(), "AdminGetPost")
+		defer span.End()
+
+		idStr := mux.Vars(r)["id"]
+		id64, err := strconv.ParseInt(idStr, 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid ID"}`, http.StatusBadRequest)
+			return
+		}
+
+		start := time.Now()
+		post, err := s.DB.GetPostByID(ctx, int32(id64))
+		metrics.ObserveDBQueryDuration("get_post_by_id", time.Since(start).Seconds())
+
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Post not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to fetch post"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", postETag(post))
+		_ = json.NewEncoder(w).Encode(post)
+	}).Methods("GET")
+}
+
+// savePost writes input over current and refreshes the detected project
+// links. A changed slug leaves the old one behind as a redirect.
+func savePost(ctx context.Context, q *db.Queries, current db.Post, input db.UpdatePostParams) (db.Post, error) {
+	if input.Slug != current.Slug {
+		if err := postSlugs.claim(ctx, q, current.ID, input.Slug); err != nil {
+			return db.Post{}, err
+		}
+	}
+	post, err := q.UpdatePost(ctx, input)
+	if err != nil {
+		return db.Post{}, err
+	}
+	if err := postlinks.Sync(ctx, q, post.ID, post.Content, feed.SiteURL()); err != nil {
+		return db.Post{}, err
+	}
+	if post.Slug != current.Slug {
+		return post, postSlugs.record(ctx, q, current.Slug, current.ID)
+	}
+	return post, nil
+}
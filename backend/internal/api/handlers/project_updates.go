@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/listquery"
+	"github.com/onnwee/onnwee.github.io/backend/internal/markdown"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/respcache"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
+)
+
+// projectUpdateListSpec is the sort/filter allowlist for a project's timeline.
+var projectUpdateListSpec = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"id":           {Column: "id", Type: listquery.Int, Sortable: true, Filterable: true},
+		"title":        {Column: "title", Type: listquery.Text, Sortable: true, Filterable: true},
+		"version":      {Column: "version", Type: listquery.Text, Sortable: true, Filterable: true},
+		"published_at": {Column: "published_at", Type: listquery.Time, Sortable: true, Filterable: true},
+	},
+	DefaultSort:  "-published_at",
+	DefaultLimit: 10,
+	MaxLimit:     100,
+}
+
+// projectUpdateResponse is a changelog entry as returned by the API.
+type projectUpdateResponse struct {
+	ID          int32   `json:"id"`
+	Title       string  `json:"title"`
+	Body        string  `json:"body"`
+	BodyHTML    string  `json:"body_html"`
+	Version     *string `json:"version"`
+	Link        *string `json:"link"`
+	PublishedAt string  `json:"published_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+func toProjectUpdateResponse(u db.ProjectUpdate) projectUpdateResponse {
+	return projectUpdateResponse{
+		ID:          u.ID,
+		Title:       u.Title,
+		Body:        u.Body,
+		BodyHTML:    u.BodyHtml,
+		Version:     toPtr(u.Version),
+		Link:        toPtr(u.Link),
+		PublishedAt: u.PublishedAt.Format(time.RFC3339),
+		UpdatedAt:   toTimeString(u.UpdatedAt),
+	}
+}
+
+func toProjectUpdateResponses(updates []db.ProjectUpdate) []projectUpdateResponse {
+	out := make([]projectUpdateResponse, 0, len(updates))
+	for _, u := range updates {
+		out = append(out, toProjectUpdateResponse(u))
+	}
+	return out
+}
+
+// projectUpdateInput is the body of the admin create/update routes.
+type projectUpdateInput struct {
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	Version     *string    `json:"version"`
+	Link        *string    `json:"link"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+// validate checks the input and returns a message safe to show clients.
+func (in *projectUpdateInput) validate() string {
+	in.Title = strings.TrimSpace(in.Title)
+	if in.Title == "" {
+		return "title is required"
+	}
+	if in.Link != nil && *in.Link != "" {
+		u, err := url.Parse(*in.Link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "link must be an http(s) URL"
+		}
+	}
+	return ""
+}
+
+// publishedAt defaults a missing date to now.
+func (in projectUpdateInput) publishedAt() time.Time {
+	if in.PublishedAt == nil || in.PublishedAt.IsZero() {
+		return time.Now()
+	}
+	return *in.PublishedAt
+}
+
+// RegisterProjectUpdateRoutes registers the public project timeline
+func RegisterProjectUpdateRoutes(r *mux.Router, s *server.Server) {
+	// GET /projects/{slug}/updates - Published updates, newest first (?sort=&filter[field][op]=&limit=&offset=)
+	r.HandleFunc("/projects/{slug}/updates", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("project-updates-handler")
+		ctx, span := tracer.Start(r.Context(), "ListProjectUpdates")
+		defer span.End()
+
+		lq, err := listquery.Parse(r.URL.Query(), projectUpdateListSpec)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+		project, ok := projectBySlug(w, r, s)
+		if !ok {
+			return
+		}
+
+		// Updates dated in the future stay hidden until then
+		query, args := lq.Build("SELECT "+db.ProjectUpdateColumns+" FROM project_updates",
+			[]string{"project_id = $1", "published_at <= NOW()"}, []interface{}{project.ID})
+
+		start := time.Now()
+		updates, err := s.DB.QueryProjectUpdates(ctx, query, args...)
+		metrics.ObserveDBQueryDuration("list_project_updates", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list updates"}`, http.StatusInternalServerError)
+			return
+		}
+
+		respcache.Tag(ctx, projectTag(project.ID))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(toProjectUpdateResponses(updates))
+	}).Methods("GET")
+}
+
+// RegisterAdminProjectUpdateRoutes registers changelog CRUD for a project.
+// Every change bumps the project's updated_at.
+func RegisterAdminProjectUpdateRoutes(r *mux.Router, s *server.Server) {
+	// GET /admin/projects/{id}/updates - All updates, including scheduled ones
+	r.HandleFunc("/projects/{id}/updates", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("project-updates-handler")
+		ctx, span := tracer.Start(r.Context(), "AdminListProjectUpdates")
+		defer span.End()
+
+		ids, ok := parseUpdateIDs(w, r, false)
+		if !ok {
+			return
+		}
+
+		start := time.Now()
+		updates, err := s.DB.ListProjectUpdates(ctx, ids.ProjectID)
+		metrics.ObserveDBQueryDuration("list_project_updates", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list updates"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(toProjectUpdateResponses(updates))
+	}).Methods("GET")
+
+	// POST /admin/projects/{id}/updates - Add an update
+	r.HandleFunc("/projects/{id}/updates", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("project-updates-handler")
+		ctx, span := tracer.Start(r.Context(), "CreateProjectUpdate")
+		defer span.End()
+
+		ids, ok := parseUpdateIDs(w, r, false)
+		if !ok {
+			return
+		}
+		var in projectUpdateInput
+		if !decodeProjectUpdate(w, r, &in) {
+			return
+		}
+		html, err := markdown.Render(in.Body)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to render body"}`, http.StatusInternalServerError)
+			return
+		}
+
+		var update db.ProjectUpdate
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			var err error
+			update, err = q.CreateProjectUpdate(ctx, db.CreateProjectUpdateParams{
+				ProjectID:   ids.ProjectID,
+				Title:       in.Title,
+				Body:        in.Body,
+				BodyHtml:    html,
+				Version:     utils.ToNullString(in.Version),
+				Link:        utils.ToNullString(in.Link),
+				PublishedAt: in.publishedAt(),
+			})
+			if err != nil {
+				return err
+			}
+			if err := q.TouchProject(ctx, ids.ProjectID); err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.ProjectUpdated, bus.Change{ProjectIDs: []int32{ids.ProjectID}})
+		})
+		metrics.ObserveDBQueryDuration("create_project_update", time.Since(start).Seconds())
+
+		if isForeignKeyViolation(err) {
+			http.Error(w, `{"error":"Project not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to create update"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(toProjectUpdateResponse(update))
+	}).Methods("POST")
+
+	// PUT /admin/projects/{id}/updates/{updateID} - Replace an update
+	r.HandleFunc("/projects/{id}/updates/{updateID}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("project-updates-handler")
+		ctx, span := tracer.Start(r.Context(), "UpdateProjectUpdate")
+		defer span.End()
+
+		ids, ok := parseUpdateIDs(w, r, true)
+		if !ok {
+			return
+		}
+		var in projectUpdateInput
+		if !decodeProjectUpdate(w, r, &in) {
+			return
+		}
+		html, err := markdown.Render(in.Body)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to render body"}`, http.StatusInternalServerError)
+			return
+		}
+
+		var update db.ProjectUpdate
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			var err error
+			update, err = q.UpdateProjectUpdate(ctx, db.UpdateProjectUpdateParams{
+				ID:          ids.ID,
+				ProjectID:   ids.ProjectID,
+				Title:       in.Title,
+				Body:        in.Body,
+				BodyHtml:    html,
+				Version:     utils.ToNullString(in.Version),
+				Link:        utils.ToNullString(in.Link),
+				PublishedAt: in.publishedAt(),
+			})
+			if err != nil {
+				return err
+			}
+			if err := q.TouchProject(ctx, ids.ProjectID); err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.ProjectUpdated, bus.Change{ProjectIDs: []int32{ids.ProjectID}})
+		})
+		metrics.ObserveDBQueryDuration("update_project_update", time.Since(start).Seconds())
+
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Update not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to save update"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(toProjectUpdateResponse(update))
+	}).Methods("PUT")
+
+	// DELETE /admin/projects/{id}/updates/{updateID} - Remove an update
+	r.HandleFunc("/projects/{id}/updates/{updateID}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("project-updates-handler")
+		ctx, span := tracer.Start(r.Context(), "DeleteProjectUpdate")
+		defer span.End()
+
+		ids, ok := parseUpdateIDs(w, r, true)
+		if !ok {
+			return
+		}
+
+		var n int64
+		start := time.Now()
+		err := s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			var err error
+			if n, err = q.DeleteProjectUpdate(ctx, ids); err != nil || n == 0 {
+				return err
+			}
+			if err := q.TouchProject(ctx, ids.ProjectID); err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.ProjectUpdated, bus.Change{ProjectIDs: []int32{ids.ProjectID}})
+		})
+		metrics.ObserveDBQueryDuration("delete_project_update", time.Since(start).Seconds())
+
+		if err != nil {
+			http.Error(w, `{"error":"Failed to delete update"}`, http.StatusInternalServerError)
+			return
+		}
+		if n == 0 {
+			http.Error(w, `{"error":"Update not found"}`, http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+}
+
+// parseUpdateIDs reads {id} and, when withUpdate is set, {updateID}.
+func parseUpdateIDs(w http.ResponseWriter, r *http.Request, withUpdate bool) (db.DeleteProjectUpdateParams, bool) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid project ID"}`, http.StatusBadRequest)
+		return db.DeleteProjectUpdateParams{}, false
+	}
+	ids := db.DeleteProjectUpdateParams{ProjectID: int32(projectID)}
+	if withUpdate {
+		updateID, err := strconv.ParseInt(vars["updateID"], 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid update ID"}`, http.StatusBadRequest)
+			return db.DeleteProjectUpdateParams{}, false
+		}
+		ids.ID = int32(updateID)
+	}
+	return ids, true
+}
+
+func decodeProjectUpdate(w http.ResponseWriter, r *http.Request, in *projectUpdateInput) bool {
+	if err := json.NewDecoder(r.Body).Decode(in); err != nil {
+		http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+		return false
+	}
+	if msg := in.validate(); msg != "" {
+		body, _ := json.Marshal(map[string]string{"error": msg})
+		http.Error(w, string(body), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
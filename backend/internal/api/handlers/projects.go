@@ -1,114 +1,150 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
 	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/httpcache"
+	"github.com/onnwee/onnwee.github.io/backend/internal/listquery"
 	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/respcache"
 	"github.com/onnwee/onnwee.github.io/backend/internal/server"
 	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
 	"go.opentelemetry.io/otel"
 )
 
-// RegisterPublicProjectRoutes registers read-only project routes
-func RegisterPublicProjectRoutes(r *mux.Router, s *server.Server) {
-	type projectResponse struct {
-		ID          int32    `json:"id"`
-		Title       string   `json:"title"`
-		Slug        string   `json:"slug"`
-		Description *string  `json:"description"`
-		RepoURL     *string  `json:"repo_url"`
-		LiveURL     *string  `json:"live_url"`
-		Summary     *string  `json:"summary"`
-		Tags        []string `json:"tags"`
-		Footer      *string  `json:"footer"`
-		Href        *string  `json:"href"`
-		External    bool     `json:"external"`
-		Color       *string  `json:"color"`
-		Emoji       *string  `json:"emoji"`
-		Content     *string  `json:"content"`
-		Image       *string  `json:"image"`
-		Embed       *string  `json:"embed"`
-		CreatedAt   string   `json:"created_at"`
-		UpdatedAt   string   `json:"updated_at"`
-	}
+// projectListSpec is the sort/filter allowlist for GET /projects.
+var projectListSpec = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"id":         {Column: "id", Type: listquery.Int, Sortable: true, Filterable: true},
+		"title":      {Column: "title", Type: listquery.Text, Sortable: true, Filterable: true},
+		"slug":       {Column: "slug", Type: listquery.Text, Sortable: true, Filterable: true},
+		"tags":       {Column: "tags", Type: listquery.TextArray, Filterable: true},
+		"color":      {Column: "color", Type: listquery.Text, Filterable: true},
+		"external":   {Column: "external", Type: listquery.Bool, Filterable: true},
+		"status":     {Column: "status", Type: listquery.Text, Filterable: true},
+		"featured":   {Column: "featured", Type: listquery.Bool, Sortable: true, Filterable: true},
+		"sort_order": {Column: "sort_order", Type: listquery.Int, Sortable: true, Filterable: true},
+		"user_id":    {Column: "user_id", Type: listquery.Int, Filterable: true},
+		"created_at": {Column: "created_at", Type: listquery.Time, Sortable: true, Filterable: true},
+		"updated_at": {Column: "updated_at", Type: listquery.Time, Sortable: true, Filterable: true},
+	},
+	DefaultSort:  "-featured,sort_order,-created_at",
+	DefaultLimit: 100,
+	MaxLimit:     500,
+}
 
-	toPtr := func(ns sql.NullString) *string {
-		if ns.Valid {
-			v := ns.String
-			return &v
-		}
-		return nil
-	}
+// Project statuses. Drafts are only visible through the admin routes.
+const (
+	projectDraft     = "draft"
+	projectPublished = "published"
+	projectArchived  = "archived"
+)
 
-	toTimeString := func(t sql.NullTime) string {
-		if t.Valid {
-			return t.Time.Format(time.RFC3339)
-		}
-		return ""
-	}
+func validProjectStatus(status string) bool {
+	return status == projectDraft || status == projectPublished || status == projectArchived
+}
 
-	toResp := func(p db.Project) projectResponse {
-		// Ensure non-nil tags
-		tags := p.Tags
-		if tags == nil {
-			tags = []string{}
-		}
-		return projectResponse{
-			ID:          p.ID,
-			Title:       p.Title,
-			Slug:        p.Slug,
-			Description: toPtr(p.Description),
-			RepoURL:     toPtr(p.RepoUrl),
-			LiveURL:     toPtr(p.LiveUrl),
-			Summary:     toPtr(p.Summary),
-			Tags:        tags,
-			Footer:      toPtr(p.Footer),
-			Href:        toPtr(p.Href),
-			External:    p.External,
-			Color:       toPtr(p.Color),
-			Emoji:       toPtr(p.Emoji),
-			Content:     toPtr(p.Content),
-			Image:       toPtr(p.Image),
-			Embed:       toPtr(p.Embed),
-			CreatedAt:   toTimeString(p.CreatedAt),
-			UpdatedAt:   toTimeString(p.UpdatedAt),
-		}
-	}
+// projectResponse is the public JSON representation of a project.
+type projectResponse struct {
+	ID             int32           `json:"id"`
+	Title          string          `json:"title"`
+	Slug           string          `json:"slug"`
+	Description    *string         `json:"description"`
+	RepoURL        *string         `json:"repo_url"`
+	LiveURL        *string         `json:"live_url"`
+	Summary        *string         `json:"summary"`
+	Tags           []string        `json:"tags"`
+	Footer         *string         `json:"footer"`
+	Href           *string         `json:"href"`
+	External       bool            `json:"external"`
+	Color          *string         `json:"color"`
+	Emoji          *string         `json:"emoji"`
+	Content        *string         `json:"content"`
+	Image          *string         `json:"image"`
+	ImageMeta      json.RawMessage `json:"image_meta"`
+	Embed          *string         `json:"embed"`
+	ReactionCounts json.RawMessage `json:"reaction_counts"`
+	Status         string          `json:"status"`
+	Featured       bool            `json:"featured"`
+	SortOrder      int32           `json:"sort_order"`
+	CreatedAt      string          `json:"created_at"`
+	UpdatedAt      string          `json:"updated_at"`
+	Author         *publicUser     `json:"author,omitempty"`
+	Posts          []linkedPost    `json:"posts,omitempty"`
+}
 
-	// GET /projects - List projects
-	r.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
-		tracer := otel.Tracer("projects-handler")
-		ctx, span := tracer.Start(r.Context(), "ListProjects")
-		defer span.End()
+// projectFields is the ?fields= allowlist for project responses.
+var projectFields = listquery.JSONFields(projectResponse{})
 
-		start := time.Now()
-		projects, err := s.DB.ListProjects(ctx)
-		metrics.ObserveDBQueryDuration("list_projects", time.Since(start).Seconds())
+// projectLazyColumns are the heavy columns a list leaves unread when ?fields=
+// does not ask for them.
+var projectLazyColumns = map[string]string{"content": "NULL", "embed": "NULL"}
 
-		if err != nil {
-			http.Error(w, `{"error":"Failed to fetch projects"}`, http.StatusInternalServerError)
-			return
-		}
+func toPtr(ns sql.NullString) *string {
+	if ns.Valid {
+		v := ns.String
+		return &v
+	}
+	return nil
+}
 
-		// Ensure we return an empty array instead of null for zero projects
-		if projects == nil {
-			projects = []db.Project{}
-		}
+func toTimeString(t sql.NullTime) string {
+	if t.Valid {
+		return t.Time.Format(time.RFC3339)
+	}
+	return ""
+}
 
-		// Map to clean JSON
-		resp := make([]projectResponse, 0, len(projects))
-		for _, p := range projects {
-			resp = append(resp, toResp(p))
-		}
+func toProjectResponse(p db.Project) projectResponse {
+	// Ensure non-nil tags
+	tags := p.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return projectResponse{
+		ID:             p.ID,
+		Title:          p.Title,
+		Slug:           p.Slug,
+		Description:    toPtr(p.Description),
+		RepoURL:        toPtr(p.RepoUrl),
+		LiveURL:        toPtr(p.LiveUrl),
+		Summary:        toPtr(p.Summary),
+		Tags:           tags,
+		Footer:         toPtr(p.Footer),
+		Href:           toPtr(p.Href),
+		External:       p.External,
+		Color:          toPtr(p.Color),
+		Emoji:          toPtr(p.Emoji),
+		Content:        toPtr(p.Content),
+		Image:          toPtr(p.Image),
+		ImageMeta:      p.ImageMeta,
+		Embed:          toPtr(p.Embed),
+		ReactionCounts: p.ReactionCounts,
+		Status:         p.Status,
+		Featured:       p.Featured,
+		SortOrder:      p.SortOrder,
+		CreatedAt:      toTimeString(p.CreatedAt),
+		UpdatedAt:      toTimeString(p.UpdatedAt),
+	}
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(resp)
+// RegisterPublicProjectRoutes registers read-only project routes
+func RegisterPublicProjectRoutes(r *mux.Router, s *server.Server) {
+	listPolicy := httpcache.PolicyFromEnv("CACHE_CONTROL_PROJECTS", defaultListPolicy)
+	detailPolicy := httpcache.PolicyFromEnv("CACHE_CONTROL_PROJECT", defaultDetailPolicy)
+
+	// GET /projects - List published and archived projects (?sort=&filter[field][op]=&limit=&offset=)
+	r.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		serveProjectList(w, r, s, []string{"status <> 'draft'"}, &listPolicy)
 	}).Methods("GET")
 
 	// GET /projects/{slug} - Get project by slug
@@ -119,106 +155,312 @@ func RegisterPublicProjectRoutes(r *mux.Router, s *server.Server) {
 
 		slug := mux.Vars(r)["slug"]
 
+		fields, include, err := parseShape(r, projectFields)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+
 		start := time.Now()
 		project, err := s.DB.GetProjectBySlug(ctx, slug)
 		metrics.ObserveDBQueryDuration("get_project_by_slug", time.Since(start).Seconds())
 
 		if err == sql.ErrNoRows {
-			http.Error(w, `{"error":"Project not found"}`, http.StatusNotFound)
+			// Fall back to the slug history before giving up
+			if moved, rerr := projectSlugs.redirect(w, r, s.DB, slug); rerr != nil {
+				http.Error(w, `{"error":"Failed to fetch project"}`, http.StatusInternalServerError)
+			} else if !moved {
+				http.Error(w, `{"error":"Project not found"}`, http.StatusNotFound)
+			}
 			return
 		} else if err != nil {
 			http.Error(w, `{"error":"Failed to fetch project"}`, http.StatusInternalServerError)
 			return
 		}
+		if project.Status == projectDraft {
+			http.Error(w, `{"error":"Project not found"}`, http.StatusNotFound)
+			return
+		}
 
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(toResp(project))
+		resp := toProjectResponse(project)
+		modified := project.UpdatedAt.Time
+		if listquery.Includes(include, "author") {
+			authors, err := loadAuthors(ctx, s, []sql.NullInt32{project.UserID})
+			if err != nil {
+				http.Error(w, `{"error":"Failed to fetch author"}`, http.StatusInternalServerError)
+				return
+			}
+			if resp.Author = authorOf(authors, project.UserID); resp.Author != nil {
+				modified = latest(modified, resp.Author.updatedAt)
+			}
+		}
+		var postsModified time.Time
+		if resp.Posts, postsModified, err = loadLinkedPosts(ctx, s, project.ID); err != nil {
+			http.Error(w, `{"error":"Failed to fetch posts"}`, http.StatusInternalServerError)
+			return
+		}
+		modified = latest(modified, postsModified)
+
+		out, err := listquery.Select(resp, fields)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to encode project"}`, http.StatusInternalServerError)
+			return
+		}
+
+		respcache.Tag(ctx, projectTag(project.ID))
+		// Hashed from the body: counts, links and ?fields change it, not just edits
+		writeCached(w, r, detailPolicy, "", modified, out)
 	}).Methods("GET")
 }
 
-// RegisterAdminProjectRoutes registers admin-only (CRUD) project routes
-func RegisterAdminProjectRoutes(r *mux.Router, s *server.Server) {
-	type projectResponse struct {
-		ID          int32    `json:"id"`
-		Title       string   `json:"title"`
-		Slug        string   `json:"slug"`
-		Description *string  `json:"description"`
-		RepoURL     *string  `json:"repo_url"`
-		LiveURL     *string  `json:"live_url"`
-		Summary     *string  `json:"summary"`
-		Tags        []string `json:"tags"`
-		Footer      *string  `json:"footer"`
-		Href        *string  `json:"href"`
-		External    bool     `json:"external"`
-		Color       *string  `json:"color"`
-		Emoji       *string  `json:"emoji"`
-		Content     *string  `json:"content"`
-		Image       *string  `json:"image"`
-		Embed       *string  `json:"embed"`
-		CreatedAt   string   `json:"created_at"`
-		UpdatedAt   string   `json:"updated_at"`
+// serveProjectList answers a project list request. conds narrows the rows,
+// e.g. to hide drafts from the public; a non-nil policy makes the response
+// cacheable.
+func serveProjectList(w http.ResponseWriter, r *http.Request, s *server.Server, conds []string, policy *httpcache.Policy) {
+	tracer := otel.Tracer("projects-handler")
+	ctx, span := tracer.Start(r.Context(), "ListProjects")
+	defer span.End()
+
+	lq, err := listquery.Parse(r.URL.Query(), projectListSpec)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	fields, include, err := parseShape(r, projectFields)
+	if err != nil {
+		writeQueryError(w, err)
+		return
 	}
+	query, args := lq.Build("SELECT "+listquery.Columns(db.ProjectColumns, fields, projectLazyColumns)+" FROM projects", conds, nil)
 
-	toPtr := func(ns sql.NullString) *string {
-		if ns.Valid {
-			v := ns.String
-			return &v
-		}
-		return nil
+	start := time.Now()
+	projects, err := s.DB.QueryProjects(ctx, query, args...)
+	metrics.ObserveDBQueryDuration("list_projects", time.Since(start).Seconds())
+
+	if err != nil {
+		http.Error(w, `{"error":"Failed to fetch projects"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Ensure we return an empty array instead of null for zero projects
+	if projects == nil {
+		projects = []db.Project{}
 	}
 
-	toTimeString := func(t sql.NullTime) string {
-		if t.Valid {
-			return t.Time.Format(time.RFC3339)
+	var authors map[int32]*publicUser
+	if listquery.Includes(include, "author") {
+		userIDs := make([]sql.NullInt32, len(projects))
+		for i, p := range projects {
+			userIDs[i] = p.UserID
+		}
+		if authors, err = loadAuthors(ctx, s, userIDs); err != nil {
+			http.Error(w, `{"error":"Failed to fetch authors"}`, http.StatusInternalServerError)
+			return
 		}
-		return ""
 	}
 
-	toResp := func(p db.Project) projectResponse {
-		// Ensure non-nil tags
-		tags := p.Tags
-		if tags == nil {
-			tags = []string{}
+	// Map to clean JSON
+	resp := make([]projectResponse, 0, len(projects))
+	for _, p := range projects {
+		pr := toProjectResponse(p)
+		pr.Author = authorOf(authors, p.UserID)
+		resp = append(resp, pr)
+	}
+
+	out, err := listquery.Select(resp, fields)
+	if err != nil {
+		http.Error(w, `{"error":"Failed to encode projects"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Lists are capped at the spec's limit; say where the rest is
+	if link := lq.NextLink(r.URL.Query(), len(projects)); link != "" {
+		w.Header().Set("Link", link)
+	}
+	if policy != nil {
+		modified := make([]time.Time, len(projects))
+		for i, p := range projects {
+			modified[i] = p.UpdatedAt.Time
 		}
-		return projectResponse{
-			ID:          p.ID,
-			Title:       p.Title,
-			Slug:        p.Slug,
-			Description: toPtr(p.Description),
-			RepoURL:     toPtr(p.RepoUrl),
-			LiveURL:     toPtr(p.LiveUrl),
-			Summary:     toPtr(p.Summary),
-			Tags:        tags,
-			Footer:      toPtr(p.Footer),
-			Href:        toPtr(p.Href),
-			External:    p.External,
-			Color:       toPtr(p.Color),
-			Emoji:       toPtr(p.Emoji),
-			Content:     toPtr(p.Content),
-			Image:       toPtr(p.Image),
-			Embed:       toPtr(p.Embed),
-			CreatedAt:   toTimeString(p.CreatedAt),
-			UpdatedAt:   toTimeString(p.UpdatedAt),
+		setListModified(w, modified)
+		respcache.Tag(ctx, projectsTag)
+		writeCached(w, r, *policy, "", time.Time{}, out)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// projectPayload is the body of the admin create and update routes, and the
+// document a PATCH merge patch applies to.
+type projectPayload struct {
+	Title       string   `json:"title"`
+	Slug        string   `json:"slug"`
+	Description *string  `json:"description"`
+	RepoURL     *string  `json:"repo_url"`
+	LiveURL     *string  `json:"live_url"`
+	Summary     *string  `json:"summary"`
+	Tags        []string `json:"tags"`
+	Footer      *string  `json:"footer"`
+	Href        *string  `json:"href"`
+	External    *bool    `json:"external"`
+	Color       *string  `json:"color"`
+	Emoji       *string  `json:"emoji"`
+	Content     *string  `json:"content"`
+	Image       *string  `json:"image"`
+	Embed       *string  `json:"embed"`
+	Status      *string  `json:"status"`
+	Featured    *bool    `json:"featured"`
+	SortOrder   *int32   `json:"sort_order"`
+}
+
+// projectPayloadOf returns the stored project in payload form.
+func projectPayloadOf(p db.Project) projectPayload {
+	return projectPayload{
+		Title:       p.Title,
+		Slug:        p.Slug,
+		Description: toPtr(p.Description),
+		RepoURL:     toPtr(p.RepoUrl),
+		LiveURL:     toPtr(p.LiveUrl),
+		Summary:     toPtr(p.Summary),
+		Tags:        p.Tags,
+		Footer:      toPtr(p.Footer),
+		Href:        toPtr(p.Href),
+		External:    &p.External,
+		Color:       toPtr(p.Color),
+		Emoji:       toPtr(p.Emoji),
+		Content:     toPtr(p.Content),
+		Image:       toPtr(p.Image),
+		Embed:       toPtr(p.Embed),
+		Status:      &p.Status,
+		Featured:    &p.Featured,
+		SortOrder:   &p.SortOrder,
+	}
+}
+
+// updateParams maps the payload onto current. An empty slug and missing
+// visibility or ordering fields keep their current values.
+func (body projectPayload) updateParams(current db.Project) db.UpdateProjectParams {
+	tags := body.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	params := db.UpdateProjectParams{
+		ID:          current.ID,
+		Title:       body.Title,
+		Slug:        body.Slug,
+		Description: utils.ToNullString(body.Description),
+		RepoUrl:     utils.ToNullString(body.RepoURL),
+		LiveUrl:     utils.ToNullString(body.LiveURL),
+		Summary:     utils.ToNullString(body.Summary),
+		Tags:        tags,
+		Footer:      utils.ToNullString(body.Footer),
+		Href:        utils.ToNullString(body.Href),
+		External:    body.External != nil && *body.External,
+		Color:       utils.ToNullString(body.Color),
+		Emoji:       utils.ToNullString(body.Emoji),
+		Content:     utils.ToNullString(body.Content),
+		Image:       utils.ToNullString(body.Image),
+		Embed:       utils.ToNullString(body.Embed),
+		Status:      current.Status,
+		Featured:    current.Featured,
+		SortOrder:   current.SortOrder,
+	}
+	if params.Slug == "" {
+		params.Slug = current.Slug
+	}
+	if body.Status != nil {
+		params.Status = *body.Status
+	}
+	if body.Featured != nil {
+		params.Featured = *body.Featured
+	}
+	if body.SortOrder != nil {
+		params.SortOrder = *body.SortOrder
+	}
+	return params
+}
+
+// saveProject writes params over current. A changed slug leaves the old one
+// behind as a redirect.
+func saveProject(ctx context.Context, q *db.Queries, current db.Project, params db.UpdateProjectParams) (db.Project, error) {
+	if params.Slug != current.Slug {
+		if err := projectSlugs.claim(ctx, q, current.ID, params.Slug); err != nil {
+			return db.Project{}, err
 		}
 	}
+	project, err := q.UpdateProject(ctx, params)
+	if err != nil {
+		return db.Project{}, err
+	}
+	if project.Slug != current.Slug {
+		return project, projectSlugs.record(ctx, q, current.Slug, current.ID)
+	}
+	return project, nil
+}
 
-	type projectPayload struct {
-		Title       string   `json:"title"`
-		Slug        string   `json:"slug"`
-		Description *string  `json:"description"`
-		RepoURL     *string  `json:"repo_url"`
-		LiveURL     *string  `json:"live_url"`
-		Summary     *string  `json:"summary"`
-		Tags        []string `json:"tags"`
-		Footer      *string  `json:"footer"`
-		Href        *string  `json:"href"`
-		External    *bool    `json:"external"`
-		Color       *string  `json:"color"`
-		Emoji       *string  `json:"emoji"`
-		Content     *string  `json:"content"`
-		Image       *string  `json:"image"`
-		Embed       *string  `json:"embed"`
+// RegisterAdminProjectRoutes registers admin-only (CRUD) project routes
+func RegisterAdminProjectRoutes(r *mux.Router, s *server.Server) {
+	validStatus := func(w http.ResponseWriter, status *string) bool {
+		if status != nil && !validProjectStatus(*status) {
+			http.Error(w, `{"error":"status must be draft, published or archived"}`, http.StatusBadRequest)
+			return false
+		}
+		return true
 	}
+
+	// Bulk catalog import/export (registered before the /projects/{id} routes)
+	registerProjectCatalogRoutes(r, s)
+
+	// GET /admin/projects - List every project, drafts included
+	r.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		serveProjectList(w, r, s, nil, nil)
+	}).Methods("GET")
+
+	// PUT /admin/projects/order - Set sort_order from a list of IDs
+	r.HandleFunc("/projects/order", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("projects-handler")
+		ctx, span := tracer.Start(r.Context(), "ReorderProjects")
+		defer span.End()
+
+		var body struct {
+			IDs []int32 `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+		if len(body.IDs) == 0 {
+			http.Error(w, `{"error":"ids is required"}`, http.StatusBadRequest)
+			return
+		}
+
+		// Projects not listed keep their sort_order
+		start := time.Now()
+		err := s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			for i, id := range body.IDs {
+				n, err := q.SetProjectSortOrder(ctx, db.SetProjectSortOrderParams{ID: id, SortOrder: int32(i + 1)})
+				if err != nil {
+					return err
+				}
+				if n == 0 {
+					return sql.ErrNoRows
+				}
+			}
+			return out.Add(ctx, bus.ProjectUpdated, bus.Change{ProjectIDs: body.IDs})
+		})
+		metrics.ObserveDBQueryDuration("reorder_projects", time.Since(start).Seconds())
+
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Unknown project ID"}`, http.StatusBadRequest)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to reorder projects"}`, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("PUT")
+
 	// POST /admin/projects - Create a project
 	r.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
 		tracer := otel.Tracer("projects-handler")
@@ -230,12 +472,19 @@ func RegisterAdminProjectRoutes(r *mux.Router, s *server.Server) {
 			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
 			return
 		}
+		if !validStatus(w, body.Status) {
+			return
+		}
 
 		// default values
 		ext := false
 		if body.External != nil {
 			ext = *body.External
 		}
+		status := projectPublished
+		if body.Status != nil {
+			status = *body.Status
+		}
 		tags := body.Tags
 		if tags == nil {
 			tags = []string{}
@@ -258,22 +507,72 @@ func RegisterAdminProjectRoutes(r *mux.Router, s *server.Server) {
 			Image:       utils.ToNullString(body.Image),
 			Embed:       utils.ToNullString(body.Embed),
 			UserID:      sql.NullInt32{},
+			Status:      status,
+			Featured:    body.Featured != nil && *body.Featured,
+		}
+		if body.SortOrder != nil {
+			params.SortOrder = *body.SortOrder
 		}
 
+		var project db.Project
 		start := time.Now()
-		project, err := s.DB.CreateProject(ctx, params)
+		err := s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			slug, err := projectSlugs.forCreate(ctx, q, body.Slug, body.Title)
+			if err != nil {
+				return err
+			}
+			params.Slug = slug
+			if project, err = q.CreateProject(ctx, params); err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.ProjectUpdated, bus.Change{ProjectIDs: []int32{project.ID}})
+		})
 		metrics.ObserveDBQueryDuration("create_project", time.Since(start).Seconds())
 
-		if err != nil {
+		if writeSlugError(w, err) {
+			return
+		} else if err != nil {
 			http.Error(w, `{"error":"Failed to create project"}`, http.StatusInternalServerError)
 			return
 		}
+		project = refreshProjectImageMeta(ctx, s, project)
 
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", projectETag(project))
 		w.WriteHeader(http.StatusCreated)
-		_ = json.NewEncoder(w).Encode(toResp(project))
+		_ = json.NewEncoder(w).Encode(toProjectResponse(project))
 	}).Methods("POST")
 
+	// GET /admin/projects/{id} - Get any project by ID, drafts included
+	r.HandleFunc("/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("projects-handler")
+		ctx, span := tracer.Start(r.Context(), "AdminGetProject")
+		defer span.End()
+
+		idStr := mux.Vars(r)["id"]
+		id64, err := strconv.ParseInt(idStr, 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid ID"}`, http.StatusBadRequest)
+			return
+		}
+
+		start := time.Now()
+		project, err := s.DB.GetProjectByID(ctx, int32(id64))
+		metrics.ObserveDBQueryDuration("get_project_by_id", time.Since(start).Seconds())
+
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Project not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to fetch project"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", projectETag(project))
+		_ = json.NewEncoder(w).Encode(toProjectResponse(project))
+	}).Methods("GET")
+
 	// PUT /admin/projects/{id} - Update a project
 	r.HandleFunc("/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
 		tracer := otel.Tracer("projects-handler")
@@ -288,55 +587,131 @@ func RegisterAdminProjectRoutes(r *mux.Router, s *server.Server) {
 		}
 		id := int32(id64)
 
+		ifMatch, ok := requireIfMatch(w, r)
+		if !ok {
+			return
+		}
+
 		var body projectPayload
 		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
 			return
 		}
+		if !validStatus(w, body.Status) {
+			return
+		}
 
-		// Defaults
-		ext := false
-		if body.External != nil {
-			ext = *body.External
+		var project db.Project
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			current, err := q.GetProjectByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if !httpcache.MatchStrong(ifMatch, projectETag(current)) {
+				return &staleError{etag: projectETag(current), current: toProjectResponse(current)}
+			}
+			if project, err = saveProject(ctx, q, current, body.updateParams(current)); err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.ProjectUpdated, bus.Change{ProjectIDs: []int32{project.ID}})
+		})
+		metrics.ObserveDBQueryDuration("update_project", time.Since(start).Seconds())
+
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Project not found"}`, http.StatusNotFound)
+			return
+		} else if writeStaleError(w, err) || writeSlugError(w, err) {
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to update project"}`, http.StatusInternalServerError)
+			return
 		}
-		tags := body.Tags
-		if tags == nil {
-			tags = []string{}
+		project = refreshProjectImageMeta(ctx, s, project)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", projectETag(project))
+		_ = json.NewEncoder(w).Encode(toProjectResponse(project))
+	}).Methods("PUT")
+
+	// PATCH /admin/projects/{id} - Partial update (JSON Merge Patch)
+	r.HandleFunc("/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("projects-handler")
+		ctx, span := tracer.Start(r.Context(), "PatchProject")
+		defer span.End()
+
+		idStr := mux.Vars(r)["id"]
+		id64, err := strconv.ParseInt(idStr, 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid ID"}`, http.StatusBadRequest)
+			return
 		}
+		id := int32(id64)
 
-		params := db.UpdateProjectParams{
-			ID:          id,
-			Title:       body.Title,
-			Description: utils.ToNullString(body.Description),
-			RepoUrl:     utils.ToNullString(body.RepoURL),
-			LiveUrl:     utils.ToNullString(body.LiveURL),
-			Summary:     utils.ToNullString(body.Summary),
-			Tags:        tags,
-			Footer:      utils.ToNullString(body.Footer),
-			Href:        utils.ToNullString(body.Href),
-			External:    ext,
-			Color:       utils.ToNullString(body.Color),
-			Emoji:       utils.ToNullString(body.Emoji),
-			Content:     utils.ToNullString(body.Content),
-			Image:       utils.ToNullString(body.Image),
-			Embed:       utils.ToNullString(body.Embed),
+		ifMatch, ok := requireIfMatch(w, r)
+		if !ok {
+			return
 		}
 
+		patch, ok := readMergePatch(w, r)
+		if !ok {
+			return
+		}
+
+		var project db.Project
 		start := time.Now()
-		project, err := s.DB.UpdateProject(ctx, params)
-		metrics.ObserveDBQueryDuration("update_project", time.Since(start).Seconds())
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			current, err := q.GetProjectByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if !httpcache.MatchStrong(ifMatch, projectETag(current)) {
+				return &staleError{etag: projectETag(current), current: toProjectResponse(current)}
+			}
+			var body projectPayload
+			if err := applyMergePatch(projectPayloadOf(current), patch, &body); err != nil {
+				return err
+			}
+			// Validate the merged document, not the patch
+			switch {
+			case strings.TrimSpace(body.Title) == "":
+				return patchError("title is required")
+			case body.Slug == "":
+				return patchError("slug cannot be null")
+			case body.Status == nil:
+				return patchError("status cannot be null")
+			case !validProjectStatus(*body.Status):
+				return patchError("status must be draft, published or archived")
+			}
+			// null resets the remaining non-nullable fields
+			if body.Featured == nil {
+				body.Featured = new(bool)
+			}
+			if body.SortOrder == nil {
+				body.SortOrder = new(int32)
+			}
+			if project, err = saveProject(ctx, q, current, body.updateParams(current)); err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.ProjectUpdated, bus.Change{ProjectIDs: []int32{project.ID}})
+		})
+		metrics.ObserveDBQueryDuration("patch_project", time.Since(start).Seconds())
 
 		if err == sql.ErrNoRows {
 			http.Error(w, `{"error":"Project not found"}`, http.StatusNotFound)
 			return
+		} else if writeStaleError(w, err) || writePatchError(w, err) || writeSlugError(w, err) {
+			return
 		} else if err != nil {
 			http.Error(w, `{"error":"Failed to update project"}`, http.StatusInternalServerError)
 			return
 		}
+		project = refreshProjectImageMeta(ctx, s, project)
 
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(toResp(project))
-	}).Methods("PUT")
+		w.Header().Set("ETag", projectETag(project))
+		_ = json.NewEncoder(w).Encode(toProjectResponse(project))
+	}).Methods("PATCH")
 
 	// DELETE /admin/projects/{id} - Delete a project
 	r.HandleFunc("/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
@@ -353,7 +728,12 @@ func RegisterAdminProjectRoutes(r *mux.Router, s *server.Server) {
 		id := int32(id64)
 
 		start := time.Now()
-		err = s.DB.DeleteProject(ctx, id)
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			if err := q.DeleteProject(ctx, id); err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.ProjectDeleted, bus.Change{ProjectIDs: []int32{id}})
+		})
 		metrics.ObserveDBQueryDuration("delete_project", time.Since(start).Seconds())
 
 		if err == sql.ErrNoRows {
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/reactions"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
+)
+
+// reactionTarget abstracts over the post and project reaction queries.
+type reactionTarget struct {
+	// change names the reacted-to record in reaction.toggled
+	change bus.Change
+	counts json.RawMessage
+	add    func(ctx context.Context, q *db.Queries, kind, visitor string) (int64, error)
+	remove func(ctx context.Context, q *db.Queries, kind, visitor string) (int64, error)
+	adjust func(ctx context.Context, q *db.Queries, kind string, delta int32) (json.RawMessage, error)
+	list   func(ctx context.Context, q *db.Queries, visitor string) ([]string, error)
+}
+
+func postReactionTarget(p db.Post) reactionTarget {
+	id := sql.NullInt32{Int32: p.ID, Valid: true}
+	return reactionTarget{
+		change: bus.Change{PostIDs: []int32{p.ID}},
+		counts: p.ReactionCounts,
+		add: func(ctx context.Context, q *db.Queries, kind, visitor string) (int64, error) {
+			return q.AddPostReaction(ctx, db.AddPostReactionParams{PostID: id, Kind: kind, VisitorHash: visitor})
+		},
+		remove: func(ctx context.Context, q *db.Queries, kind, visitor string) (int64, error) {
+			return q.RemovePostReaction(ctx, db.RemovePostReactionParams{PostID: id, Kind: kind, VisitorHash: visitor})
+		},
+		adjust: func(ctx context.Context, q *db.Queries, kind string, delta int32) (json.RawMessage, error) {
+			return q.AdjustPostReactionCount(ctx, db.AdjustPostReactionCountParams{Kind: kind, Delta: delta, ID: p.ID})
+		},
+		list: func(ctx context.Context, q *db.Queries, visitor string) ([]string, error) {
+			return q.ListPostReactionsByVisitor(ctx, db.ListPostReactionsByVisitorParams{PostID: id, VisitorHash: visitor})
+		},
+	}
+}
+
+func projectReactionTarget(p db.Project) reactionTarget {
+	id := sql.NullInt32{Int32: p.ID, Valid: true}
+	return reactionTarget{
+		change: bus.Change{ProjectIDs: []int32{p.ID}},
+		counts: p.ReactionCounts,
+		add: func(ctx context.Context, q *db.Queries, kind, visitor string) (int64, error) {
+			return q.AddProjectReaction(ctx, db.AddProjectReactionParams{ProjectID: id, Kind: kind, VisitorHash: visitor})
+		},
+		remove: func(ctx context.Context, q *db.Queries, kind, visitor string) (int64, error) {
+			return q.RemoveProjectReaction(ctx, db.RemoveProjectReactionParams{ProjectID: id, Kind: kind, VisitorHash: visitor})
+		},
+		adjust: func(ctx context.Context, q *db.Queries, kind string, delta int32) (json.RawMessage, error) {
+			return q.AdjustProjectReactionCount(ctx, db.AdjustProjectReactionCountParams{Kind: kind, Delta: delta, ID: p.ID})
+		},
+		list: func(ctx context.Context, q *db.Queries, visitor string) ([]string, error) {
+			return q.ListProjectReactionsByVisitor(ctx, db.ListProjectReactionsByVisitorParams{ProjectID: id, VisitorHash: visitor})
+		},
+	}
+}
+
+type reactionInput struct {
+	Kind      string `json:"kind"`
+	SessionID string `json:"session_id"`
+}
+
+type reactionsResponse struct {
+	Counts  json.RawMessage `json:"counts"`
+	Reacted []string        `json:"reacted"`
+}
+
+// visitorHash identifies the caller from IP and user agent, narrowed by the
+// analytics session ID (body, X-Session-ID header or ?session_id=) if any.
+func visitorHash(r *http.Request, sessionID string) string {
+	if sessionID == "" {
+		sessionID = r.Header.Get("X-Session-ID")
+	}
+	if sessionID == "" {
+		sessionID = r.URL.Query().Get("session_id")
+	}
+	return reactions.VisitorHash(reactions.Salt(), sessionID, utils.GetIP(r), r.UserAgent())
+}
+
+// RegisterReactionRoutes registers the public reaction routes for posts and projects
+func RegisterReactionRoutes(r *mux.Router, s *server.Server) {
+	// GET /posts/{slug}/reactions - Counts plus the kinds the caller has reacted with
+	r.HandleFunc("/posts/{slug}/reactions", func(w http.ResponseWriter, r *http.Request) {
+		post, ok := publishedPost(w, r, s)
+		if !ok {
+			return
+		}
+		listReactions(w, r, s, postReactionTarget(post))
+	}).Methods("GET")
+
+	// POST /posts/{slug}/reactions - Toggle a reaction {"kind":"like"}
+	r.HandleFunc("/posts/{slug}/reactions", func(w http.ResponseWriter, r *http.Request) {
+		post, ok := publishedPost(w, r, s)
+		if !ok {
+			return
+		}
+		toggleReaction(w, r, s, postReactionTarget(post))
+	}).Methods("POST")
+
+	// GET /projects/{slug}/reactions
+	r.HandleFunc("/projects/{slug}/reactions", func(w http.ResponseWriter, r *http.Request) {
+		project, ok := projectBySlug(w, r, s)
+		if !ok {
+			return
+		}
+		listReactions(w, r, s, projectReactionTarget(project))
+	}).Methods("GET")
+
+	// POST /projects/{slug}/reactions
+	r.HandleFunc("/projects/{slug}/reactions", func(w http.ResponseWriter, r *http.Request) {
+		project, ok := projectBySlug(w, r, s)
+		if !ok {
+			return
+		}
+		toggleReaction(w, r, s, projectReactionTarget(project))
+	}).Methods("POST")
+}
+
+func listReactions(w http.ResponseWriter, r *http.Request, s *server.Server, t reactionTarget) {
+	tracer := otel.Tracer("reactions-handler")
+	ctx, span := tracer.Start(r.Context(), "ListReactions")
+	defer span.End()
+
+	start := time.Now()
+	reacted, err := t.list(ctx, s.DB, visitorHash(r, ""))
+	metrics.ObserveDBQueryDuration("list_reactions_by_visitor", time.Since(start).Seconds())
+	if err != nil {
+		http.Error(w, `{"error":"Failed to fetch reactions"}`, http.StatusInternalServerError)
+		return
+	}
+	writeReactions(w, t.counts, reacted)
+}
+
+// toggleReaction adds the caller's reaction, or removes it if already present,
+// and moves the denormalized counter in the same transaction.
+func toggleReaction(w http.ResponseWriter, r *http.Request, s *server.Server, t reactionTarget) {
+	tracer := otel.Tracer("reactions-handler")
+	ctx, span := tracer.Start(r.Context(), "ToggleReaction")
+	defer span.End()
+
+	var in reactionInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+	if !reactions.ValidKind(in.Kind) {
+		http.Error(w, `{"error":"kind must be one of like, fire, heart"}`, http.StatusBadRequest)
+		return
+	}
+	visitor := visitorHash(r, in.SessionID)
+
+	var counts json.RawMessage
+	var reacted []string
+	start := time.Now()
+	err := s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+		var delta int32 = 1
+		n, err := t.add(ctx, q, in.Kind, visitor)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			if _, err := t.remove(ctx, q, in.Kind, visitor); err != nil {
+				return err
+			}
+			delta = -1
+		}
+		if counts, err = t.adjust(ctx, q, in.Kind, delta); err != nil {
+			return err
+		}
+		if reacted, err = t.list(ctx, q, visitor); err != nil {
+			return err
+		}
+		// Drops the cached detail page; its ETag is hashed from the body, so
+		// the new counts also fail If-None-Match. updated_at, and with it
+		// If-Match, is left alone
+		return out.Add(ctx, bus.ReactionToggled, t.change)
+	})
+	metrics.ObserveDBQueryDuration("toggle_reaction", time.Since(start).Seconds())
+	if err != nil {
+		http.Error(w, `{"error":"Failed to update reaction"}`, http.StatusInternalServerError)
+		return
+	}
+	writeReactions(w, counts, reacted)
+}
+
+func writeReactions(w http.ResponseWriter, counts json.RawMessage, reacted []string) {
+	if len(counts) == 0 {
+		counts = json.RawMessage(`{}`)
+	}
+	if reacted == nil {
+		reacted = []string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(reactionsResponse{Counts: counts, Reacted: reacted})
+}
+
+// projectBySlug loads the project named by {slug}, writing a 404 if it is
+// missing or a draft.
+func projectBySlug(w http.ResponseWriter, r *http.Request, s *server.Server) (db.Project, bool) {
+	start := time.Now()
+	project, err := s.DB.GetProjectBySlug(r.Context(), mux.Vars(r)["slug"])
+	metrics.ObserveDBQueryDuration("get_project_by_slug", time.Since(start).Seconds())
+
+	if err == sql.ErrNoRows || (err == nil && project.Status == projectDraft) {
+		http.Error(w, `{"error":"Project not found"}`, http.StatusNotFound)
+		return db.Project{}, false
+	} else if err != nil {
+		http.Error(w, `{"error":"Failed to fetch project"}`, http.StatusInternalServerError)
+		return db.Project{}, false
+	}
+	return project, true
+}
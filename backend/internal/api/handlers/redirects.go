@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/listquery"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/redirects"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+)
+
+// notFoundListSpec is the sort/filter allowlist for GET /admin/not-found.
+var notFoundListSpec = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"path":          {Column: "path", Type: listquery.Text, Sortable: true, Filterable: true},
+		"hits":          {Column: "hits", Type: listquery.Int, Sortable: true, Filterable: true},
+		"last_referrer": {Column: "last_referrer", Type: listquery.Text, Filterable: true},
+		"first_seen_at": {Column: "first_seen_at", Type: listquery.Time, Sortable: true, Filterable: true},
+		"last_seen_at":  {Column: "last_seen_at", Type: listquery.Time, Sortable: true, Filterable: true},
+	},
+	DefaultSort:  "-hits",
+	DefaultLimit: 100,
+	MaxLimit:     500,
+	TieBreaker:   "path",
+}
+
+type redirectInput struct {
+	Source     string `json:"source"`
+	Target     string `json:"target"`
+	StatusCode int    `json:"status_code"`
+}
+
+// rule validates the input, defaulting to a permanent redirect.
+func (in redirectInput) rule() (redirects.Rule, error) {
+	if in.StatusCode == 0 {
+		in.StatusCode = http.StatusMovedPermanently
+	}
+	rule := redirects.Rule{Source: in.Source, Target: in.Target, StatusCode: in.StatusCode}
+	return rule, redirects.Validate(rule)
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint error.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isForeignKeyViolation reports whether err is a Postgres foreign key error.
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// checkRedirectLoop rejects a rule that would close a loop with the stored
+// ones. It writes the response and returns false when the rule is refused.
+func checkRedirectLoop(w http.ResponseWriter, r *http.Request, s *server.Server, rule redirects.Rule) bool {
+	start := time.Now()
+	rules, err := s.RedirectRules(r.Context())
+	metrics.ObserveDBQueryDuration("list_redirects", time.Since(start).Seconds())
+	if err != nil {
+		http.Error(w, `{"error":"Failed to check redirect"}`, http.StatusInternalServerError)
+		return false
+	}
+	if err := redirects.CheckLoop(rules, rule); err != nil {
+		writeRedirectError(w, err)
+		return false
+	}
+	return true
+}
+
+func writeRedirectError(w http.ResponseWriter, err error) {
+	body, _ := json.Marshal(map[string]string{"error": err.Error()})
+	http.Error(w, string(body), http.StatusBadRequest)
+}
+
+// RegisterAdminRedirectRoutes registers redirect rule and 404 report routes
+func RegisterAdminRedirectRoutes(r *mux.Router, s *server.Server) {
+	// GET /admin/redirects - List redirect rules with hit counts
+	r.HandleFunc("/redirects", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("redirects-handler")
+		ctx, span := tracer.Start(r.Context(), "ListRedirects")
+		defer span.End()
+
+		start := time.Now()
+		rules, err := s.DB.ListRedirects(ctx)
+		metrics.ObserveDBQueryDuration("list_redirects", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list redirects"}`, http.StatusInternalServerError)
+			return
+		}
+		if rules == nil {
+			rules = []db.Redirect{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(rules)
+	}).Methods("GET")
+
+	// POST /admin/redirects - Create a redirect rule
+	r.HandleFunc("/redirects", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("redirects-handler")
+		ctx, span := tracer.Start(r.Context(), "CreateRedirect")
+		defer span.End()
+
+		var in redirectInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+		rule, err := in.rule()
+		if err != nil {
+			writeRedirectError(w, err)
+			return
+		}
+		if !checkRedirectLoop(w, r, s, rule) {
+			return
+		}
+
+		var created db.Redirect
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			var err error
+			created, err = q.CreateRedirect(ctx, db.CreateRedirectParams{
+				Source:     rule.Source,
+				Target:     rule.Target,
+				StatusCode: int32(rule.StatusCode),
+			})
+			if err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.RedirectsUpdated, nil)
+		})
+		metrics.ObserveDBQueryDuration("create_redirect", time.Since(start).Seconds())
+		if isUniqueViolation(err) {
+			http.Error(w, `{"error":"A redirect for this source already exists"}`, http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to create redirect"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(created)
+	}).Methods("POST")
+
+	// PUT /admin/redirects/{id} - Replace a redirect rule
+	r.HandleFunc("/redirects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("redirects-handler")
+		ctx, span := tracer.Start(r.Context(), "UpdateRedirect")
+		defer span.End()
+
+		id64, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid ID"}`, http.StatusBadRequest)
+			return
+		}
+		var in redirectInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+		rule, err := in.rule()
+		if err != nil {
+			writeRedirectError(w, err)
+			return
+		}
+		rule.ID = int32(id64)
+		if !checkRedirectLoop(w, r, s, rule) {
+			return
+		}
+
+		var updated db.Redirect
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			var err error
+			updated, err = q.UpdateRedirect(ctx, db.UpdateRedirectParams{
+				ID:         int32(id64),
+				Source:     rule.Source,
+				Target:     rule.Target,
+				StatusCode: int32(rule.StatusCode),
+			})
+			if err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.RedirectsUpdated, nil)
+		})
+		metrics.ObserveDBQueryDuration("update_redirect", time.Since(start).Seconds())
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Redirect not found"}`, http.StatusNotFound)
+			return
+		} else if isUniqueViolation(err) {
+			http.Error(w, `{"error":"A redirect for this source already exists"}`, http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to update redirect"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(updated)
+	}).Methods("PUT")
+
+	// DELETE /admin/redirects/{id} - Delete a redirect rule
+	r.HandleFunc("/redirects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("redirects-handler")
+		ctx, span := tracer.Start(r.Context(), "DeleteRedirect")
+		defer span.End()
+
+		id64, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid ID"}`, http.StatusBadRequest)
+			return
+		}
+
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			deleted, err := q.DeleteRedirect(ctx, int32(id64))
+			if err != nil {
+				return err
+			}
+			if deleted == 0 {
+				return sql.ErrNoRows
+			}
+			return out.Add(ctx, bus.RedirectsUpdated, nil)
+		})
+		metrics.ObserveDBQueryDuration("delete_redirect", time.Since(start).Seconds())
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Redirect not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to delete redirect"}`, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+
+	// GET /admin/not-found - Paths that served 404s (?sort=-hits&filter[path][contains]=blog)
+	r.HandleFunc("/not-found", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("redirects-handler")
+		ctx, span := tracer.Start(r.Context(), "ListNotFoundPaths")
+		defer span.End()
+
+		lq, err := listquery.Parse(r.URL.Query(), notFoundListSpec)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+		query, args := lq.Build("SELECT "+db.NotFoundPathColumns+" FROM not_found_paths", nil, nil)
+
+		start := time.Now()
+		paths, err := s.DB.QueryNotFoundPaths(ctx, query, args...)
+		metrics.ObserveDBQueryDuration("list_not_found_paths", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list 404s"}`, http.StatusInternalServerError)
+			return
+		}
+		if paths == nil {
+			paths = []db.NotFoundPath{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(paths)
+	}).Methods("GET")
+
+	// POST /admin/not-found/redirect - Turn a 404 path into a redirect {"path","target","status_code"}
+	r.HandleFunc("/not-found/redirect", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("redirects-handler")
+		ctx, span := tracer.Start(r.Context(), "RedirectNotFoundPath")
+		defer span.End()
+
+		var body struct {
+			Path       string `json:"path"`
+			Target     string `json:"target"`
+			StatusCode int    `json:"status_code"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+		rule, err := redirectInput{Source: body.Path, Target: body.Target, StatusCode: body.StatusCode}.rule()
+		if err != nil {
+			writeRedirectError(w, err)
+			return
+		}
+
+		var created db.Redirect
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			var err error
+			created, err = q.CreateRedirect(ctx, db.CreateRedirectParams{
+				Source:     rule.Source,
+				Target:     rule.Target,
+				StatusCode: int32(rule.StatusCode),
+			})
+			if err != nil {
+				return err
+			}
+			if _, err := q.DeleteNotFoundPath(ctx, rule.Source); err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.RedirectsUpdated, nil)
+		})
+		metrics.ObserveDBQueryDuration("redirect_not_found_path", time.Since(start).Seconds())
+		if isUniqueViolation(err) {
+			http.Error(w, `{"error":"A redirect for this source already exists"}`, http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to create redirect"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(created)
+	}).Methods("POST")
+
+	// DELETE /admin/not-found?path=/x - Dismiss a 404 entry
+	r.HandleFunc("/not-found", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("redirects-handler")
+		ctx, span := tracer.Start(r.Context(), "DeleteNotFoundPath")
+		defer span.End()
+
+		path := r.URL.Query().Get("path")
+		if path == "" {
+			http.Error(w, `{"error":"path is required"}`, http.StatusBadRequest)
+			return
+		}
+
+		start := time.Now()
+		deleted, err := s.DB.DeleteNotFoundPath(ctx, path)
+		metrics.ObserveDBQueryDuration("delete_not_found_path", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to delete 404 entry"}`, http.StatusInternalServerError)
+			return
+		}
+		if deleted == 0 {
+			http.Error(w, `{"error":"Path not found"}`, http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+}
+
+// SubscribeRedirectInvalidation reloads the redirect table when rules change
+// on this or any other instance.
+func SubscribeRedirectInvalidation(s *server.Server) {
+	s.Bus.Subscribe(bus.RedirectsUpdated, func(ctx context.Context, e bus.Event) error {
+		s.Redirects.Invalidate()
+		return nil
+	})
+}
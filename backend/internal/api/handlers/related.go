@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/related"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+)
+
+// defaultRelatedLimit is the size of a "you may also like" block.
+const defaultRelatedLimit = 5
+
+// RegisterRelatedRoutes registers the public related-content routes
+func RegisterRelatedRoutes(r *mux.Router, s *server.Server) {
+	// GET /posts/{slug}/related?limit=5 - Related posts and projects
+	r.HandleFunc("/posts/{slug}/related", func(w http.ResponseWriter, r *http.Request) {
+		serveRelated(w, r, s, related.TypePost)
+	}).Methods("GET")
+
+	// GET /projects/{slug}/related?limit=5
+	r.HandleFunc("/projects/{slug}/related", func(w http.ResponseWriter, r *http.Request) {
+		serveRelated(w, r, s, related.TypeProject)
+	}).Methods("GET")
+}
+
+func serveRelated(w http.ResponseWriter, r *http.Request, s *server.Server, typ string) {
+	tracer := otel.Tracer("related-handler")
+	ctx, span := tracer.Start(r.Context(), "GetRelated")
+	defer span.End()
+
+	limit := defaultRelatedLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > related.MaxLimit {
+			http.Error(w, `{"error":"limit must be between 1 and 20"}`, http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	start := time.Now()
+	results, found, err := s.Related.Related(ctx, typ, mux.Vars(r)["slug"], limit)
+	metrics.ObserveDBQueryDuration("get_related", time.Since(start).Seconds())
+	if err != nil {
+		http.Error(w, `{"error":"Failed to fetch related content"}`, http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, `{"error":"Not found"}`, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(results)
+}
+
+// resolveRelatedItem finds the ID of a post or project by slug.
+func resolveRelatedItem(ctx context.Context, s *server.Server, typ, slug string) (int32, error) {
+	if typ == related.TypePost {
+		p, err := s.DB.GetPostBySlug(ctx, slug)
+		return p.ID, err
+	}
+	p, err := s.DB.GetProjectBySlug(ctx, slug)
+	return p.ID, err
+}
+
+// RegisterAdminRelatedRoutes registers pin/exclude override routes
+func RegisterAdminRelatedRoutes(r *mux.Router, s *server.Server) {
+	// GET /admin/related/overrides - List overrides
+	r.HandleFunc("/related/overrides", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("related-handler")
+		ctx, span := tracer.Start(r.Context(), "ListRelatedOverrides")
+		defer span.End()
+
+		start := time.Now()
+		overrides, err := s.DB.ListRelatedOverrides(ctx)
+		metrics.ObserveDBQueryDuration("list_related_overrides", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list overrides"}`, http.StatusInternalServerError)
+			return
+		}
+		if overrides == nil {
+			overrides = []db.RelatedOverride{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(overrides)
+	}).Methods("GET")
+
+	// POST /admin/related/overrides - Pin or exclude a relation (replaces an existing one)
+	r.HandleFunc("/related/overrides", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("related-handler")
+		ctx, span := tracer.Start(r.Context(), "CreateRelatedOverride")
+		defer span.End()
+
+		var body struct {
+			SourceType string `json:"source_type"`
+			SourceSlug string `json:"source_slug"`
+			TargetType string `json:"target_type"`
+			TargetSlug string `json:"target_slug"`
+			Action     string `json:"action"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+		if !related.ValidType(body.SourceType) || !related.ValidType(body.TargetType) {
+			http.Error(w, `{"error":"source_type and target_type must be post or project"}`, http.StatusBadRequest)
+			return
+		}
+		if !related.ValidAction(body.Action) {
+			http.Error(w, `{"error":"action must be pin or exclude"}`, http.StatusBadRequest)
+			return
+		}
+		if body.SourceType == body.TargetType && body.SourceSlug == body.TargetSlug {
+			http.Error(w, `{"error":"An item cannot be related to itself"}`, http.StatusBadRequest)
+			return
+		}
+
+		sourceID, err := resolveRelatedItem(ctx, s, body.SourceType, body.SourceSlug)
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Source not found"}`, http.StatusBadRequest)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to save override"}`, http.StatusInternalServerError)
+			return
+		}
+		targetID, err := resolveRelatedItem(ctx, s, body.TargetType, body.TargetSlug)
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Target not found"}`, http.StatusBadRequest)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to save override"}`, http.StatusInternalServerError)
+			return
+		}
+
+		var override db.RelatedOverride
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			var err error
+			override, err = q.CreateRelatedOverride(ctx, db.CreateRelatedOverrideParams{
+				SourceType: body.SourceType,
+				SourceID:   sourceID,
+				TargetType: body.TargetType,
+				TargetID:   targetID,
+				Action:     body.Action,
+			})
+			if err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.RelatedOverridesUpdated, nil)
+		})
+		metrics.ObserveDBQueryDuration("create_related_override", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to save override"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(override)
+	}).Methods("POST")
+
+	// DELETE /admin/related/overrides/{id} - Remove an override
+	r.HandleFunc("/related/overrides/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("related-handler")
+		ctx, span := tracer.Start(r.Context(), "DeleteRelatedOverride")
+		defer span.End()
+
+		id64, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid ID"}`, http.StatusBadRequest)
+			return
+		}
+
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			deleted, err := q.DeleteRelatedOverride(ctx, int32(id64))
+			if err != nil {
+				return err
+			}
+			if deleted == 0 {
+				return sql.ErrNoRows
+			}
+			return out.Add(ctx, bus.RelatedOverridesUpdated, nil)
+		})
+		metrics.ObserveDBQueryDuration("delete_related_override", time.Since(start).Seconds())
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Override not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to delete override"}`, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+}
+
+// SubscribeRelatedInvalidation drops the related-content index when content
+// or overrides change on this or any other instance.
+func SubscribeRelatedInvalidation(s *server.Server) {
+	s.Bus.Subscribe(bus.All, func(ctx context.Context, e bus.Event) error {
+		switch e.Name {
+		case bus.PostPublished, bus.PostUpdated, bus.PostDeleted,
+			bus.ProjectUpdated, bus.ProjectDeleted, bus.ProjectsImported,
+			bus.RelatedOverridesUpdated:
+			s.Related.Invalidate()
+		}
+		return nil
+	})
+}
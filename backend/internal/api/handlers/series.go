@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/respcache"
+	"github.com/onnwee/onnwee.github.io/backend/internal/series"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
+)
+
+// seriesLink points at a neighbouring post in a series.
+type seriesLink struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+// seriesContext is embedded in GET /posts/{slug} for posts in a series.
+type seriesContext struct {
+	ID       int32       `json:"id"`
+	Title    string      `json:"title"`
+	Slug     string      `json:"slug"`
+	Position int         `json:"position"`
+	Total    int         `json:"total"`
+	Prev     *seriesLink `json:"prev"`
+	Next     *seriesLink `json:"next"`
+}
+
+// seriesPost is a post listed in a series, numbered from 1.
+type seriesPost struct {
+	Position int `json:"position"`
+	db.Post
+}
+
+type seriesResponse struct {
+	db.Series
+	Posts []seriesPost `json:"posts"`
+}
+
+func linkTo(p *db.Post) *seriesLink {
+	if p == nil {
+		return nil
+	}
+	return &seriesLink{Slug: p.Slug, Title: p.Title}
+}
+
+// loadSeriesContext returns the series context for a post, or nil when the
+// post is not a published part of a series, and the newest updated_at of the
+// series and its neighbouring posts.
+func loadSeriesContext(ctx context.Context, s *server.Server, post db.Post) (*seriesContext, time.Time, error) {
+	start := time.Now()
+	sr, err := s.DB.GetSeriesByPost(ctx, post.ID)
+	metrics.ObserveDBQueryDuration("get_series_by_post", time.Since(start).Seconds())
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	} else if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	start = time.Now()
+	posts, err := s.DB.ListSeriesPosts(ctx, sr.ID)
+	metrics.ObserveDBQueryDuration("list_series_posts", time.Since(start).Seconds())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	nav, ok := series.Navigate(posts, post.ID)
+	if !ok {
+		return nil, time.Time{}, nil
+	}
+	respcache.Tag(ctx, seriesTag(sr.ID))
+	modified := sr.UpdatedAt.Time
+	for _, p := range []*db.Post{nav.Prev, nav.Next} {
+		if p != nil {
+			respcache.Tag(ctx, postTag(p.ID))
+			modified = latest(modified, p.UpdatedAt.Time)
+		}
+	}
+	return &seriesContext{
+		ID:       sr.ID,
+		Title:    sr.Title,
+		Slug:     sr.Slug,
+		Position: nav.Position,
+		Total:    nav.Total,
+		Prev:     linkTo(nav.Prev),
+		Next:     linkTo(nav.Next),
+	}, modified, nil
+}
+
+// RegisterSeriesRoutes registers the public series routes
+func RegisterSeriesRoutes(r *mux.Router, s *server.Server) {
+	// GET /series - List series
+	r.HandleFunc("/series", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("series-handler")
+		ctx, span := tracer.Start(r.Context(), "ListSeries")
+		defer span.End()
+
+		start := time.Now()
+		list, err := s.DB.ListSeries(ctx)
+		metrics.ObserveDBQueryDuration("list_series", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list series"}`, http.StatusInternalServerError)
+			return
+		}
+		if list == nil {
+			list = []db.Series{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(list)
+	}).Methods("GET")
+
+	// GET /series/{slug} - A series with its published posts in order
+	r.HandleFunc("/series/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("series-handler")
+		ctx, span := tracer.Start(r.Context(), "GetSeriesBySlug")
+		defer span.End()
+
+		start := time.Now()
+		sr, err := s.DB.GetSeriesBySlug(ctx, mux.Vars(r)["slug"])
+		metrics.ObserveDBQueryDuration("get_series_by_slug", time.Since(start).Seconds())
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Series not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to fetch series"}`, http.StatusInternalServerError)
+			return
+		}
+
+		start = time.Now()
+		posts, err := s.DB.ListSeriesPosts(ctx, sr.ID)
+		metrics.ObserveDBQueryDuration("list_series_posts", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to fetch series posts"}`, http.StatusInternalServerError)
+			return
+		}
+
+		resp := seriesResponse{Series: sr, Posts: []seriesPost{}}
+		for i, p := range series.Published(posts) {
+			resp.Posts = append(resp.Posts, seriesPost{Position: i + 1, Post: p})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}).Methods("GET")
+}
+
+// RegisterAdminSeriesRoutes registers admin-only series management routes
+func RegisterAdminSeriesRoutes(r *mux.Router, s *server.Server) {
+	type seriesPayload struct {
+		Title       string  `json:"title"`
+		Slug        string  `json:"slug"`
+		Description *string `json:"description"`
+	}
+
+	seriesSlugTaken := func(ctx context.Context, slug string) (bool, error) {
+		_, err := s.DB.GetSeriesBySlug(ctx, slug)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	// POST /admin/series - Create a series (slug generated from the title if omitted)
+	r.HandleFunc("/series", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("series-handler")
+		ctx, span := tracer.Start(r.Context(), "CreateSeries")
+		defer span.End()
+
+		var body seriesPayload
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+		if body.Title == "" {
+			http.Error(w, `{"error":"title is required"}`, http.StatusBadRequest)
+			return
+		}
+		slug := body.Slug
+		if slug == "" {
+			var err error
+			if slug, err = utils.UniqueSlug(ctx, utils.Slugify(body.Title), seriesSlugTaken); err != nil {
+				http.Error(w, `{"error":"Failed to create series"}`, http.StatusInternalServerError)
+				return
+			}
+		}
+		if !validSlug(slug) {
+			writeSlugError(w, errSlugInvalid)
+			return
+		}
+
+		start := time.Now()
+		created, err := s.DB.CreateSeries(ctx, db.CreateSeriesParams{
+			Title:       body.Title,
+			Slug:        slug,
+			Description: utils.ToNullString(body.Description),
+		})
+		metrics.ObserveDBQueryDuration("create_series", time.Since(start).Seconds())
+		if isUniqueViolation(err) {
+			writeSlugError(w, errSlugTaken)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to create series"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(created)
+	}).Methods("POST")
+
+	// PUT /admin/series/{id} - Update a series
+	r.HandleFunc("/series/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("series-handler")
+		ctx, span := tracer.Start(r.Context(), "UpdateSeries")
+		defer span.End()
+
+		id64, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid ID"}`, http.StatusBadRequest)
+			return
+		}
+		var body seriesPayload
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+		if body.Title == "" {
+			http.Error(w, `{"error":"title is required"}`, http.StatusBadRequest)
+			return
+		}
+
+		var updated db.Series
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			current, err := q.GetSeriesByID(ctx, int32(id64))
+			if err != nil {
+				return err
+			}
+			if body.Slug == "" {
+				body.Slug = current.Slug
+			}
+			if !validSlug(body.Slug) {
+				return errSlugInvalid
+			}
+			updated, err = q.UpdateSeries(ctx, db.UpdateSeriesParams{
+				ID:          current.ID,
+				Title:       body.Title,
+				Slug:        body.Slug,
+				Description: utils.ToNullString(body.Description),
+			})
+			if isUniqueViolation(err) {
+				return errSlugTaken
+			} else if err != nil {
+				return err
+			}
+			return out.Add(ctx, bus.SeriesUpdated, bus.Change{SeriesIDs: []int32{updated.ID}})
+		})
+		metrics.ObserveDBQueryDuration("update_series", time.Since(start).Seconds())
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Series not found"}`, http.StatusNotFound)
+			return
+		} else if writeSlugError(w, err) {
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to update series"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(updated)
+	}).Methods("PUT")
+
+	// DELETE /admin/series/{id} - Delete a series (its posts are kept)
+	r.HandleFunc("/series/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("series-handler")
+		ctx, span := tracer.Start(r.Context(), "DeleteSeries")
+		defer span.End()
+
+		id64, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid ID"}`, http.StatusBadRequest)
+			return
+		}
+
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			deleted, err := q.DeleteSeries(ctx, int32(id64))
+			if err != nil {
+				return err
+			}
+			if deleted == 0 {
+				return sql.ErrNoRows
+			}
+			return out.Add(ctx, bus.SeriesUpdated, bus.Change{SeriesIDs: []int32{int32(id64)}})
+		})
+		metrics.ObserveDBQueryDuration("delete_series", time.Since(start).Seconds())
+		if err == sql.ErrNoRows {
+			http.Error(w, `{"error":"Series not found"}`, http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, `{"error":"Failed to delete series"}`, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+
+	// PUT /admin/series/{id}/posts - Replace the ordered membership {"post_ids":[3,1,2]}
+	r.HandleFunc("/series/{id}/posts", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("series-handler")
+		ctx, span := tracer.Start(r.Context(), "SetSeriesPosts")
+		defer span.End()
+
+		id64, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 32)
+		if err != nil {
+			http.Error(w, `{"error":"Invalid ID"}`, http.StatusBadRequest)
+			return
+		}
+		var body struct {
+			PostIDs []int32 `json:"post_ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+		seen := make(map[int32]bool, len(body.PostIDs))
+		for _, pid := range body.PostIDs {
+			if seen[pid] {
+				http.Error(w, `{"error":"post_ids must not repeat"}`, http.StatusBadRequest)
+				return
+			}
+			seen[pid] = true
+		}
+
+		var sr db.Series
+		var posts []db.Post
+		start := time.Now()
+		err = s.WithEvents(ctx, func(q *db.Queries, out *bus.Outbox) error {
+			var err error
+			if sr, err = q.GetSeriesByID(ctx, int32(id64)); err != nil {
+				return err
+			}
+			// Former members are tagged with the series; new ones may not be
+			change := bus.Change{SeriesIDs: []int32{sr.ID}}
+			if err := q.ClearSeriesPosts(ctx, sr.ID); err != nil {
+				return err
+			}
+			for i, pid := range body.PostIDs {
+				err := q.AddSeriesPost(ctx, db.AddSeriesPostParams{SeriesID: sr.ID, PostID: pid, Position: int32(i + 1)})
+				if err != nil {
+					return err
+				}
+			}
+			if posts, err = q.ListSeriesPosts(ctx, sr.ID); err != nil {
+				return err
+			}
+			for _, p := range posts {
+				change.PostIDs = append(change.PostIDs, p.ID)
+			}
+			return out.Add(ctx, bus.SeriesUpdated, change)
+		})
+		metrics.ObserveDBQueryDuration("set_series_posts", time.Since(start).Seconds())
+
+		switch {
+		case err == sql.ErrNoRows:
+			http.Error(w, `{"error":"Series not found"}`, http.StatusNotFound)
+			return
+		case isUniqueViolation(err):
+			http.Error(w, `{"error":"A post is already part of another series"}`, http.StatusConflict)
+			return
+		case isForeignKeyViolation(err):
+			http.Error(w, `{"error":"Unknown post ID"}`, http.StatusBadRequest)
+			return
+		case err != nil:
+			http.Error(w, `{"error":"Failed to update series posts"}`, http.StatusInternalServerError)
+			return
+		}
+
+		// Admins see drafts too, numbered by their stored position
+		resp := seriesResponse{Series: sr, Posts: make([]seriesPost, 0, len(posts))}
+		for i, p := range posts {
+			resp.Posts = append(resp.Posts, seriesPost{Position: i + 1, Post: p})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}).Methods("PUT")
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
+)
+
+var (
+	errSlugTaken   = errors.New("slug already in use")
+	errSlugInvalid = errors.New("invalid slug")
+)
+
+// slugStore abstracts over the post and project slug history queries.
+type slugStore struct {
+	taken   func(ctx context.Context, q *db.Queries, slug string) (bool, error)
+	history func(ctx context.Context, q *db.Queries, id int32) ([]string, error)
+	forget  func(ctx context.Context, q *db.Queries, slug string) error
+	record  func(ctx context.Context, q *db.Queries, oldSlug string, id int32) error
+	resolve func(ctx context.Context, q *db.Queries, oldSlug string) (string, error)
+}
+
+var postSlugs = slugStore{
+	taken: func(ctx context.Context, q *db.Queries, slug string) (bool, error) {
+		return q.PostSlugTaken(ctx, slug)
+	},
+	history: func(ctx context.Context, q *db.Queries, id int32) ([]string, error) {
+		return q.ListPostSlugRedirects(ctx, sql.NullInt32{Int32: id, Valid: true})
+	},
+	forget: func(ctx context.Context, q *db.Queries, slug string) error {
+		return q.DeletePostSlugRedirect(ctx, slug)
+	},
+	record: func(ctx context.Context, q *db.Queries, oldSlug string, id int32) error {
+		return q.CreatePostSlugRedirect(ctx, db.CreatePostSlugRedirectParams{OldSlug: oldSlug, PostID: sql.NullInt32{Int32: id, Valid: true}})
+	},
+	resolve: func(ctx context.Context, q *db.Queries, oldSlug string) (string, error) {
+		return q.ResolvePostSlugRedirect(ctx, oldSlug)
+	},
+}
+
+var projectSlugs = slugStore{
+	taken: func(ctx context.Context, q *db.Queries, slug string) (bool, error) {
+		return q.ProjectSlugTaken(ctx, slug)
+	},
+	history: func(ctx context.Context, q *db.Queries, id int32) ([]string, error) {
+		return q.ListProjectSlugRedirects(ctx, sql.NullInt32{Int32: id, Valid: true})
+	},
+	forget: func(ctx context.Context, q *db.Queries, slug string) error {
+		return q.DeleteProjectSlugRedirect(ctx, slug)
+	},
+	record: func(ctx context.Context, q *db.Queries, oldSlug string, id int32) error {
+		return q.CreateProjectSlugRedirect(ctx, db.CreateProjectSlugRedirectParams{OldSlug: oldSlug, ProjectID: sql.NullInt32{Int32: id, Valid: true}})
+	},
+	resolve: func(ctx context.Context, q *db.Queries, oldSlug string) (string, error) {
+		return q.ResolveProjectSlugRedirect(ctx, oldSlug)
+	},
+}
+
+// validSlug reports whether s is already in canonical slug form.
+func validSlug(s string) bool {
+	return s != "" && utils.Slugify(s) == s
+}
+
+// forCreate returns the slug for a new record: the requested one if it is
+// valid and free, or one generated from the title with a -2, -3, ... suffix.
+func (st slugStore) forCreate(ctx context.Context, q *db.Queries, requested, title string) (string, error) {
+	if requested == "" {
+		base := utils.Slugify(title)
+		if base == "" {
+			return "", errSlugInvalid
+		}
+		return utils.UniqueSlug(ctx, base, func(ctx context.Context, s string) (bool, error) {
+			return st.taken(ctx, q, s)
+		})
+	}
+	if !validSlug(requested) {
+		return "", errSlugInvalid
+	}
+	taken, err := st.taken(ctx, q, requested)
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return "", errSlugTaken
+	}
+	return requested, nil
+}
+
+// claim prepares record id to move to slug next. A slug from the
+// record's own history may be reused; anything else must be free.
+func (st slugStore) claim(ctx context.Context, q *db.Queries, id int32, next string) error {
+	if !validSlug(next) {
+		return errSlugInvalid
+	}
+	history, err := st.history(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	for _, old := range history {
+		if old == next {
+			if err := st.forget(ctx, q, next); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	taken, err := st.taken(ctx, q, next)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errSlugTaken
+	}
+	return nil
+}
+
+// redirect writes a 301 to the canonical slug if slug is a former one and
+// reports whether it did.
+func (st slugStore) redirect(w http.ResponseWriter, r *http.Request, q *db.Queries, slug string) (bool, error) {
+	start := time.Now()
+	canonical, err := st.resolve(r.Context(), q, slug)
+	metrics.ObserveDBQueryDuration("resolve_slug_redirect", time.Since(start).Seconds())
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// Relative, so it resolves correctly behind any path prefix
+	w.Header().Set("Location", url.PathEscape(canonical))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusMovedPermanently)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"redirect": true,
+		"slug":     canonical,
+	})
+	return true, nil
+}
+
+// writeSlugError maps slug errors to 400/409, returning false for other errors.
+func writeSlugError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, errSlugInvalid):
+		http.Error(w, `{"error":"slug must be lowercase letters, digits and single hyphens (or a title to generate one from)"}`, http.StatusBadRequest)
+	case errors.Is(err, errSlugTaken):
+		http.Error(w, `{"error":"Slug already in use"}`, http.StatusConflict)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+	"github.com/onnwee/onnwee.github.io/backend/internal/respcache"
+	"github.com/onnwee/onnwee.github.io/backend/internal/server"
+	"go.opentelemetry.io/otel"
+)
+
+// tagResponse lists everything published under a single tag.
+type tagResponse struct {
+	Tag      string            `json:"tag"`
+	Posts    []db.Post         `json:"posts"`
+	Projects []projectResponse `json:"projects"`
+}
+
+// RegisterTagRoutes registers read-only tag routes
+func RegisterTagRoutes(r *mux.Router, s *server.Server) {
+	// GET /tags - List tags with post and project counts
+	r.HandleFunc("/tags", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("tags-handler")
+		ctx, span := tracer.Start(r.Context(), "ListTags")
+		defer span.End()
+
+		start := time.Now()
+		tags, err := s.DB.ListTags(ctx)
+		metrics.ObserveDBQueryDuration("list_tags", time.Since(start).Seconds())
+
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list tags"}`, http.StatusInternalServerError)
+			return
+		}
+		if tags == nil {
+			tags = []db.ListTagsRow{}
+		}
+		respcache.Tag(ctx, postsTag, projectsTag)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(tags)
+	}).Methods("GET")
+
+	// GET /tags/{tag} - Published posts and projects carrying a tag
+	r.HandleFunc("/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("tags-handler")
+		ctx, span := tracer.Start(r.Context(), "GetTag")
+		defer span.End()
+
+		tag := mux.Vars(r)["tag"]
+
+		start := time.Now()
+		posts, err := s.DB.ListPostsByTag(ctx, tag)
+		metrics.ObserveDBQueryDuration("list_posts_by_tag", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list posts"}`, http.StatusInternalServerError)
+			return
+		}
+
+		start = time.Now()
+		projects, err := s.DB.ListProjectsByTag(ctx, tag)
+		metrics.ObserveDBQueryDuration("list_projects_by_tag", time.Since(start).Seconds())
+		if err != nil {
+			http.Error(w, `{"error":"Failed to list projects"}`, http.StatusInternalServerError)
+			return
+		}
+
+		if len(posts) == 0 && len(projects) == 0 {
+			http.Error(w, `{"error":"Tag not found"}`, http.StatusNotFound)
+			return
+		}
+
+		respcache.Tag(ctx, postsTag, projectsTag)
+		resp := tagResponse{Tag: tag, Posts: posts, Projects: make([]projectResponse, 0, len(projects))}
+		if resp.Posts == nil {
+			resp.Posts = []db.Post{}
+		}
+		for _, p := range projects {
+			resp.Projects = append(resp.Projects, toProjectResponse(p))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}).Methods("GET")
+}
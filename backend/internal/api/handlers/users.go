@@ -1,17 +1,80 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/listquery"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
 	"github.com/onnwee/onnwee.github.io/backend/internal/server"
 	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
 )
 
+// userListSpec is the sort/filter allowlist for GET /users.
+var userListSpec = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"id":         {Column: "id", Type: listquery.Int, Sortable: true, Filterable: true},
+		"username":   {Column: "username", Type: listquery.Text, Sortable: true, Filterable: true},
+		"created_at": {Column: "created_at", Type: listquery.Time, Sortable: true, Filterable: true},
+		"updated_at": {Column: "updated_at", Type: listquery.Time, Sortable: true, Filterable: true},
+	},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 10,
+}
+
+// userFields is the ?fields= allowlist for user responses and their default
+// projection, so password_hash is never sent.
+var userFields = []string{"id", "username", "email", "created_at", "updated_at"}
+
+// publicUser is the owner profile embedded via ?include=author.
+type publicUser struct {
+	ID       int32  `json:"id"`
+	Username string `json:"username"`
+
+	updatedAt time.Time // for Last-Modified of the embedding response
+}
+
+// loadAuthors fetches the owners of the given rows in one query, keyed by user ID.
+func loadAuthors(ctx context.Context, s *server.Server, userIDs []sql.NullInt32) (map[int32]*publicUser, error) {
+	seen := make(map[int32]bool, len(userIDs))
+	ids := make([]int32, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id.Valid && !seen[id.Int32] {
+			seen[id.Int32] = true
+			ids = append(ids, id.Int32)
+		}
+	}
+	authors := make(map[int32]*publicUser, len(ids))
+	if len(ids) == 0 {
+		return authors, nil
+	}
+
+	start := time.Now()
+	users, err := s.DB.GetUsersByIDs(ctx, ids)
+	metrics.ObserveDBQueryDuration("get_users_by_ids", time.Since(start).Seconds())
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		authors[u.ID] = &publicUser{ID: u.ID, Username: u.Username, updatedAt: u.UpdatedAt.Time}
+	}
+	return authors, nil
+}
+
+// authorOf returns the embedded author for a row, or nil when it has none.
+func authorOf(authors map[int32]*publicUser, userID sql.NullInt32) *publicUser {
+	if !userID.Valid {
+		return nil
+	}
+	return authors[userID.Int32]
+}
+
 func RegisterUserRoutes(r *mux.Router, s *server.Server) {
 	// POST /users - Create a user with uniqueness checks
 	r.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
@@ -37,42 +100,51 @@ func RegisterUserRoutes(r *mux.Router, s *server.Server) {
 			return
 		}
 
+		resp, err := listquery.Select(user, userFields)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to encode user"}`, http.StatusInternalServerError)
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
-		_ = json.NewEncoder(w).Encode(user)
+		_ = json.NewEncoder(w).Encode(resp)
 	}).Methods("POST")
 
-	// GET /users - List users with pagination
+	// GET /users - List users (?sort=&filter[field][op]=&limit=&offset=)
 	r.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
-		query := r.URL.Query()
-
-		limit := int32(10) // default
-		if limitStr := query.Get("limit"); limitStr != "" {
-			if limit64, err := strconv.ParseInt(limitStr, 10, 32); err == nil && limit64 > 0 {
-				limit = int32(limit64)
-			}
+		lq, err := listquery.Parse(r.URL.Query(), userListSpec)
+		if err != nil {
+			writeQueryError(w, err)
+			return
 		}
-
-		offset := int32(0) // default
-		if offsetStr := query.Get("offset"); offsetStr != "" {
-			if offset64, err := strconv.ParseInt(offsetStr, 10, 32); err == nil && offset64 >= 0 {
-				offset = int32(offset64)
-			}
+		fields, err := listquery.ParseFields(r.URL.Query(), userFields)
+		if err != nil {
+			writeQueryError(w, err)
+			return
 		}
-
-		params := db.ListUsersParams{
-			Limit:  limit,
-			Offset: offset,
+		if fields == nil {
+			fields = userFields
 		}
+		query, args := lq.Build("SELECT "+db.UserColumns+" FROM users", nil, nil)
 
-		users, err := s.DB.ListUsers(r.Context(), params)
+		users, err := s.DB.QueryUsers(r.Context(), query, args...)
 		if err != nil {
 			http.Error(w, `{"error":"Failed to fetch users"}`, http.StatusInternalServerError)
 			return
 		}
+		if users == nil {
+			users = []db.User{}
+		}
+
+		resp, err := listquery.Select(users, fields)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to encode users"}`, http.StatusInternalServerError)
+			return
+		}
 
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(users)
+		_ = json.NewEncoder(w).Encode(resp)
 	}).Methods("GET")
 
 	// GET /users/{id} - Get user by ID
@@ -85,6 +157,15 @@ func RegisterUserRoutes(r *mux.Router, s *server.Server) {
 		}
 		id := int32(id64)
 
+		fields, err := listquery.ParseFields(r.URL.Query(), userFields)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+		if fields == nil {
+			fields = userFields
+		}
+
 		user, err := s.DB.GetUserByID(r.Context(), id)
 		if err == sql.ErrNoRows {
 			http.Error(w, `{"error":"User not found"}`, http.StatusNotFound)
@@ -94,8 +175,14 @@ func RegisterUserRoutes(r *mux.Router, s *server.Server) {
 			return
 		}
 
+		resp, err := listquery.Select(user, fields)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to encode user"}`, http.StatusInternalServerError)
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(user)
+		_ = json.NewEncoder(w).Encode(resp)
 	}).Methods("GET")
 
 	// DELETE /users/{id} - Delete a user
@@ -154,8 +241,14 @@ func RegisterUserRoutes(r *mux.Router, s *server.Server) {
 			return
 		}
 
+		resp, err := listquery.Select(user, userFields)
+		if err != nil {
+			http.Error(w, `{"error":"Failed to encode user"}`, http.StatusInternalServerError)
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(user)
+		_ = json.NewEncoder(w).Encode(resp)
 	}).Methods("PATCH")
 
 	// GET /users/available?username=...
@@ -9,13 +9,10 @@ import (
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
-	"github.com/onnwee/onnwee.github.io/backend/internal/db"
 	"github.com/onnwee/onnwee.github.io/backend/internal/server"
 )
 
-func NewRouter(queries *db.Queries) http.Handler {
-	s := server.NewServer(queries)
-
+func NewRouter(s *server.Server) http.Handler {
 	r := mux.NewRouter()
 
 	// Public routes
@@ -24,21 +21,50 @@ func NewRouter(queries *db.Queries) http.Handler {
 	handlers.RegisterPageViewRoutes(r, s)
 	handlers.RegisterAnalyticsRoutes(r, s)
 	handlers.RegisterHealthRoutes(r, s)
-	handlers.RegisterPostRoutes(r, s)
+	handlers.RegisterCommentRoutes(r, s)
+	handlers.RegisterReactionRoutes(r, s)
 	handlers.RegisterUserRoutes(r, s)
+	handlers.RegisterSeriesRoutes(r, s)
+	handlers.RegisterRelatedRoutes(r, s)
+	handlers.RegisterMediaRoutes(r, s)
+	handlers.RegisterOGCardRoutes(r, s)
 
 	// Auth routes (rate-limited by default middleware)
 	handlers.RegisterAuthRoutes(r, s)
 
-	// Public project routes (GET only)
-	handlers.RegisterPublicProjectRoutes(r, s)
+	// Public content routes, served through the response cache. Writes
+	// registered alongside them pass straight through it.
+	cached := r.NewRoute().Subrouter()
+	cached.Use(s.Cache.Middleware)
+	handlers.RegisterPostRoutes(cached, s)
+	handlers.RegisterTagRoutes(cached, s)
+	handlers.RegisterFeedRoutes(cached, s)
+	handlers.RegisterPublicProjectRoutes(cached, s)
+	handlers.RegisterProjectUpdateRoutes(cached, s)
 
 	// Admin routes - protected by auth middleware
 	// These are mounted under /admin prefix
 	adminRouter := r.PathPrefix("/admin").Subrouter()
-	adminRouter.Use(middleware.RequireAuth(queries))
+	adminRouter.Use(middleware.RequireAuth(s.DB))
 	handlers.RegisterAdminProjectRoutes(adminRouter, s)
+	handlers.RegisterAdminPostRoutes(adminRouter, s)
+	handlers.RegisterAdminCommentRoutes(adminRouter, s)
+	handlers.RegisterAdminRedirectRoutes(adminRouter, s)
+	handlers.RegisterAdminSeriesRoutes(adminRouter, s)
+	handlers.RegisterAdminRelatedRoutes(adminRouter, s)
+	handlers.RegisterAdminPostProjectRoutes(adminRouter, s)
+	handlers.RegisterAdminProjectUpdateRoutes(adminRouter, s)
+	handlers.RegisterAdminMediaRoutes(adminRouter, s)
+
+	// Per-process state kept in step with the other instances
+	handlers.SubscribeCacheInvalidation(s)
+	handlers.SubscribeCardInvalidation(s)
+	handlers.SubscribeRelatedInvalidation(s)
+	handlers.SubscribeRedirectInvalidation(s)
+	limiter := newSharedRateLimiter(s)
 
-	base := middleware.Chain(r, middleware.Logging, middleware.Recovery, middleware.CORS, middleware.RealIP, middleware.Analytics(queries), middleware.RateLimit, middleware.Metrics)
+	// Admin redirects and 404 tracking apply to site paths only; see
+	// NewClientHandler
+	base := middleware.Chain(r, middleware.Logging, middleware.Recovery, middleware.CORS, middleware.Compress, middleware.RealIP, middleware.Analytics(s.DB), limiter.RateLimit, middleware.Metrics)
 	return otelhttp.NewHandler(base, "HTTPRouter")
 }
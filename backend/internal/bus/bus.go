@@ -0,0 +1,356 @@
+// Package bus is the domain-event bus shared by every server instance.
+// Events are written to the domain_events table through an Outbox, in the
+// transaction of the change they describe, so an event exists exactly when
+// its change committed. The table's trigger announces each row with Postgres
+// NOTIFY, which is sent on commit. Each instance LISTENs, reads the rows it
+// has not handled yet and passes them to its handlers, so delivery is at
+// least once: handlers must be idempotent.
+package bus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+)
+
+// Channel is the NOTIFY channel the domain_events trigger announces on.
+const Channel = "domain_events"
+
+// Event names.
+const (
+	PostPublished    = "post.published"
+	PostUpdated      = "post.updated"
+	PostDeleted      = "post.deleted"
+	ProjectUpdated   = "project.updated"
+	ProjectDeleted   = "project.deleted"
+	ProjectsImported = "projects.imported"
+	SeriesUpdated    = "series.updated"
+	// ReactionToggled carries the post or project whose counts moved.
+	ReactionToggled = "reaction.toggled"
+	// RelatedOverridesUpdated and RedirectsUpdated carry no payload: each
+	// instance reloads the whole table.
+	RelatedOverridesUpdated = "related.overrides_updated"
+	RedirectsUpdated        = "redirects.updated"
+	// VisitorRateLimited is published when an instance rate limits a visitor.
+	VisitorRateLimited = "visitor.rate_limited"
+
+	// All subscribes a handler to every event.
+	All = "*"
+)
+
+// Change is the payload of the post, project, series and reaction events: the
+// records that changed.
+type Change struct {
+	PostIDs    []int32 `json:"post_ids,omitempty"`
+	ProjectIDs []int32 `json:"project_ids,omitempty"`
+	SeriesIDs  []int32 `json:"series_ids,omitempty"`
+}
+
+// RateLimit is the payload of visitor.rate_limited. Visitor is a salted
+// hash of the IP address, so addresses are never stored.
+type RateLimit struct {
+	Visitor string    `json:"visitor"`
+	Until   time.Time `json:"until"`
+}
+
+// Event is one published event. ID is 0 when it could not be stored.
+type Event struct {
+	ID      int64
+	Name    string
+	Payload json.RawMessage
+	Origin  string
+	Time    time.Time
+}
+
+// Decode unmarshals the payload into v.
+func (e Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Handler reacts to an event. An error makes the bus deliver the event again
+// later, up to maxAttempts times.
+type Handler func(ctx context.Context, e Event) error
+
+// Store is the event table; *db.Queries implements it.
+type Store interface {
+	InsertDomainEvent(ctx context.Context, arg db.InsertDomainEventParams) (db.DomainEvent, error)
+	ListDomainEventsAfter(ctx context.Context, arg db.ListDomainEventsAfterParams) ([]db.DomainEvent, error)
+	GetLatestDomainEventID(ctx context.Context) (int64, error)
+	DeleteDomainEventsBefore(ctx context.Context, createdAt time.Time) (int64, error)
+}
+
+const (
+	batchSize   = 100
+	maxAttempts = 5
+	// pollInterval re-reads the table in case a notification was lost.
+	pollInterval = 30 * time.Second
+	// gapTimeout is how long a missing ID is waited for before it is taken
+	// as a rolled-back insert. IDs are assigned before commit, so a later
+	// event can become visible first.
+	gapTimeout = time.Minute
+	// retention is how long events are kept for instances to catch up.
+	retention = 24 * time.Hour
+
+	minReconnect = time.Second
+	maxReconnect = time.Minute
+)
+
+// Bus publishes events and delivers them to handlers. A nil *Bus drops
+// everything, for tools that run without one.
+type Bus struct {
+	store  Store
+	origin string
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+
+	// Delivery state, owned by Run. Every ID up to cursor has been
+	// handled; done holds the handled IDs above it, left by a gap.
+	cursor   int64
+	done     map[int64]bool
+	gapSince time.Time
+	attempts int
+}
+
+// New creates a bus over store with a random origin identifying this
+// instance.
+func New(store Store) *Bus {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return &Bus{
+		store:    store,
+		origin:   hex.EncodeToString(b),
+		now:      time.Now,
+		handlers: map[string][]Handler{},
+		done:     map[int64]bool{},
+	}
+}
+
+// Subscribe registers h for events called name, or for every event with All.
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.mu.Lock()
+	b.handlers[name] = append(b.handlers[name], h)
+	b.mu.Unlock()
+}
+
+// Inserter stores an event; a transaction-scoped *db.Queries implements it.
+type Inserter interface {
+	InsertDomainEvent(ctx context.Context, arg db.InsertDomainEventParams) (db.DomainEvent, error)
+}
+
+// Outbox collects the events of one transaction. Add stores them through the
+// transaction, and Deliver hands them to this instance's handlers once it
+// has committed. A nil *Outbox drops everything.
+type Outbox struct {
+	bus    *Bus
+	store  Inserter
+	events []Event
+}
+
+// Outbox returns an outbox writing through store, which should be scoped to
+// the transaction making the change.
+func (b *Bus) Outbox(store Inserter) *Outbox {
+	if b == nil {
+		return nil
+	}
+	return &Outbox{bus: b, store: store}
+}
+
+// Add stores an event. It is only seen by other instances, and only
+// delivered by Deliver, if the transaction commits; an error should abort
+// the transaction.
+func (o *Outbox) Add(ctx context.Context, name string, payload interface{}) error {
+	if o == nil {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	row, err := o.store.InsertDomainEvent(ctx, db.InsertDomainEventParams{Name: name, Payload: data, Origin: o.bus.origin})
+	if err != nil {
+		return err
+	}
+	o.events = append(o.events, Event{ID: row.ID, Name: name, Payload: data, Origin: o.bus.origin, Time: row.CreatedAt})
+	return nil
+}
+
+// Deliver runs this instance's handlers for the added events. Call it after
+// the transaction has committed; other instances receive the events through
+// LISTEN.
+func (o *Outbox) Deliver(ctx context.Context) {
+	if o == nil {
+		return
+	}
+	for _, e := range o.events {
+		if err := o.bus.dispatch(ctx, e); err != nil {
+			log.Printf("bus: handling %s: %v", e.Name, err)
+		}
+	}
+	o.events = nil
+}
+
+// Publish stores an event on its own and runs this instance's handlers
+// before returning. It is for announcements with no change to commit with,
+// such as visitor.rate_limited; changes go through an Outbox. The local
+// handlers run even when the event cannot be stored, and that error is
+// returned.
+func (b *Bus) Publish(ctx context.Context, name string, payload interface{}) error {
+	if b == nil {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	e := Event{Name: name, Payload: data, Origin: b.origin, Time: b.now()}
+	row, err := b.store.InsertDomainEvent(ctx, db.InsertDomainEventParams{Name: name, Payload: data, Origin: b.origin})
+	if err == nil {
+		e.ID, e.Time = row.ID, row.CreatedAt
+	}
+	if herr := b.dispatch(ctx, e); herr != nil {
+		log.Printf("bus: handling %s: %v", name, herr)
+	}
+	return err
+}
+
+// dispatch runs the handlers for e, returning the first error.
+func (b *Bus) dispatch(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	handlers := append(append([]Handler(nil), b.handlers[e.Name]...), b.handlers[All]...)
+	b.mu.RUnlock()
+
+	var first error
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Run delivers events published by other instances until ctx is done. dsn
+// opens the dedicated LISTEN connection, which reconnects on its own; after
+// a reconnect, and every pollInterval, the table is read for anything
+// missed. Events older than the start of Run are not replayed.
+func (b *Bus) Run(ctx context.Context, dsn string) {
+	for backoff := minReconnect; ; backoff = min(backoff*2, maxReconnect) {
+		latest, err := b.store.GetLatestDomainEventID(ctx)
+		if err == nil {
+			b.cursor = latest
+			break
+		}
+		log.Printf("bus: reading events: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+	}
+
+	l := pq.NewListener(dsn, minReconnect, maxReconnect, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("bus: listener: %v", err)
+		}
+	})
+	defer l.Close()
+	if err := l.Listen(Channel); err != nil {
+		log.Printf("bus: listen: %v", err)
+	}
+
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	prune := time.NewTicker(time.Hour)
+	defer prune.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.Notify:
+			// nil after a reconnect; either way, read what is new
+			b.catchUp(ctx)
+		case <-poll.C:
+			_ = l.Ping()
+			b.catchUp(ctx)
+		case <-prune.C:
+			if _, err := b.store.DeleteDomainEventsBefore(ctx, b.now().Add(-retention)); err != nil {
+				log.Printf("bus: pruning events: %v", err)
+			}
+		}
+	}
+}
+
+// catchUp handles the stored events above the cursor, in ID order. Events
+// from this instance were handled when published. A failing event stops the
+// pass and is retried on the next one.
+func (b *Bus) catchUp(ctx context.Context) {
+	after := b.cursor
+	for {
+		rows, err := b.store.ListDomainEventsAfter(ctx, db.ListDomainEventsAfterParams{ID: after, Limit: batchSize})
+		if err != nil {
+			log.Printf("bus: reading events: %v", err)
+			return
+		}
+		for _, row := range rows {
+			after = row.ID
+			if b.done[row.ID] {
+				continue
+			}
+			if row.Origin != b.origin {
+				e := Event{ID: row.ID, Name: row.Name, Payload: row.Payload, Origin: row.Origin, Time: row.CreatedAt}
+				if err := b.dispatch(ctx, e); err != nil {
+					if b.attempts++; b.attempts < maxAttempts {
+						log.Printf("bus: handling %s #%d (attempt %d): %v", row.Name, row.ID, b.attempts, err)
+						b.advance()
+						return
+					}
+					log.Printf("bus: giving up on %s #%d: %v", row.Name, row.ID, err)
+				}
+			}
+			b.attempts = 0
+			b.done[row.ID] = true
+		}
+		if len(rows) < batchSize {
+			break
+		}
+	}
+	b.advance()
+}
+
+// advance moves the cursor over handled IDs. A gap that has not filled in
+// within gapTimeout is skipped.
+func (b *Bus) advance() {
+	for b.done[b.cursor+1] {
+		b.cursor++
+		delete(b.done, b.cursor)
+	}
+	if len(b.done) == 0 {
+		b.gapSince = time.Time{}
+		return
+	}
+	if b.gapSince.IsZero() {
+		b.gapSince = b.now()
+		return
+	}
+	if b.now().Sub(b.gapSince) < gapTimeout {
+		return
+	}
+	next := int64(-1)
+	for id := range b.done {
+		if next < 0 || id < next {
+			next = id
+		}
+	}
+	b.cursor = next - 1
+	b.gapSince = time.Time{}
+	b.advance()
+}
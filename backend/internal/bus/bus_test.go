@@ -0,0 +1,224 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+)
+
+// memStore is an in-memory event table.
+type memStore struct {
+	rows    []db.DomainEvent
+	nextID  int64
+	failIns bool
+}
+
+func (m *memStore) InsertDomainEvent(_ context.Context, arg db.InsertDomainEventParams) (db.DomainEvent, error) {
+	if m.failIns {
+		return db.DomainEvent{}, errors.New("insert failed")
+	}
+	m.nextID++
+	row := db.DomainEvent{ID: m.nextID, Name: arg.Name, Payload: arg.Payload, Origin: arg.Origin, CreatedAt: time.Now()}
+	m.rows = append(m.rows, row)
+	return row, nil
+}
+
+func (m *memStore) ListDomainEventsAfter(_ context.Context, arg db.ListDomainEventsAfterParams) ([]db.DomainEvent, error) {
+	sort.Slice(m.rows, func(i, j int) bool { return m.rows[i].ID < m.rows[j].ID })
+	var out []db.DomainEvent
+	for _, r := range m.rows {
+		if r.ID > arg.ID && len(out) < int(arg.Limit) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (m *memStore) GetLatestDomainEventID(context.Context) (int64, error) { return m.nextID, nil }
+
+func (m *memStore) DeleteDomainEventsBefore(context.Context, time.Time) (int64, error) { return 0, nil }
+
+// add stores an event from another instance with the given ID.
+func (m *memStore) add(id int64, name string) {
+	m.rows = append(m.rows, db.DomainEvent{ID: id, Name: name, Payload: []byte(`{}`), Origin: "other"})
+	if id > m.nextID {
+		m.nextID = id
+	}
+}
+
+func recorder(got *[]int64) Handler {
+	return func(_ context.Context, e Event) error {
+		*got = append(*got, e.ID)
+		return nil
+	}
+}
+
+func TestPublishRunsLocalHandlers(t *testing.T) {
+	store := &memStore{}
+	b := New(store)
+	var got []Change
+	b.Subscribe(PostPublished, func(_ context.Context, e Event) error {
+		var c Change
+		if err := e.Decode(&c); err != nil {
+			return err
+		}
+		got = append(got, c)
+		return nil
+	})
+	var all int
+	b.Subscribe(All, func(context.Context, Event) error { all++; return nil })
+
+	if err := b.Publish(context.Background(), PostPublished, Change{PostIDs: []int32{4}}); err != nil {
+		t.Fatal(err)
+	}
+	_ = b.Publish(context.Background(), ProjectUpdated, Change{})
+	if len(got) != 1 || got[0].PostIDs[0] != 4 || all != 2 {
+		t.Errorf("handlers saw %v and %d events", got, all)
+	}
+	if len(store.rows) != 2 || store.rows[0].Origin != b.origin {
+		t.Errorf("events = %v", store.rows)
+	}
+
+	// Own events are not delivered twice
+	got = nil
+	b.catchUp(context.Background())
+	if len(got) != 0 {
+		t.Errorf("catchUp redelivered a local event")
+	}
+
+	store.failIns = true
+	if err := b.Publish(context.Background(), PostPublished, Change{}); err == nil {
+		t.Error("Publish hid the store error")
+	}
+	if len(got) != 1 {
+		t.Error("local handlers did not run when the store failed")
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	store := &memStore{}
+	b := New(store)
+	var got []int64
+	b.Subscribe(All, recorder(&got))
+
+	out := b.Outbox(store)
+	if err := out.Add(context.Background(), PostUpdated, Change{PostIDs: []int32{4}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Add(context.Background(), SeriesUpdated, Change{SeriesIDs: []int32{2}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.rows) != 2 || len(got) != 0 {
+		t.Fatalf("stored %d, delivered %v; want both stored and none delivered before commit", len(store.rows), got)
+	}
+
+	out.Deliver(context.Background())
+	out.Deliver(context.Background())
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("delivered %v; want each event once, in order", got)
+	}
+	b.catchUp(context.Background())
+	if len(got) != 2 {
+		t.Errorf("catchUp redelivered a local event")
+	}
+
+	store.failIns = true
+	if err := b.Outbox(store).Add(context.Background(), PostUpdated, Change{}); err == nil {
+		t.Error("Add hid the store error")
+	}
+}
+
+func TestCatchUpOrderAndRetry(t *testing.T) {
+	store := &memStore{}
+	b := New(store)
+	var got []int64
+	b.Subscribe(PostUpdated, recorder(&got))
+	fails := 2
+	b.Subscribe(ProjectUpdated, func(_ context.Context, e Event) error {
+		if fails > 0 {
+			fails--
+			return errors.New("busy")
+		}
+		got = append(got, e.ID)
+		return nil
+	})
+
+	store.add(1, PostUpdated)
+	store.add(2, ProjectUpdated)
+	store.add(3, PostUpdated)
+	for i := 0; i < 3; i++ {
+		b.catchUp(context.Background())
+	}
+	want := []int64{1, 2, 3}
+	if len(got) != len(want) || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("delivered %v; want %v in order, each once", got, want)
+	}
+	if b.cursor != 3 {
+		t.Errorf("cursor = %d; want 3", b.cursor)
+	}
+}
+
+func TestCatchUpGivesUp(t *testing.T) {
+	store := &memStore{}
+	b := New(store)
+	var got []int64
+	b.Subscribe(PostUpdated, recorder(&got))
+	b.Subscribe(ProjectUpdated, func(context.Context, Event) error { return errors.New("broken") })
+
+	store.add(1, ProjectUpdated)
+	store.add(2, PostUpdated)
+	for i := 0; i < maxAttempts; i++ {
+		b.catchUp(context.Background())
+	}
+	if len(got) != 1 || got[0] != 2 || b.cursor != 2 {
+		t.Errorf("got %v, cursor %d; want the failing event skipped after %d attempts", got, b.cursor, maxAttempts)
+	}
+}
+
+func TestCatchUpGap(t *testing.T) {
+	store := &memStore{}
+	b := New(store)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+	var got []int64
+	b.Subscribe(All, recorder(&got))
+
+	// 2 was assigned but is not committed yet
+	store.add(1, PostUpdated)
+	store.add(3, PostUpdated)
+	b.catchUp(context.Background())
+	if b.cursor != 1 {
+		t.Fatalf("cursor = %d; want it held before the gap", b.cursor)
+	}
+
+	store.add(2, PostUpdated)
+	b.catchUp(context.Background())
+	if len(got) != 3 || got[2] != 2 || b.cursor != 3 {
+		t.Errorf("got %v, cursor %d; want the late event delivered once", got, b.cursor)
+	}
+
+	// A rolled-back insert leaves a gap for good
+	store.add(5, PostUpdated)
+	b.catchUp(context.Background())
+	now = now.Add(gapTimeout)
+	b.catchUp(context.Background())
+	if b.cursor != 5 || len(b.done) != 0 {
+		t.Errorf("cursor = %d, done = %v; want the gap skipped after %s", b.cursor, b.done, gapTimeout)
+	}
+}
+
+func TestNilBus(t *testing.T) {
+	var b *Bus
+	if err := b.Publish(context.Background(), PostUpdated, Change{}); err != nil {
+		t.Errorf("nil Bus Publish = %v", err)
+	}
+	out := b.Outbox(nil)
+	if err := out.Add(context.Background(), PostUpdated, Change{}); err != nil {
+		t.Errorf("nil Outbox Add = %v", err)
+	}
+	out.Deliver(context.Background())
+}
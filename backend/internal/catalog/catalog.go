@@ -0,0 +1,261 @@
+// Package catalog imports and exports the project catalog in the shape of the
+// client's Project type (client/src/data/projects.ts), as JSON or YAML.
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+)
+
+// Supported encodings.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Palette lists the card colors the client knows how to render.
+var Palette = []string{"green", "pink", "cyan", "yellow"}
+
+// Statuses lists the project statuses. An item without one is imported as
+// DefaultStatus, or keeps the status of the project it updates.
+var Statuses = []string{"draft", "published", "archived"}
+
+// DefaultStatus is the status of a new project.
+const DefaultStatus = "published"
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// Item is one project in the client's Project shape. Field order follows
+// projects.ts so exported files diff cleanly against it; the publishing
+// fields, which projects.ts does not have, come last. An item that leaves
+// them out keeps the current values, so a projects.ts file can be imported
+// without resetting them.
+type Item struct {
+	Slug     string   `json:"slug" yaml:"slug"`
+	Title    string   `json:"title" yaml:"title"`
+	Summary  string   `json:"summary" yaml:"summary"`
+	Tags     []string `json:"tags" yaml:"tags"`
+	Footer   string   `json:"footer,omitempty" yaml:"footer,omitempty"`
+	Href     string   `json:"href,omitempty" yaml:"href,omitempty"`
+	External *bool    `json:"external,omitempty" yaml:"external,omitempty"`
+	Color    string   `json:"color,omitempty" yaml:"color,omitempty"`
+	Emoji    string   `json:"emoji,omitempty" yaml:"emoji,omitempty"`
+	Content  string   `json:"content,omitempty" yaml:"content,omitempty"`
+	Image    string   `json:"image,omitempty" yaml:"image,omitempty"`
+	Embed    string   `json:"embed,omitempty" yaml:"embed,omitempty"`
+
+	Status    string `json:"status,omitempty" yaml:"status,omitempty"`
+	Featured  *bool  `json:"featured,omitempty" yaml:"featured,omitempty"`
+	SortOrder *int32 `json:"sort_order,omitempty" yaml:"sort_order,omitempty"`
+}
+
+// document is the wrapped form accepted alongside a bare list.
+type document struct {
+	Projects []Item `json:"projects" yaml:"projects"`
+}
+
+// ParseFormat normalizes a format name, defaulting to JSON.
+func ParseFormat(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unsupported format %q (want json or yaml)", s)
+}
+
+// FormatFromPath picks a format from a file extension.
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// FormatFromContentType picks a format from a request Content-Type.
+func FormatFromContentType(ct string) string {
+	if strings.Contains(ct, "yaml") {
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// ContentType returns the response Content-Type for a format.
+func ContentType(format string) string {
+	if format == FormatYAML {
+		return "application/yaml"
+	}
+	return "application/json"
+}
+
+// Decode reads a catalog, either as a bare list of items or as an object with
+// a "projects" list.
+func Decode(data []byte, format string) ([]Item, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("empty catalog")
+	}
+
+	var doc document
+	if format == FormatYAML {
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, fmt.Errorf("invalid yaml catalog: %w", err)
+		}
+		if len(node.Content) > 0 && node.Content[0].Kind == yaml.MappingNode {
+			if err := node.Decode(&doc); err != nil {
+				return nil, fmt.Errorf("invalid yaml catalog: %w", err)
+			}
+			return doc.Projects, nil
+		}
+		var items []Item
+		if err := node.Decode(&items); err != nil {
+			return nil, fmt.Errorf("invalid yaml catalog: %w", err)
+		}
+		return items, nil
+	}
+
+	if data[0] == '{' {
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid json catalog: %w", err)
+		}
+		return doc.Projects, nil
+	}
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("invalid json catalog: %w", err)
+	}
+	return items, nil
+}
+
+// Encode writes items as a bare list.
+func Encode(items []Item, format string) ([]byte, error) {
+	if items == nil {
+		items = []Item{}
+	}
+	return Marshal(items, format)
+}
+
+// Marshal encodes any value (items or an import Report) in the given format.
+func Marshal(v interface{}, format string) ([]byte, error) {
+	if format == FormatYAML {
+		var b bytes.Buffer
+		enc := yaml.NewEncoder(&b)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Validate reports every problem with an item; nil means it can be imported.
+func Validate(it Item) []string {
+	var problems []string
+	if it.Slug == "" {
+		problems = append(problems, "slug is required")
+	} else if !slugPattern.MatchString(it.Slug) {
+		problems = append(problems, "slug must be lowercase letters, digits and single hyphens")
+	}
+	if strings.TrimSpace(it.Title) == "" {
+		problems = append(problems, "title is required")
+	}
+	if it.Color != "" && !oneOf(it.Color, Palette) {
+		problems = append(problems, fmt.Sprintf("color %q is not one of %s", it.Color, strings.Join(Palette, ", ")))
+	}
+	if it.Status != "" && !oneOf(it.Status, Statuses) {
+		problems = append(problems, fmt.Sprintf("status %q is not one of %s", it.Status, strings.Join(Statuses, ", ")))
+	}
+	return problems
+}
+
+func oneOf(s string, values []string) bool {
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// FromProject converts a projects row into an Item.
+func FromProject(p db.Project) Item {
+	external, featured, sortOrder := p.External, p.Featured, p.SortOrder
+	it := Item{
+		Slug:     p.Slug,
+		Title:    p.Title,
+		Summary:  p.Summary.String,
+		Tags:     p.Tags,
+		Footer:   p.Footer.String,
+		Href:     p.Href.String,
+		External: &external,
+		Color:    p.Color.String,
+		Emoji:    p.Emoji.String,
+		Content:  p.Content.String,
+		Image:    p.Image.String,
+		Embed:    p.Embed.String,
+
+		Status:    p.Status,
+		Featured:  &featured,
+		SortOrder: &sortOrder,
+	}
+	if it.Tags == nil {
+		it.Tags = []string{}
+	}
+	return it
+}
+
+// normalize fills the defaults the database would apply so that an imported
+// item compares equal to its exported form.
+func normalize(it Item) Item {
+	it.Slug = strings.TrimSpace(it.Slug)
+	it.Title = strings.TrimSpace(it.Title)
+	it.Color = strings.TrimSpace(it.Color)
+	it.Status = strings.TrimSpace(it.Status)
+	if it.Tags == nil {
+		it.Tags = []string{}
+	}
+	if it.External == nil {
+		external := false
+		it.External = &external
+	}
+	return it
+}
+
+// withDefaults fills the publishing fields an item leaves out from the
+// project it updates, or with a new project's defaults when there is none.
+func withDefaults(it Item, existing *db.Project) Item {
+	status, featured, sortOrder := DefaultStatus, false, int32(0)
+	if existing != nil {
+		status, featured, sortOrder = existing.Status, existing.Featured, existing.SortOrder
+	}
+	if it.Status == "" {
+		it.Status = status
+	}
+	if it.Featured == nil {
+		it.Featured = &featured
+	}
+	if it.SortOrder == nil {
+		it.SortOrder = &sortOrder
+	}
+	return it
+}
@@ -0,0 +1,216 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+)
+
+type fakeStore struct {
+	projects map[string]db.Project
+	upserts  int
+}
+
+func (f *fakeStore) GetProjectBySlug(_ context.Context, slug string) (db.Project, error) {
+	p, ok := f.projects[slug]
+	if !ok {
+		return db.Project{}, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+func (f *fakeStore) ListProjects(_ context.Context) ([]db.Project, error) {
+	var out []db.Project
+	for _, p := range f.projects {
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func (f *fakeStore) UpsertProjectBySlug(_ context.Context, arg db.UpsertProjectBySlugParams) (db.Project, error) {
+	f.upserts++
+	p := db.Project{
+		ID:       int32(len(f.projects) + 1),
+		Title:    arg.Title,
+		Slug:     arg.Slug,
+		Summary:  arg.Summary,
+		Tags:     arg.Tags,
+		Footer:   arg.Footer,
+		Href:     arg.Href,
+		External: arg.External,
+		Color:    arg.Color,
+		Emoji:    arg.Emoji,
+		Content:  arg.Content,
+		Image:    arg.Image,
+		Embed:    arg.Embed,
+
+		Status:    arg.Status,
+		Featured:  arg.Featured,
+		SortOrder: arg.SortOrder,
+	}
+	f.projects[arg.Slug] = p
+	return p, nil
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+	}{
+		{"json list", FormatJSON, `[{"slug":"a","title":"A","summary":"s","tags":["Go"],"color":"cyan"}]`},
+		{"json wrapped", FormatJSON, `{"projects":[{"slug":"a","title":"A","summary":"s","tags":["Go"],"color":"cyan"}]}`},
+		{"yaml list", FormatYAML, "- slug: a\n  title: A\n  summary: s\n  tags: [Go]\n  color: cyan\n"},
+		{"yaml wrapped", FormatYAML, "projects:\n  - slug: a\n    title: A\n    summary: s\n    tags: [Go]\n    color: cyan\n"},
+	}
+	want := []Item{{Slug: "a", Title: "A", Summary: "s", Tags: []string{"Go"}, Color: "cyan"}}
+
+	for _, tt := range tests {
+		got, err := Decode([]byte(tt.input), tt.format)
+		if err != nil {
+			t.Errorf("%s: Decode returned error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Decode = %+v; want %+v", tt.name, got, want)
+		}
+	}
+
+	if _, err := Decode([]byte("   "), FormatJSON); err == nil {
+		t.Error("Decode of an empty body succeeded; want error")
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	external := true
+	items := []Item{{Slug: "a", Title: "A", Tags: []string{"Go"}, External: &external, Color: "pink", Emoji: "🧪"}}
+
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		data, err := Encode(items, format)
+		if err != nil {
+			t.Fatalf("%s: Encode returned error: %v", format, err)
+		}
+		got, err := Decode(data, format)
+		if err != nil {
+			t.Fatalf("%s: Decode returned error: %v", format, err)
+		}
+		if !reflect.DeepEqual(got, items) {
+			t.Errorf("%s: round trip = %+v; want %+v", format, got, items)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		item Item
+		want int
+	}{
+		{"valid", Item{Slug: "llm-punctuator", Title: "LLM Punctuator", Color: "green"}, 0},
+		{"no color", Item{Slug: "x", Title: "X"}, 0},
+		{"bad color", Item{Slug: "x", Title: "X", Color: "purple"}, 1},
+		{"uppercase color", Item{Slug: "x", Title: "X", Color: "Cyan"}, 1},
+		{"bad slug", Item{Slug: "Not A Slug", Title: "X"}, 1},
+		{"missing everything", Item{}, 2},
+	}
+	for _, tt := range tests {
+		if got := Validate(tt.item); len(got) != tt.want {
+			t.Errorf("%s: Validate = %v; want %d problem(s)", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	store := &fakeStore{projects: map[string]db.Project{}}
+	items := []Item{
+		{Slug: "one", Title: "One", Color: "cyan"},
+		{Slug: "two", Title: "Two", Color: "purple"},
+		{Slug: "one", Title: "Duplicate"},
+	}
+
+	report := Import(context.Background(), store, items, true)
+	if store.upserts != 0 {
+		t.Errorf("dry run wrote %d row(s)", store.upserts)
+	}
+	wantActions := []string{ActionCreated, ActionInvalid, ActionInvalid}
+	for i, res := range report.Results {
+		if res.Action != wantActions[i] {
+			t.Errorf("dry run item %d: action = %q; want %q", i, res.Action, wantActions[i])
+		}
+	}
+	if !report.Failed() {
+		t.Error("report with invalid items should be marked failed")
+	}
+
+	Import(context.Background(), store, items[:1], false)
+	if store.upserts != 1 {
+		t.Fatalf("import wrote %d row(s); want 1", store.upserts)
+	}
+
+	again := Import(context.Background(), store, items[:1], false)
+	if got := again.Results[0].Action; got != ActionUnchanged {
+		t.Errorf("re-import action = %q; want %q", got, ActionUnchanged)
+	}
+
+	changed := Import(context.Background(), store, []Item{{Slug: "one", Title: "One", Color: "pink"}}, false)
+	if got := changed.Results[0].Action; got != ActionUpdated {
+		t.Errorf("changed import action = %q; want %q", got, ActionUpdated)
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	source := &fakeStore{projects: map[string]db.Project{
+		"wip":  {ID: 1, Slug: "wip", Title: "WIP", Tags: []string{}, Status: "draft", SortOrder: 3},
+		"star": {ID: 2, Slug: "star", Title: "Star", Tags: []string{"Go"}, Status: "published", Featured: true, SortOrder: 1},
+		"old":  {ID: 3, Slug: "old", Title: "Old", Tags: []string{}, Status: "archived"},
+	}}
+	items, err := Export(context.Background(), source)
+	if err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	data, err := Encode(items, FormatYAML)
+	if err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+	decoded, err := Decode(data, FormatYAML)
+	if err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+
+	restored := &fakeStore{projects: map[string]db.Project{}}
+	if report := Import(context.Background(), restored, decoded, false); report.Failed() {
+		t.Fatalf("restore failed: %+v", report.Results)
+	}
+	for slug, want := range source.projects {
+		got := restored.projects[slug]
+		if got.Status != want.Status || got.Featured != want.Featured || got.SortOrder != want.SortOrder {
+			t.Errorf("%s restored as %s/%v/%d; want %s/%v/%d", slug,
+				got.Status, got.Featured, got.SortOrder, want.Status, want.Featured, want.SortOrder)
+		}
+	}
+}
+
+func TestImportKeepsPublishingFields(t *testing.T) {
+	store := &fakeStore{projects: map[string]db.Project{
+		"wip": {ID: 1, Slug: "wip", Title: "WIP", Tags: []string{}, Status: "draft", Featured: true, SortOrder: 2},
+	}}
+
+	// A projects.ts item has no publishing fields
+	report := Import(context.Background(), store, []Item{{Slug: "wip", Title: "Work in progress"}, {Slug: "new", Title: "New"}}, false)
+	if report.Failed() {
+		t.Fatalf("import failed: %+v", report.Results)
+	}
+	if p := store.projects["wip"]; p.Status != "draft" || !p.Featured || p.SortOrder != 2 {
+		t.Errorf("updated project = %s/%v/%d; want draft/true/2", p.Status, p.Featured, p.SortOrder)
+	}
+	if p := store.projects["new"]; p.Status != DefaultStatus || p.Featured || p.SortOrder != 0 {
+		t.Errorf("new project = %s/%v/%d; want the defaults", p.Status, p.Featured, p.SortOrder)
+	}
+
+	if problems := Validate(Item{Slug: "x", Title: "X", Status: "hidden"}); len(problems) != 1 {
+		t.Errorf("Validate of an unknown status = %v; want 1 problem", problems)
+	}
+}
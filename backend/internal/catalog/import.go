@@ -0,0 +1,158 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+)
+
+// Actions reported per item.
+const (
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionUnchanged = "unchanged"
+	ActionInvalid   = "invalid"
+	ActionError     = "error"
+)
+
+// Result describes what an import did with a single item.
+type Result struct {
+	Index  int      `json:"index" yaml:"index"`
+	Slug   string   `json:"slug" yaml:"slug"`
+	Action string   `json:"action" yaml:"action"`
+	Errors []string `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// Report summarizes an import.
+type Report struct {
+	DryRun  bool           `json:"dry_run" yaml:"dry_run"`
+	Counts  map[string]int `json:"counts" yaml:"counts"`
+	Results []Result       `json:"results" yaml:"results"`
+}
+
+// Failed reports whether any item was invalid or could not be written.
+func (r Report) Failed() bool {
+	return r.Counts[ActionInvalid] > 0 || r.Counts[ActionError] > 0
+}
+
+// Store is the subset of db.Queries used by Import and Export.
+type Store interface {
+	GetProjectBySlug(ctx context.Context, slug string) (db.Project, error)
+	ListProjects(ctx context.Context) ([]db.Project, error)
+	UpsertProjectBySlug(ctx context.Context, arg db.UpsertProjectBySlugParams) (db.Project, error)
+}
+
+// Import validates every item and upserts the valid ones by slug. Invalid
+// items do not stop the rest of the catalog from being imported. With dryRun
+// nothing is written, but the report still says what would have happened.
+func Import(ctx context.Context, store Store, items []Item, dryRun bool) Report {
+	report := Report{DryRun: dryRun, Counts: map[string]int{}, Results: make([]Result, 0, len(items))}
+	seen := map[string]int{}
+
+	for i, raw := range items {
+		it := normalize(raw)
+		res := Result{Index: i, Slug: it.Slug}
+
+		problems := Validate(it)
+		if first, dup := seen[it.Slug]; dup {
+			problems = append(problems, fmt.Sprintf("duplicate slug (first used by item %d)", first))
+		} else if it.Slug != "" {
+			seen[it.Slug] = i
+		}
+
+		if len(problems) > 0 {
+			res.Action, res.Errors = ActionInvalid, problems
+		} else {
+			res.Action, res.Errors = importItem(ctx, store, it, dryRun)
+		}
+		report.Counts[res.Action]++
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+func importItem(ctx context.Context, store Store, it Item, dryRun bool) (string, []string) {
+	existing, err := store.GetProjectBySlug(ctx, it.Slug)
+	exists := err == nil
+	if err != nil && err != sql.ErrNoRows {
+		return ActionError, []string{err.Error()}
+	}
+	if exists {
+		it = withDefaults(it, &existing)
+	} else {
+		it = withDefaults(it, nil)
+	}
+	if exists && reflect.DeepEqual(FromProject(existing), it) {
+		return ActionUnchanged, nil
+	}
+
+	action := ActionCreated
+	if exists {
+		action = ActionUpdated
+	}
+	if dryRun {
+		return action, nil
+	}
+	if _, err := store.UpsertProjectBySlug(ctx, it.params()); err != nil {
+		return ActionError, []string{err.Error()}
+	}
+	return action, nil
+}
+
+// Export returns the whole catalog, oldest project first, which matches the
+// order an import creates rows in.
+func Export(ctx context.Context, store Store) ([]Item, error) {
+	projects, err := store.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(projects, func(i, j int) bool {
+		a, b := projects[i], projects[j]
+		if !a.CreatedAt.Time.Equal(b.CreatedAt.Time) {
+			return a.CreatedAt.Time.Before(b.CreatedAt.Time)
+		}
+		return a.ID < b.ID
+	})
+
+	items := make([]Item, 0, len(projects))
+	for _, p := range projects {
+		items = append(items, FromProject(p))
+	}
+	return items, nil
+}
+
+func (it Item) params() db.UpsertProjectBySlugParams {
+	return db.UpsertProjectBySlugParams{
+		Title:    it.Title,
+		Slug:     it.Slug,
+		Summary:  nullString(it.Summary),
+		Tags:     it.Tags,
+		Footer:   nullString(it.Footer),
+		Href:     nullString(it.Href),
+		External: it.External != nil && *it.External,
+		Color:    nullString(it.Color),
+		Emoji:    nullString(it.Emoji),
+		Content:  nullString(it.Content),
+		Image:    nullString(it.Image),
+		Embed:    nullString(it.Embed),
+
+		Status:    it.Status,
+		Featured:  it.Featured != nil && *it.Featured,
+		SortOrder: derefInt32(it.SortOrder),
+	}
+}
+
+func derefInt32(p *int32) int32 {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
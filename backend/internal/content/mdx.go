@@ -0,0 +1,134 @@
+// Package content converts blog posts between the client's MDX files
+// (client/src/blog) and rows in the posts table.
+package content
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dateLayout is the frontmatter date format used by the client.
+const dateLayout = "2006-01-02"
+
+// Document is a parsed MDX file: YAML frontmatter plus the MDX body.
+type Document struct {
+	Title   string
+	Slug    string
+	Summary string
+	Tags    []string
+	Date    time.Time
+	Draft   bool
+	Body    string
+}
+
+type frontmatter struct {
+	Title       string   `yaml:"title"`
+	Slug        string   `yaml:"slug"`
+	Summary     string   `yaml:"summary"`
+	Description string   `yaml:"description"`
+	Tags        []string `yaml:"tags"`
+	Date        string   `yaml:"date"`
+	Draft       bool     `yaml:"draft"`
+}
+
+var errNoFrontmatter = errors.New("missing frontmatter block")
+
+// Parse reads an MDX file with a leading "---" delimited frontmatter block.
+// fallbackSlug is used when the frontmatter has no slug (usually the file name).
+func Parse(data []byte, fallbackSlug string) (Document, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return Document{}, errNoFrontmatter
+	}
+	rest := text[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return Document{}, errNoFrontmatter
+	}
+	header := rest[:end]
+	body := rest[end+len("\n---"):]
+	body = strings.TrimPrefix(body, "\n")
+	body = strings.TrimLeft(body, "\n")
+
+	var fm frontmatter
+	if err := yaml.Unmarshal([]byte(header), &fm); err != nil {
+		return Document{}, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+
+	doc := Document{
+		Title:   strings.TrimSpace(fm.Title),
+		Slug:    strings.TrimSpace(fm.Slug),
+		Summary: fm.Summary,
+		Tags:    fm.Tags,
+		Draft:   fm.Draft,
+		Body:    body,
+	}
+	if doc.Summary == "" {
+		doc.Summary = fm.Description
+	}
+	if doc.Slug == "" {
+		doc.Slug = fallbackSlug
+	}
+	if doc.Tags == nil {
+		doc.Tags = []string{}
+	}
+	if doc.Title == "" {
+		return Document{}, errors.New("frontmatter is missing a title")
+	}
+	if fm.Date != "" {
+		t, err := parseDate(fm.Date)
+		if err != nil {
+			return Document{}, err
+		}
+		doc.Date = t
+	}
+	return doc, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", s)
+}
+
+// Format renders a Document in the style used by the files in client/src/blog.
+func Format(doc Document) []byte {
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", quote(doc.Title))
+	fmt.Fprintf(&b, "slug: %s\n", quote(doc.Slug))
+	if doc.Summary != "" {
+		fmt.Fprintf(&b, "summary: %s\n", quote(doc.Summary))
+	}
+	tags := make([]string, len(doc.Tags))
+	for i, t := range doc.Tags {
+		tags[i] = quote(t)
+	}
+	fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(tags, ", "))
+	if !doc.Date.IsZero() {
+		fmt.Fprintf(&b, "date: %s\n", quote(doc.Date.UTC().Format(dateLayout)))
+	}
+	if doc.Draft {
+		b.WriteString("draft: true\n")
+	}
+	b.WriteString("---\n\n")
+	b.WriteString(doc.Body)
+	if !strings.HasSuffix(doc.Body, "\n") {
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
+
+// quote renders s as a single-quoted YAML scalar.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
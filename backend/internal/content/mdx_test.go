@@ -0,0 +1,80 @@
+package content
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleMDX = `---
+title: "Sample Post 1: Exploring Html"
+slug: "sample-post-1"
+tags: ['ux', 'mdx']
+date: "2025-05-27"
+---
+
+import GlitchBox from '@/components/mdx/GlitchBox'
+
+# Sample Post 1
+`
+
+func TestParse(t *testing.T) {
+	doc, err := Parse([]byte(sampleMDX), "fallback")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if doc.Title != "Sample Post 1: Exploring Html" {
+		t.Errorf("Title = %q", doc.Title)
+	}
+	if doc.Slug != "sample-post-1" {
+		t.Errorf("Slug = %q; want sample-post-1", doc.Slug)
+	}
+	if len(doc.Tags) != 2 || doc.Tags[0] != "ux" || doc.Tags[1] != "mdx" {
+		t.Errorf("Tags = %v; want [ux mdx]", doc.Tags)
+	}
+	if !doc.Date.Equal(time.Date(2025, 5, 27, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Date = %v; want 2025-05-27", doc.Date)
+	}
+	if !strings.HasPrefix(doc.Body, "import GlitchBox") {
+		t.Errorf("Body should start after the frontmatter, got %q", doc.Body)
+	}
+}
+
+func TestParseFallbackSlugAndErrors(t *testing.T) {
+	doc, err := Parse([]byte("---\ntitle: 'No slug'\n---\nbody\n"), "from-file")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if doc.Slug != "from-file" {
+		t.Errorf("Slug = %q; want from-file", doc.Slug)
+	}
+
+	for name, input := range map[string]string{
+		"no frontmatter": "# just markdown\n",
+		"no title":       "---\nslug: x\n---\nbody\n",
+		"bad date":       "---\ntitle: x\ndate: someday\n---\nbody\n",
+	} {
+		if _, err := Parse([]byte(input), "x"); err == nil {
+			t.Errorf("%s: Parse succeeded; want error", name)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	doc := Document{
+		Title:   "It's a test",
+		Slug:    "its-a-test",
+		Summary: "Short",
+		Tags:    []string{"go", "mdx"},
+		Date:    time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		Body:    "# Heading\n\nText.\n",
+	}
+
+	parsed, err := Parse(Format(doc), "ignored")
+	if err != nil {
+		t.Fatalf("Parse(Format(doc)) returned error: %v", err)
+	}
+	if !docsEqual(doc, parsed) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", parsed, doc)
+	}
+}
@@ -0,0 +1,57 @@
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// StateFile is the name of the sync bookkeeping file kept next to the MDX files.
+const StateFile = ".content-sync.json"
+
+// Entry records both sides of a post as of its last successful sync.
+type Entry struct {
+	FileHash  string `json:"file_hash"`
+	UpdatedAt string `json:"db_updated_at"`
+}
+
+// State maps post slugs to their last synced Entry.
+type State struct {
+	path    string
+	Entries map[string]Entry `json:"entries"`
+}
+
+// LoadState reads the state file in dir, returning an empty state if none exists.
+func LoadState(dir string) (*State, error) {
+	st := &State{path: filepath.Join(dir, StateFile), Entries: map[string]Entry{}}
+	data, err := os.ReadFile(st.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, err
+	}
+	if st.Entries == nil {
+		st.Entries = map[string]Entry{}
+	}
+	return st, nil
+}
+
+// Save writes the state file atomically. Entries are keyed by slug and
+// encoding/json sorts map keys, so the output is stable between runs.
+func (st *State) Save() error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := st.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, st.path)
+}
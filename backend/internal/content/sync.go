@@ -0,0 +1,394 @@
+package content
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/feed"
+	"github.com/onnwee/onnwee.github.io/backend/internal/postlinks"
+	"github.com/onnwee/onnwee.github.io/backend/internal/utils"
+)
+
+// Actions reported per post.
+const (
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionWritten   = "written"
+	ActionUnchanged = "unchanged"
+	ActionConflict  = "conflict"
+	ActionError     = "error"
+)
+
+// Result describes what a sync did with a single post.
+type Result struct {
+	Slug   string `json:"slug"`
+	File   string `json:"file"`
+	Action string `json:"action"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Syncer moves posts between a directory of MDX files and the database.
+type Syncer struct {
+	Queries *db.Queries
+	// Conn opens the transaction each imported post is written in, together
+	// with its event.
+	Conn *sql.DB
+	Dir  string
+	// Bus, if set, announces imported posts to running servers.
+	Bus *bus.Bus
+	// Force overwrites the destination even when both sides changed.
+	Force bool
+	// DryRun reports what would happen without writing anything.
+	DryRun bool
+}
+
+// Import upserts every MDX file in the directory into posts.
+func (s *Syncer) Import(ctx context.Context) ([]Result, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	return s.ImportFiles(ctx, files)
+}
+
+// ImportFiles upserts the given MDX files into posts, keyed by slug. A file is
+// skipped when unchanged since the last sync and reported as a conflict when
+// the database row was also edited since then.
+func (s *Syncer) ImportFiles(ctx context.Context, files []string) ([]Result, error) {
+	st, err := LoadState(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(files))
+	for _, path := range files {
+		res := s.importFile(ctx, st, path)
+		results = append(results, res)
+	}
+
+	if s.DryRun {
+		return results, nil
+	}
+	return results, st.Save()
+}
+
+func (s *Syncer) importFile(ctx context.Context, st *State, path string) Result {
+	name := filepath.Base(path)
+	res := Result{File: name}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		res.Action, res.Detail = ActionError, err.Error()
+		return res
+	}
+	doc, err := Parse(data, strings.TrimSuffix(name, filepath.Ext(name)))
+	if err != nil {
+		res.Action, res.Detail = ActionError, err.Error()
+		return res
+	}
+	res.Slug = doc.Slug
+	hash := hashBytes(data)
+
+	entry, synced := st.Entries[doc.Slug]
+	if synced && entry.FileHash == hash {
+		res.Action = ActionUnchanged
+		return res
+	}
+
+	row, err := s.Queries.GetPostBySlug(ctx, doc.Slug)
+	exists := err == nil
+	if err != nil && err != sql.ErrNoRows {
+		res.Action, res.Detail = ActionError, err.Error()
+		return res
+	}
+
+	if exists && (!synced || entry.UpdatedAt != stamp(row.UpdatedAt)) {
+		// The row moved since the last sync (or was never synced).
+		if docsEqual(doc, FromPost(row)) {
+			if !s.DryRun {
+				st.Entries[doc.Slug] = Entry{FileHash: hash, UpdatedAt: stamp(row.UpdatedAt)}
+			}
+			res.Action = ActionUnchanged
+			return res
+		}
+		if !s.Force {
+			res.Action = ActionConflict
+			res.Detail = "file and database both changed since last sync"
+			return res
+		}
+	}
+
+	res.Action = ActionCreated
+	if exists {
+		res.Action = ActionUpdated
+	}
+	if s.DryRun {
+		return res
+	}
+
+	post, err := s.upsert(ctx, doc, exists && !row.IsDraft.Bool)
+	if err != nil {
+		res.Action, res.Detail = ActionError, err.Error()
+		return res
+	}
+	st.Entries[doc.Slug] = Entry{FileHash: hash, UpdatedAt: stamp(post.UpdatedAt)}
+	return res
+}
+
+// upsert writes doc, its detected project links and the event announcing it
+// in one transaction.
+func (s *Syncer) upsert(ctx context.Context, doc Document, wasPublished bool) (db.Post, error) {
+	tx, err := s.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return db.Post{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	q := s.Queries.WithTx(tx)
+
+	post, err := q.UpsertPostBySlug(ctx, db.UpsertPostBySlugParams{
+		Title:       doc.Title,
+		Slug:        doc.Slug,
+		Summary:     sql.NullString{String: doc.Summary, Valid: doc.Summary != ""},
+		Content:     doc.Body,
+		Tags:        doc.Tags,
+		IsDraft:     sql.NullBool{Bool: doc.Draft, Valid: true},
+		PublishedAt: sql.NullTime{Time: doc.Date, Valid: !doc.Date.IsZero()},
+	})
+	if err != nil {
+		return db.Post{}, err
+	}
+	if err := postlinks.Sync(ctx, q, post.ID, post.Content, feed.SiteURL()); err != nil {
+		return db.Post{}, err
+	}
+	event := bus.PostUpdated
+	if !post.IsDraft.Bool && !wasPublished {
+		event = bus.PostPublished
+	}
+	if err := s.Bus.Outbox(q).Add(ctx, event, bus.Change{PostIDs: []int32{post.ID}}); err != nil {
+		return db.Post{}, err
+	}
+	return post, tx.Commit()
+}
+
+// Export writes every post to <slug>.mdx (or the existing file carrying that
+// slug). Files edited since the last sync are reported as conflicts, and
+// posts whose slug is not safe as a file name as errors.
+func (s *Syncer) Export(ctx context.Context) ([]Result, error) {
+	st, err := LoadState(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index existing files by the slug in their frontmatter
+	existing := map[string]string{}
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		name := filepath.Base(path)
+		if doc, err := Parse(data, strings.TrimSuffix(name, filepath.Ext(name))); err == nil {
+			existing[doc.Slug] = path
+		}
+	}
+
+	posts, err := s.Queries.ListAllPosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(posts))
+	for _, post := range posts {
+		path, err := exportPath(s.Dir, post.Slug)
+		if err != nil {
+			results = append(results, Result{Slug: post.Slug, Action: ActionError, Detail: err.Error()})
+			continue
+		}
+		if p, ok := existing[post.Slug]; ok {
+			path = p
+		}
+		results = append(results, s.exportPost(st, post, path))
+	}
+
+	if s.DryRun {
+		return results, nil
+	}
+	return results, st.Save()
+}
+
+// exportPath returns <dir>/<slug>.mdx, refusing slugs that are not in
+// canonical form or would put the file outside dir.
+func exportPath(dir, slug string) (string, error) {
+	if slug == "" || utils.Slugify(slug) != slug {
+		return "", fmt.Errorf("invalid slug %q", slug)
+	}
+	path := filepath.Join(dir, slug+".mdx")
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel != filepath.Base(path) {
+		return "", fmt.Errorf("slug %q escapes the content directory", slug)
+	}
+	return path, nil
+}
+
+func (s *Syncer) exportPost(st *State, post db.Post, path string) Result {
+	res := Result{Slug: post.Slug, File: filepath.Base(path)}
+	doc := FromPost(post)
+	data := Format(doc)
+	hash := hashBytes(data)
+	entry, synced := st.Entries[post.Slug]
+
+	current, err := os.ReadFile(path)
+	fileExists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		res.Action, res.Detail = ActionError, err.Error()
+		return res
+	}
+
+	if fileExists {
+		currentHash := hashBytes(current)
+		dbChanged := !synced || entry.UpdatedAt != stamp(post.UpdatedAt)
+		if !dbChanged {
+			res.Action = ActionUnchanged
+			return res
+		}
+		if currentHash == hash || sameDocument(current, doc, path) {
+			if !s.DryRun {
+				st.Entries[post.Slug] = Entry{FileHash: currentHash, UpdatedAt: stamp(post.UpdatedAt)}
+			}
+			res.Action = ActionUnchanged
+			return res
+		}
+		if (!synced || entry.FileHash != currentHash) && !s.Force {
+			res.Action = ActionConflict
+			res.Detail = "file and database both changed since last sync"
+			return res
+		}
+	}
+
+	res.Action = ActionWritten
+	if s.DryRun {
+		return res
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		res.Action, res.Detail = ActionError, err.Error()
+		return res
+	}
+	st.Entries[post.Slug] = Entry{FileHash: hash, UpdatedAt: stamp(post.UpdatedAt)}
+	return res
+}
+
+// Watch polls the directory and imports files whose modification time or size
+// changed. Results for files that were not unchanged are passed to report.
+func (s *Syncer) Watch(ctx context.Context, interval time.Duration, report func([]Result, error)) {
+	seen := map[string]string{}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		files, err := s.files()
+		if err != nil {
+			report(nil, err)
+		}
+		var changed []string
+		for _, path := range files {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			sig := fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+			if seen[path] != sig {
+				seen[path] = sig
+				changed = append(changed, path)
+			}
+		}
+		if len(changed) > 0 {
+			results, err := s.ImportFiles(ctx, changed)
+			var interesting []Result
+			for _, r := range results {
+				if r.Action != ActionUnchanged {
+					interesting = append(interesting, r)
+				}
+			}
+			if len(interesting) > 0 || err != nil {
+				report(interesting, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Syncer) files() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(s.Dir, "*.mdx"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// FromPost converts a posts row into a Document.
+func FromPost(p db.Post) Document {
+	doc := Document{
+		Title:   p.Title,
+		Slug:    p.Slug,
+		Summary: p.Summary.String,
+		Tags:    p.Tags,
+		Draft:   p.IsDraft.Valid && p.IsDraft.Bool,
+		Body:    p.Content,
+	}
+	if doc.Tags == nil {
+		doc.Tags = []string{}
+	}
+	if p.PublishedAt.Valid {
+		doc.Date = p.PublishedAt.Time.UTC().Truncate(24 * time.Hour)
+	}
+	return doc
+}
+
+func sameDocument(data []byte, doc Document, path string) bool {
+	name := filepath.Base(path)
+	parsed, err := Parse(data, strings.TrimSuffix(name, filepath.Ext(name)))
+	return err == nil && docsEqual(parsed, doc)
+}
+
+func docsEqual(a, b Document) bool {
+	return a.Title == b.Title &&
+		a.Slug == b.Slug &&
+		a.Summary == b.Summary &&
+		reflect.DeepEqual(a.Tags, b.Tags) &&
+		a.Date.Equal(b.Date) &&
+		a.Draft == b.Draft &&
+		strings.TrimSpace(a.Body) == strings.TrimSpace(b.Body)
+}
+
+func stamp(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339Nano)
+}
+
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,23 @@
+package content
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExportPath(t *testing.T) {
+	dir := filepath.Join("content", "posts")
+	path, err := exportPath(dir, "hello-world")
+	if err != nil {
+		t.Fatalf("exportPath returned error: %v", err)
+	}
+	if want := filepath.Join(dir, "hello-world.mdx"); path != want {
+		t.Errorf("exportPath = %s; want %s", path, want)
+	}
+
+	for _, slug := range []string{"", "../escape", "../../etc/passwd", "a/b", "Hello World", "/abs"} {
+		if _, err := exportPath(dir, slug); err == nil {
+			t.Errorf("exportPath(%q) returned no error", slug)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+// Hand-written companion to the sqlc output: runs dynamically built list
+// queries (see internal/listquery) and scans rows into the generated models.
+// Keep the column lists in sync with models.go when the schema changes.
+
+package db
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+// Column lists matching the field order of the generated models.
+const (
+	PostColumns          = "id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta"
+	ProjectColumns       = "id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta"
+	UserColumns          = "id, username, email, password_hash, created_at, updated_at"
+	LogColumns           = "id, level, message, context, ip_address, created_at"
+	EventColumns         = "id, event_name, data, referrer, user_agent, session_id, ip_address, viewed_at, user_id"
+	CommentColumns       = "id, post_id, parent_id, user_id, author_name, author_email, body, body_html, status, spam_reasons, ip_hash, user_agent, created_at, updated_at"
+	NotFoundPathColumns  = "path, hits, last_referrer, first_seen_at, last_seen_at"
+	ProjectUpdateColumns = "id, project_id, title, body, body_html, version, link, published_at, created_at, updated_at"
+)
+
+// QueryPosts runs a query selecting PostColumns.
+func (q *Queries) QueryPosts(ctx context.Context, query string, args ...interface{}) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Summary,
+			&i.Content,
+			pq.Array(&i.Tags),
+			&i.IsDraft,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.ReactionCounts,
+			&i.CoverImage,
+			&i.CoverImageMeta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// QueryProjects runs a query selecting ProjectColumns.
+func (q *Queries) QueryProjects(ctx context.Context, query string, args ...interface{}) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.RepoUrl,
+			&i.LiveUrl,
+			&i.Summary,
+			pq.Array(&i.Tags),
+			&i.Footer,
+			&i.Href,
+			&i.External,
+			&i.Color,
+			&i.Emoji,
+			&i.Content,
+			&i.Image,
+			&i.Embed,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.ReactionCounts,
+			&i.Status,
+			&i.Featured,
+			&i.SortOrder,
+			&i.ImageMeta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// QueryUsers runs a query selecting UserColumns.
+func (q *Queries) QueryUsers(ctx context.Context, query string, args ...interface{}) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.PasswordHash,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// QueryLogs runs a query selecting LogColumns.
+func (q *Queries) QueryLogs(ctx context.Context, query string, args ...interface{}) ([]Log, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Log
+	for rows.Next() {
+		var i Log
+		if err := rows.Scan(
+			&i.ID,
+			&i.Level,
+			&i.Message,
+			&i.Context,
+			&i.IpAddress,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// QueryEvents runs a query selecting EventColumns.
+func (q *Queries) QueryEvents(ctx context.Context, query string, args ...interface{}) ([]Event, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventName,
+			&i.Data,
+			&i.Referrer,
+			&i.UserAgent,
+			&i.SessionID,
+			&i.IpAddress,
+			&i.ViewedAt,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// QueryComments runs a query selecting CommentColumns.
+func (q *Queries) QueryComments(ctx context.Context, query string, args ...interface{}) ([]Comment, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Comment
+	for rows.Next() {
+		var i Comment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.ParentID,
+			&i.UserID,
+			&i.AuthorName,
+			&i.AuthorEmail,
+			&i.Body,
+			&i.BodyHtml,
+			&i.Status,
+			pq.Array(&i.SpamReasons),
+			&i.IpHash,
+			&i.UserAgent,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// QueryNotFoundPaths runs a query selecting NotFoundPathColumns.
+func (q *Queries) QueryNotFoundPaths(ctx context.Context, query string, args ...interface{}) ([]NotFoundPath, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotFoundPath
+	for rows.Next() {
+		var i NotFoundPath
+		if err := rows.Scan(
+			&i.Path,
+			&i.Hits,
+			&i.LastReferrer,
+			&i.FirstSeenAt,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// QueryProjectUpdates runs a query selecting ProjectUpdateColumns.
+func (q *Queries) QueryProjectUpdates(ctx context.Context, query string, args ...interface{}) ([]ProjectUpdate, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectUpdate
+	for rows.Next() {
+		var i ProjectUpdate
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Title,
+			&i.Body,
+			&i.BodyHtml,
+			&i.Version,
+			&i.Link,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
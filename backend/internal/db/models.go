@@ -12,6 +12,31 @@ import (
 	"github.com/google/uuid"
 )
 
+type Comment struct {
+	ID          int32          `json:"id"`
+	PostID      int32          `json:"post_id"`
+	ParentID    sql.NullInt32  `json:"parent_id"`
+	UserID      sql.NullInt32  `json:"user_id"`
+	AuthorName  sql.NullString `json:"author_name"`
+	AuthorEmail sql.NullString `json:"author_email"`
+	Body        string         `json:"body"`
+	BodyHtml    string         `json:"body_html"`
+	Status      string         `json:"status"`
+	SpamReasons []string       `json:"spam_reasons"`
+	IpHash      sql.NullString `json:"ip_hash"`
+	UserAgent   sql.NullString `json:"user_agent"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+	UpdatedAt   sql.NullTime   `json:"updated_at"`
+}
+
+type DomainEvent struct {
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Payload   json.RawMessage `json:"payload"`
+	Origin    string          `json:"origin"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
 type Event struct {
 	ID        int32           `json:"id"`
 	EventName sql.NullString  `json:"event_name"`
@@ -33,6 +58,28 @@ type Log struct {
 	CreatedAt time.Time       `json:"created_at"`
 }
 
+type Media struct {
+	ID          int32           `json:"id"`
+	Key         string          `json:"key"`
+	Filename    string          `json:"filename"`
+	ContentType string          `json:"content_type"`
+	SizeBytes   int64           `json:"size_bytes"`
+	Width       int32           `json:"width"`
+	Height      int32           `json:"height"`
+	AltText     string          `json:"alt_text"`
+	Variants    json.RawMessage `json:"variants"`
+	CreatedAt   sql.NullTime    `json:"created_at"`
+	UpdatedAt   sql.NullTime    `json:"updated_at"`
+}
+
+type NotFoundPath struct {
+	Path         string         `json:"path"`
+	Hits         int64          `json:"hits"`
+	LastReferrer sql.NullString `json:"last_referrer"`
+	FirstSeenAt  sql.NullTime   `json:"first_seen_at"`
+	LastSeenAt   sql.NullTime   `json:"last_seen_at"`
+}
+
 type PageView struct {
 	ID        int32          `json:"id"`
 	Path      string         `json:"path"`
@@ -45,38 +92,112 @@ type PageView struct {
 }
 
 type Post struct {
-	ID        int32          `json:"id"`
-	Title     string         `json:"title"`
-	Slug      string         `json:"slug"`
-	Summary   sql.NullString `json:"summary"`
-	Content   string         `json:"content"`
-	Tags      []string       `json:"tags"`
-	IsDraft   sql.NullBool   `json:"is_draft"`
-	CreatedAt sql.NullTime   `json:"created_at"`
-	UpdatedAt sql.NullTime   `json:"updated_at"`
-	UserID    sql.NullInt32  `json:"user_id"`
+	ID             int32           `json:"id"`
+	Title          string          `json:"title"`
+	Slug           string          `json:"slug"`
+	Summary        sql.NullString  `json:"summary"`
+	Content        string          `json:"content"`
+	Tags           []string        `json:"tags"`
+	IsDraft        sql.NullBool    `json:"is_draft"`
+	CreatedAt      sql.NullTime    `json:"created_at"`
+	UpdatedAt      sql.NullTime    `json:"updated_at"`
+	UserID         sql.NullInt32   `json:"user_id"`
+	PublishedAt    sql.NullTime    `json:"published_at"`
+	ReactionCounts json.RawMessage `json:"reaction_counts"`
+	CoverImage     string          `json:"cover_image"`
+	CoverImageMeta json.RawMessage `json:"cover_image_meta"`
+}
+
+type PostProject struct {
+	PostID    int32        `json:"post_id"`
+	ProjectID int32        `json:"project_id"`
+	Source    string       `json:"source"`
+	CreatedAt sql.NullTime `json:"created_at"`
 }
 
 type Project struct {
+	ID             int32           `json:"id"`
+	Title          string          `json:"title"`
+	Slug           string          `json:"slug"`
+	Description    sql.NullString  `json:"description"`
+	RepoUrl        sql.NullString  `json:"repo_url"`
+	LiveUrl        sql.NullString  `json:"live_url"`
+	Summary        sql.NullString  `json:"summary"`
+	Tags           []string        `json:"tags"`
+	Footer         sql.NullString  `json:"footer"`
+	Href           sql.NullString  `json:"href"`
+	External       bool            `json:"external"`
+	Color          sql.NullString  `json:"color"`
+	Emoji          sql.NullString  `json:"emoji"`
+	Content        sql.NullString  `json:"content"`
+	Image          sql.NullString  `json:"image"`
+	Embed          sql.NullString  `json:"embed"`
+	CreatedAt      sql.NullTime    `json:"created_at"`
+	UpdatedAt      sql.NullTime    `json:"updated_at"`
+	UserID         sql.NullInt32   `json:"user_id"`
+	ReactionCounts json.RawMessage `json:"reaction_counts"`
+	Status         string          `json:"status"`
+	Featured       bool            `json:"featured"`
+	SortOrder      int32           `json:"sort_order"`
+	ImageMeta      json.RawMessage `json:"image_meta"`
+}
+
+type ProjectUpdate struct {
+	ID          int32          `json:"id"`
+	ProjectID   int32          `json:"project_id"`
+	Title       string         `json:"title"`
+	Body        string         `json:"body"`
+	BodyHtml    string         `json:"body_html"`
+	Version     sql.NullString `json:"version"`
+	Link        sql.NullString `json:"link"`
+	PublishedAt time.Time      `json:"published_at"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+	UpdatedAt   sql.NullTime   `json:"updated_at"`
+}
+
+type Reaction struct {
+	ID          int32         `json:"id"`
+	PostID      sql.NullInt32 `json:"post_id"`
+	ProjectID   sql.NullInt32 `json:"project_id"`
+	Kind        string        `json:"kind"`
+	VisitorHash string        `json:"visitor_hash"`
+	CreatedAt   sql.NullTime  `json:"created_at"`
+}
+
+type Redirect struct {
+	ID         int32        `json:"id"`
+	Source     string       `json:"source"`
+	Target     string       `json:"target"`
+	StatusCode int32        `json:"status_code"`
+	HitCount   int64        `json:"hit_count"`
+	LastHitAt  sql.NullTime `json:"last_hit_at"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+	UpdatedAt  sql.NullTime `json:"updated_at"`
+}
+
+type RelatedOverride struct {
+	ID         int32        `json:"id"`
+	SourceType string       `json:"source_type"`
+	SourceID   int32        `json:"source_id"`
+	TargetType string       `json:"target_type"`
+	TargetID   int32        `json:"target_id"`
+	Action     string       `json:"action"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+}
+
+type Series struct {
 	ID          int32          `json:"id"`
 	Title       string         `json:"title"`
 	Slug        string         `json:"slug"`
 	Description sql.NullString `json:"description"`
-	RepoUrl     sql.NullString `json:"repo_url"`
-	LiveUrl     sql.NullString `json:"live_url"`
-	Summary     sql.NullString `json:"summary"`
-	Tags        []string       `json:"tags"`
-	Footer      sql.NullString `json:"footer"`
-	Href        sql.NullString `json:"href"`
-	External    bool           `json:"external"`
-	Color       sql.NullString `json:"color"`
-	Emoji       sql.NullString `json:"emoji"`
-	Content     sql.NullString `json:"content"`
-	Image       sql.NullString `json:"image"`
-	Embed       sql.NullString `json:"embed"`
 	CreatedAt   sql.NullTime   `json:"created_at"`
 	UpdatedAt   sql.NullTime   `json:"updated_at"`
-	UserID      sql.NullInt32  `json:"user_id"`
+}
+
+type SeriesPost struct {
+	SeriesID int32 `json:"series_id"`
+	PostID   int32 `json:"post_id"`
+	Position int32 `json:"position"`
 }
 
 type Session struct {
@@ -88,6 +209,14 @@ type Session struct {
 	ExpiresAt sql.NullTime   `json:"expires_at"`
 }
 
+type SlugRedirect struct {
+	ID        int32         `json:"id"`
+	OldSlug   string        `json:"old_slug"`
+	PostID    sql.NullInt32 `json:"post_id"`
+	ProjectID sql.NullInt32 `json:"project_id"`
+	CreatedAt sql.NullTime  `json:"created_at"`
+}
+
 type User struct {
 	ID           int32          `json:"id"`
 	Username     string         `json:"username"`
@@ -8,24 +8,26 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/lib/pq"
 )
 
 const createPost = `-- name: CreatePost :one
-INSERT INTO posts (title, slug, summary, content, tags, is_draft, user_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id
+INSERT INTO posts (title, slug, summary, content, tags, is_draft, user_id, cover_image)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta
 `
 
 type CreatePostParams struct {
-	Title   string         `json:"title"`
-	Slug    string         `json:"slug"`
-	Summary sql.NullString `json:"summary"`
-	Content string         `json:"content"`
-	Tags    []string       `json:"tags"`
-	IsDraft sql.NullBool   `json:"is_draft"`
-	UserID  sql.NullInt32  `json:"user_id"`
+	Title      string         `json:"title"`
+	Slug       string         `json:"slug"`
+	Summary    sql.NullString `json:"summary"`
+	Content    string         `json:"content"`
+	Tags       []string       `json:"tags"`
+	IsDraft    sql.NullBool   `json:"is_draft"`
+	UserID     sql.NullInt32  `json:"user_id"`
+	CoverImage string         `json:"cover_image"`
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
@@ -37,6 +39,7 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		pq.Array(arg.Tags),
 		arg.IsDraft,
 		arg.UserID,
+		arg.CoverImage,
 	)
 	var i Post
 	err := row.Scan(
@@ -50,6 +53,10 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserID,
+		&i.PublishedAt,
+		&i.ReactionCounts,
+		&i.CoverImage,
+		&i.CoverImageMeta,
 	)
 	return i, err
 }
@@ -63,8 +70,62 @@ func (q *Queries) DeletePost(ctx context.Context, id int32) error {
 	return err
 }
 
+const getPostByID = `-- name: GetPostByID :one
+SELECT id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta FROM posts WHERE id = $1
+`
+
+func (q *Queries) GetPostByID(ctx context.Context, id int32) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByID, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Summary,
+		&i.Content,
+		pq.Array(&i.Tags),
+		&i.IsDraft,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.PublishedAt,
+		&i.ReactionCounts,
+		&i.CoverImage,
+		&i.CoverImageMeta,
+	)
+	return i, err
+}
+
+const getPostByIDForUpdate = `-- name: GetPostByIDForUpdate :one
+SELECT id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta FROM posts
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetPostByIDForUpdate(ctx context.Context, id int32) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByIDForUpdate, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Summary,
+		&i.Content,
+		pq.Array(&i.Tags),
+		&i.IsDraft,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.PublishedAt,
+		&i.ReactionCounts,
+		&i.CoverImage,
+		&i.CoverImageMeta,
+	)
+	return i, err
+}
+
 const getPostBySlug = `-- name: GetPostBySlug :one
-SELECT id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id FROM posts WHERE slug = $1
+SELECT id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta FROM posts WHERE slug = $1
 `
 
 func (q *Queries) GetPostBySlug(ctx context.Context, slug string) (Post, error) {
@@ -81,12 +142,59 @@ func (q *Queries) GetPostBySlug(ctx context.Context, slug string) (Post, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserID,
+		&i.PublishedAt,
+		&i.ReactionCounts,
+		&i.CoverImage,
+		&i.CoverImageMeta,
 	)
 	return i, err
 }
 
+const listAllPosts = `-- name: ListAllPosts :many
+SELECT id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta FROM posts
+ORDER BY slug
+`
+
+func (q *Queries) ListAllPosts(ctx context.Context) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listAllPosts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Summary,
+			&i.Content,
+			pq.Array(&i.Tags),
+			&i.IsDraft,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.ReactionCounts,
+			&i.CoverImage,
+			&i.CoverImageMeta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPosts = `-- name: ListPosts :many
-SELECT id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id FROM posts
+SELECT id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta FROM posts
 WHERE is_draft = FALSE
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
@@ -117,6 +225,10 @@ func (q *Queries) ListPosts(ctx context.Context, arg ListPostsParams) ([]Post, e
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.UserID,
+			&i.PublishedAt,
+			&i.ReactionCounts,
+			&i.CoverImage,
+			&i.CoverImageMeta,
 		); err != nil {
 			return nil, err
 		}
@@ -131,25 +243,198 @@ func (q *Queries) ListPosts(ctx context.Context, arg ListPostsParams) ([]Post, e
 	return items, nil
 }
 
+const listPostsByTag = `-- name: ListPostsByTag :many
+SELECT id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta FROM posts
+WHERE is_draft = FALSE AND $1::text = ANY(tags)
+ORDER BY COALESCE(published_at, created_at) DESC, id DESC
+`
+
+func (q *Queries) ListPostsByTag(ctx context.Context, tag string) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByTag, tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Summary,
+			&i.Content,
+			pq.Array(&i.Tags),
+			&i.IsDraft,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.ReactionCounts,
+			&i.CoverImage,
+			&i.CoverImageMeta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsWithStaleCoverImageMeta = `-- name: ListPostsWithStaleCoverImageMeta :many
+SELECT id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta FROM posts
+WHERE cover_image <> COALESCE(cover_image_meta->>'src', '')
+ORDER BY id
+`
+
+func (q *Queries) ListPostsWithStaleCoverImageMeta(ctx context.Context) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsWithStaleCoverImageMeta)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Summary,
+			&i.Content,
+			pq.Array(&i.Tags),
+			&i.IsDraft,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.ReactionCounts,
+			&i.CoverImage,
+			&i.CoverImageMeta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublishedPosts = `-- name: ListPublishedPosts :many
+SELECT id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta FROM posts
+WHERE is_draft = FALSE
+ORDER BY COALESCE(published_at, created_at) DESC, id DESC
+`
+
+func (q *Queries) ListPublishedPosts(ctx context.Context) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPublishedPosts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Summary,
+			&i.Content,
+			pq.Array(&i.Tags),
+			&i.IsDraft,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.ReactionCounts,
+			&i.CoverImage,
+			&i.CoverImageMeta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPostCoverImageMeta = `-- name: SetPostCoverImageMeta :one
+UPDATE posts
+SET cover_image_meta = $2
+WHERE id = $1
+RETURNING id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta
+`
+
+type SetPostCoverImageMetaParams struct {
+	ID             int32           `json:"id"`
+	CoverImageMeta json.RawMessage `json:"cover_image_meta"`
+}
+
+func (q *Queries) SetPostCoverImageMeta(ctx context.Context, arg SetPostCoverImageMetaParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, setPostCoverImageMeta,
+		arg.ID,
+		arg.CoverImageMeta,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Summary,
+		&i.Content,
+		pq.Array(&i.Tags),
+		&i.IsDraft,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.PublishedAt,
+		&i.ReactionCounts,
+		&i.CoverImage,
+		&i.CoverImageMeta,
+	)
+	return i, err
+}
+
 const updatePost = `-- name: UpdatePost :one
 UPDATE posts
 SET title = $2,
+    slug = $7,
     summary = $3,
     content = $4,
     tags = $5,
     is_draft = $6,
+    cover_image = $8,
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id
+RETURNING id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta
 `
 
 type UpdatePostParams struct {
-	ID      int32          `json:"id"`
-	Title   string         `json:"title"`
-	Summary sql.NullString `json:"summary"`
-	Content string         `json:"content"`
-	Tags    []string       `json:"tags"`
-	IsDraft sql.NullBool   `json:"is_draft"`
+	ID         int32          `json:"id"`
+	Title      string         `json:"title"`
+	Summary    sql.NullString `json:"summary"`
+	Content    string         `json:"content"`
+	Tags       []string       `json:"tags"`
+	IsDraft    sql.NullBool   `json:"is_draft"`
+	Slug       string         `json:"slug"`
+	CoverImage string         `json:"cover_image"`
 }
 
 func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, error) {
@@ -160,6 +445,62 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, e
 		arg.Content,
 		pq.Array(arg.Tags),
 		arg.IsDraft,
+		arg.Slug,
+		arg.CoverImage,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Summary,
+		&i.Content,
+		pq.Array(&i.Tags),
+		&i.IsDraft,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.PublishedAt,
+		&i.ReactionCounts,
+		&i.CoverImage,
+		&i.CoverImageMeta,
+	)
+	return i, err
+}
+
+const upsertPostBySlug = `-- name: UpsertPostBySlug :one
+INSERT INTO posts (title, slug, summary, content, tags, is_draft, published_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (slug) DO UPDATE
+SET title = EXCLUDED.title,
+    summary = EXCLUDED.summary,
+    content = EXCLUDED.content,
+    tags = EXCLUDED.tags,
+    is_draft = EXCLUDED.is_draft,
+    published_at = EXCLUDED.published_at,
+    updated_at = NOW()
+RETURNING id, title, slug, summary, content, tags, is_draft, created_at, updated_at, user_id, published_at, reaction_counts, cover_image, cover_image_meta
+`
+
+type UpsertPostBySlugParams struct {
+	Title       string         `json:"title"`
+	Slug        string         `json:"slug"`
+	Summary     sql.NullString `json:"summary"`
+	Content     string         `json:"content"`
+	Tags        []string       `json:"tags"`
+	IsDraft     sql.NullBool   `json:"is_draft"`
+	PublishedAt sql.NullTime   `json:"published_at"`
+}
+
+func (q *Queries) UpsertPostBySlug(ctx context.Context, arg UpsertPostBySlugParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, upsertPostBySlug,
+		arg.Title,
+		arg.Slug,
+		arg.Summary,
+		arg.Content,
+		pq.Array(arg.Tags),
+		arg.IsDraft,
+		arg.PublishedAt,
 	)
 	var i Post
 	err := row.Scan(
@@ -173,6 +514,10 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserID,
+		&i.PublishedAt,
+		&i.ReactionCounts,
+		&i.CoverImage,
+		&i.CoverImageMeta,
 	)
 	return i, err
 }
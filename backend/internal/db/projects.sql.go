@@ -8,6 +8,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/lib/pq"
 )
@@ -16,14 +17,14 @@ const createProject = `-- name: CreateProject :one
 INSERT INTO projects (
     title, slug, description, repo_url, live_url,
     summary, tags, footer, href, external, color, emoji, content, image, embed,
-    user_id
+    user_id, status, featured, sort_order
 )
 VALUES (
     $1, $2, $3, $4, $5,
     $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
-    $16
+    $16, $17, $18, $19
 )
-RETURNING id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id
+RETURNING id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta
 `
 
 type CreateProjectParams struct {
@@ -43,6 +44,9 @@ type CreateProjectParams struct {
 	Image       sql.NullString `json:"image"`
 	Embed       sql.NullString `json:"embed"`
 	UserID      sql.NullInt32  `json:"user_id"`
+	Status      string         `json:"status"`
+	Featured    bool           `json:"featured"`
+	SortOrder   int32          `json:"sort_order"`
 }
 
 func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error) {
@@ -63,6 +67,9 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		arg.Image,
 		arg.Embed,
 		arg.UserID,
+		arg.Status,
+		arg.Featured,
+		arg.SortOrder,
 	)
 	var i Project
 	err := row.Scan(
@@ -85,6 +92,11 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserID,
+		&i.ReactionCounts,
+		&i.Status,
+		&i.Featured,
+		&i.SortOrder,
+		&i.ImageMeta,
 	)
 	return i, err
 }
@@ -99,8 +111,83 @@ func (q *Queries) DeleteProject(ctx context.Context, id int32) error {
 	return err
 }
 
+const getProjectByID = `-- name: GetProjectByID :one
+SELECT id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta FROM projects
+WHERE id = $1
+`
+
+func (q *Queries) GetProjectByID(ctx context.Context, id int32) (Project, error) {
+	row := q.db.QueryRowContext(ctx, getProjectByID, id)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.RepoUrl,
+		&i.LiveUrl,
+		&i.Summary,
+		pq.Array(&i.Tags),
+		&i.Footer,
+		&i.Href,
+		&i.External,
+		&i.Color,
+		&i.Emoji,
+		&i.Content,
+		&i.Image,
+		&i.Embed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.ReactionCounts,
+		&i.Status,
+		&i.Featured,
+		&i.SortOrder,
+		&i.ImageMeta,
+	)
+	return i, err
+}
+
+const getProjectByIDForUpdate = `-- name: GetProjectByIDForUpdate :one
+SELECT id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta FROM projects
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetProjectByIDForUpdate(ctx context.Context, id int32) (Project, error) {
+	row := q.db.QueryRowContext(ctx, getProjectByIDForUpdate, id)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.RepoUrl,
+		&i.LiveUrl,
+		&i.Summary,
+		pq.Array(&i.Tags),
+		&i.Footer,
+		&i.Href,
+		&i.External,
+		&i.Color,
+		&i.Emoji,
+		&i.Content,
+		&i.Image,
+		&i.Embed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.ReactionCounts,
+		&i.Status,
+		&i.Featured,
+		&i.SortOrder,
+		&i.ImageMeta,
+	)
+	return i, err
+}
+
 const getProjectBySlug = `-- name: GetProjectBySlug :one
-SELECT id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id FROM projects
+SELECT id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta FROM projects
 WHERE slug = $1
 `
 
@@ -127,13 +214,18 @@ func (q *Queries) GetProjectBySlug(ctx context.Context, slug string) (Project, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserID,
+		&i.ReactionCounts,
+		&i.Status,
+		&i.Featured,
+		&i.SortOrder,
+		&i.ImageMeta,
 	)
 	return i, err
 }
 
 const listProjects = `-- name: ListProjects :many
-SELECT id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id FROM projects
-ORDER BY created_at DESC
+SELECT id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta FROM projects
+ORDER BY featured DESC, sort_order, created_at DESC
 `
 
 func (q *Queries) ListProjects(ctx context.Context) ([]Project, error) {
@@ -165,6 +257,65 @@ func (q *Queries) ListProjects(ctx context.Context) ([]Project, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.UserID,
+			&i.ReactionCounts,
+			&i.Status,
+			&i.Featured,
+			&i.SortOrder,
+			&i.ImageMeta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectsByTag = `-- name: ListProjectsByTag :many
+SELECT id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta FROM projects
+WHERE $1::text = ANY(tags) AND status <> 'draft'
+ORDER BY featured DESC, sort_order, created_at DESC
+`
+
+func (q *Queries) ListProjectsByTag(ctx context.Context, tag string) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectsByTag, tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.RepoUrl,
+			&i.LiveUrl,
+			&i.Summary,
+			pq.Array(&i.Tags),
+			&i.Footer,
+			&i.Href,
+			&i.External,
+			&i.Color,
+			&i.Emoji,
+			&i.Content,
+			&i.Image,
+			&i.Embed,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.ReactionCounts,
+			&i.Status,
+			&i.Featured,
+			&i.SortOrder,
+			&i.ImageMeta,
 		); err != nil {
 			return nil, err
 		}
@@ -179,9 +330,144 @@ func (q *Queries) ListProjects(ctx context.Context) ([]Project, error) {
 	return items, nil
 }
 
+const listProjectsWithStaleImageMeta = `-- name: ListProjectsWithStaleImageMeta :many
+SELECT id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta FROM projects
+WHERE COALESCE(image, '') <> COALESCE(image_meta->>'src', '')
+ORDER BY id
+`
+
+func (q *Queries) ListProjectsWithStaleImageMeta(ctx context.Context) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectsWithStaleImageMeta)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.RepoUrl,
+			&i.LiveUrl,
+			&i.Summary,
+			pq.Array(&i.Tags),
+			&i.Footer,
+			&i.Href,
+			&i.External,
+			&i.Color,
+			&i.Emoji,
+			&i.Content,
+			&i.Image,
+			&i.Embed,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.ReactionCounts,
+			&i.Status,
+			&i.Featured,
+			&i.SortOrder,
+			&i.ImageMeta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setProjectImageMeta = `-- name: SetProjectImageMeta :one
+UPDATE projects
+SET image_meta = $2
+WHERE id = $1
+RETURNING id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta
+`
+
+type SetProjectImageMetaParams struct {
+	ID        int32           `json:"id"`
+	ImageMeta json.RawMessage `json:"image_meta"`
+}
+
+func (q *Queries) SetProjectImageMeta(ctx context.Context, arg SetProjectImageMetaParams) (Project, error) {
+	row := q.db.QueryRowContext(ctx, setProjectImageMeta,
+		arg.ID,
+		arg.ImageMeta,
+	)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.RepoUrl,
+		&i.LiveUrl,
+		&i.Summary,
+		pq.Array(&i.Tags),
+		&i.Footer,
+		&i.Href,
+		&i.External,
+		&i.Color,
+		&i.Emoji,
+		&i.Content,
+		&i.Image,
+		&i.Embed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.ReactionCounts,
+		&i.Status,
+		&i.Featured,
+		&i.SortOrder,
+		&i.ImageMeta,
+	)
+	return i, err
+}
+
+const setProjectSortOrder = `-- name: SetProjectSortOrder :execrows
+UPDATE projects
+SET sort_order = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type SetProjectSortOrderParams struct {
+	ID        int32 `json:"id"`
+	SortOrder int32 `json:"sort_order"`
+}
+
+func (q *Queries) SetProjectSortOrder(ctx context.Context, arg SetProjectSortOrderParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setProjectSortOrder,
+		arg.ID,
+		arg.SortOrder,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const touchProject = `-- name: TouchProject :exec
+UPDATE projects
+SET updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) TouchProject(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, touchProject, id)
+	return err
+}
+
 const updateProject = `-- name: UpdateProject :one
 UPDATE projects
 SET title = $2,
+    slug = $16,
     description = $3,
     repo_url = $4,
     live_url = $5,
@@ -195,9 +481,12 @@ SET title = $2,
     content = $13,
     image = $14,
     embed = $15,
+    status = $17,
+    featured = $18,
+    sort_order = $19,
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id
+RETURNING id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta
 `
 
 type UpdateProjectParams struct {
@@ -216,6 +505,10 @@ type UpdateProjectParams struct {
 	Content     sql.NullString `json:"content"`
 	Image       sql.NullString `json:"image"`
 	Embed       sql.NullString `json:"embed"`
+	Slug        string         `json:"slug"`
+	Status      string         `json:"status"`
+	Featured    bool           `json:"featured"`
+	SortOrder   int32          `json:"sort_order"`
 }
 
 func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error) {
@@ -235,6 +528,103 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		arg.Content,
 		arg.Image,
 		arg.Embed,
+		arg.Slug,
+		arg.Status,
+		arg.Featured,
+		arg.SortOrder,
+	)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.RepoUrl,
+		&i.LiveUrl,
+		&i.Summary,
+		pq.Array(&i.Tags),
+		&i.Footer,
+		&i.Href,
+		&i.External,
+		&i.Color,
+		&i.Emoji,
+		&i.Content,
+		&i.Image,
+		&i.Embed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.ReactionCounts,
+		&i.Status,
+		&i.Featured,
+		&i.SortOrder,
+		&i.ImageMeta,
+	)
+	return i, err
+}
+
+const upsertProjectBySlug = `-- name: UpsertProjectBySlug :one
+INSERT INTO projects (
+    title, slug, summary, tags, footer, href, external, color, emoji, content, image, embed,
+    status, featured, sort_order
+)
+VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+)
+ON CONFLICT (slug) DO UPDATE
+SET title = EXCLUDED.title,
+    summary = EXCLUDED.summary,
+    tags = EXCLUDED.tags,
+    footer = EXCLUDED.footer,
+    href = EXCLUDED.href,
+    external = EXCLUDED.external,
+    color = EXCLUDED.color,
+    emoji = EXCLUDED.emoji,
+    content = EXCLUDED.content,
+    image = EXCLUDED.image,
+    embed = EXCLUDED.embed,
+    status = EXCLUDED.status,
+    featured = EXCLUDED.featured,
+    sort_order = EXCLUDED.sort_order,
+    updated_at = NOW()
+RETURNING id, title, slug, description, repo_url, live_url, summary, tags, footer, href, external, color, emoji, content, image, embed, created_at, updated_at, user_id, reaction_counts, status, featured, sort_order, image_meta
+`
+
+type UpsertProjectBySlugParams struct {
+	Title     string         `json:"title"`
+	Slug      string         `json:"slug"`
+	Summary   sql.NullString `json:"summary"`
+	Tags      []string       `json:"tags"`
+	Footer    sql.NullString `json:"footer"`
+	Href      sql.NullString `json:"href"`
+	External  bool           `json:"external"`
+	Color     sql.NullString `json:"color"`
+	Emoji     sql.NullString `json:"emoji"`
+	Content   sql.NullString `json:"content"`
+	Image     sql.NullString `json:"image"`
+	Embed     sql.NullString `json:"embed"`
+	Status    string         `json:"status"`
+	Featured  bool           `json:"featured"`
+	SortOrder int32          `json:"sort_order"`
+}
+
+func (q *Queries) UpsertProjectBySlug(ctx context.Context, arg UpsertProjectBySlugParams) (Project, error) {
+	row := q.db.QueryRowContext(ctx, upsertProjectBySlug,
+		arg.Title,
+		arg.Slug,
+		arg.Summary,
+		pq.Array(arg.Tags),
+		arg.Footer,
+		arg.Href,
+		arg.External,
+		arg.Color,
+		arg.Emoji,
+		arg.Content,
+		arg.Image,
+		arg.Embed,
+		arg.Status,
+		arg.Featured,
+		arg.SortOrder,
 	)
 	var i Project
 	err := row.Scan(
@@ -257,6 +647,11 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserID,
+		&i.ReactionCounts,
+		&i.Status,
+		&i.Featured,
+		&i.SortOrder,
+		&i.ImageMeta,
 	)
 	return i, err
 }
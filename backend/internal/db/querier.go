@@ -7,32 +7,73 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddPostReaction(ctx context.Context, arg AddPostReactionParams) (int64, error)
+	AddProjectReaction(ctx context.Context, arg AddProjectReactionParams) (int64, error)
+	AddSeriesPost(ctx context.Context, arg AddSeriesPostParams) error
+	AdjustPostReactionCount(ctx context.Context, arg AdjustPostReactionCountParams) (json.RawMessage, error)
+	AdjustProjectReactionCount(ctx context.Context, arg AdjustProjectReactionCountParams) (json.RawMessage, error)
+	AttachPostProject(ctx context.Context, arg AttachPostProjectParams) error
+	ClearDetectedPostProjects(ctx context.Context, post_id int32) error
+	ClearSeriesPosts(ctx context.Context, series_id int32) error
 	CountEvents(ctx context.Context) (int64, error)
+	CountRecentCommentsByIP(ctx context.Context, arg CountRecentCommentsByIPParams) (int64, error)
 	CountViewsByPath(ctx context.Context, path string) (int64, error)
+	CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error)
 	CreateEvent(ctx context.Context, arg CreateEventParams) error
 	CreateLog(ctx context.Context, arg CreateLogParams) (Log, error)
+	CreateMedia(ctx context.Context, arg CreateMediaParams) (Media, error)
 	CreatePageView(ctx context.Context, arg CreatePageViewParams) error
 	CreatePost(ctx context.Context, arg CreatePostParams) (Post, error)
+	CreatePostSlugRedirect(ctx context.Context, arg CreatePostSlugRedirectParams) error
 	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
+	CreateProjectSlugRedirect(ctx context.Context, arg CreateProjectSlugRedirectParams) error
+	CreateProjectUpdate(ctx context.Context, arg CreateProjectUpdateParams) (ProjectUpdate, error)
+	CreateRedirect(ctx context.Context, arg CreateRedirectParams) (Redirect, error)
+	CreateRelatedOverride(ctx context.Context, arg CreateRelatedOverrideParams) (RelatedOverride, error)
+	CreateSeries(ctx context.Context, arg CreateSeriesParams) (Series, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateUserWithPassword(ctx context.Context, arg CreateUserWithPasswordParams) (User, error)
+	DeleteDomainEventsBefore(ctx context.Context, created_at time.Time) (int64, error)
 	DeleteLog(ctx context.Context, id int32) error
+	DeleteMedia(ctx context.Context, id int32) (Media, error)
+	DeleteNotFoundPath(ctx context.Context, path string) (int64, error)
 	DeletePost(ctx context.Context, id int32) error
+	DeletePostSlugRedirect(ctx context.Context, old_slug string) error
 	DeleteProject(ctx context.Context, id int32) error
+	DeleteProjectSlugRedirect(ctx context.Context, old_slug string) error
+	DeleteProjectUpdate(ctx context.Context, arg DeleteProjectUpdateParams) (int64, error)
+	DeleteRedirect(ctx context.Context, id int32) (int64, error)
+	DeleteRelatedOverride(ctx context.Context, id int32) (int64, error)
+	DeleteSeries(ctx context.Context, id int32) (int64, error)
 	DeleteSession(ctx context.Context, id uuid.UUID) error
 	DeleteUser(ctx context.Context, id int32) error
+	DetachPostProject(ctx context.Context, arg DetachPostProjectParams) (int64, error)
 	ExpireSession(ctx context.Context, id uuid.UUID) error
+	GetCommentByID(ctx context.Context, id int32) (Comment, error)
 	GetEventsByName(ctx context.Context, arg GetEventsByNameParams) ([]Event, error)
 	GetEventsCountByNameLastNDays(ctx context.Context, dollar_1 sql.NullString) ([]GetEventsCountByNameLastNDaysRow, error)
+	GetLatestDomainEventID(ctx context.Context) (int64, error)
 	GetLogByID(ctx context.Context, id int32) (Log, error)
+	GetMedia(ctx context.Context, id int32) (Media, error)
+	GetMediaByKey(ctx context.Context, key string) (Media, error)
+	GetPostByID(ctx context.Context, id int32) (Post, error)
+	GetPostByIDForUpdate(ctx context.Context, id int32) (Post, error)
 	GetPostBySlug(ctx context.Context, slug string) (Post, error)
+	GetProjectByID(ctx context.Context, id int32) (Project, error)
+	GetProjectByIDForUpdate(ctx context.Context, id int32) (Project, error)
 	GetProjectBySlug(ctx context.Context, slug string) (Project, error)
+	GetRedirectByID(ctx context.Context, id int32) (Redirect, error)
+	GetSeriesByID(ctx context.Context, id int32) (Series, error)
+	GetSeriesByPost(ctx context.Context, post_id int32) (Series, error)
+	GetSeriesBySlug(ctx context.Context, slug string) (Series, error)
 	GetSessionByID(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTotalEventsLastNDays(ctx context.Context, dollar_1 sql.NullString) (int64, error)
 	GetTotalViewsLastNDays(ctx context.Context, dollar_1 sql.NullString) (int64, error)
@@ -40,20 +81,67 @@ type Querier interface {
 	GetUserByID(ctx context.Context, id int32) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
 	GetUserForAuth(ctx context.Context, username string) (User, error)
+	GetUsersByIDs(ctx context.Context, ids []int32) ([]User, error)
 	GetValidSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetViewsByPath(ctx context.Context, arg GetViewsByPathParams) ([]PageView, error)
 	GetViewsCountByPathLastNDays(ctx context.Context, dollar_1 sql.NullString) ([]GetViewsCountByPathLastNDaysRow, error)
+	IncrementRedirectHits(ctx context.Context, id int32) error
+	InsertDomainEvent(ctx context.Context, arg InsertDomainEventParams) (DomainEvent, error)
+	ListAllPosts(ctx context.Context) ([]Post, error)
+	ListApprovedCommentsByPost(ctx context.Context, postID int32) ([]Comment, error)
+	ListDomainEventsAfter(ctx context.Context, arg ListDomainEventsAfterParams) ([]DomainEvent, error)
 	// @param event_name:nullable
 	// @param session_id:nullable
 	ListEvents(ctx context.Context, arg ListEventsParams) ([]Event, error)
 	ListLogs(ctx context.Context, arg ListLogsParams) ([]Log, error)
+	ListMedia(ctx context.Context) ([]Media, error)
+	ListPostReactionsByVisitor(ctx context.Context, arg ListPostReactionsByVisitorParams) ([]string, error)
+	ListPostSlugRedirects(ctx context.Context, post_id sql.NullInt32) ([]string, error)
 	ListPosts(ctx context.Context, arg ListPostsParams) ([]Post, error)
+	ListPostsByTag(ctx context.Context, tag string) ([]Post, error)
+	ListPostsWithStaleCoverImageMeta(ctx context.Context) ([]Post, error)
+	ListProjectIDsBySlugs(ctx context.Context, slugs []string) ([]int32, error)
+	ListProjectReactionsByVisitor(ctx context.Context, arg ListProjectReactionsByVisitorParams) ([]string, error)
+	ListProjectSlugRedirects(ctx context.Context, project_id sql.NullInt32) ([]string, error)
+	ListProjectUpdates(ctx context.Context, project_id int32) ([]ProjectUpdate, error)
 	ListProjects(ctx context.Context) ([]Project, error)
+	ListProjectsByPost(ctx context.Context, post_id int32) ([]Project, error)
+	ListProjectsByTag(ctx context.Context, tag string) ([]Project, error)
+	ListProjectsWithStaleImageMeta(ctx context.Context) ([]Project, error)
+	ListPublishedPosts(ctx context.Context) ([]Post, error)
+	ListPublishedPostsByProject(ctx context.Context, project_id int32) ([]Post, error)
+	ListRecentProjectUpdates(ctx context.Context, limit int32) ([]ListRecentProjectUpdatesRow, error)
+	ListRedirects(ctx context.Context) ([]Redirect, error)
+	ListRelatedOverrides(ctx context.Context) ([]RelatedOverride, error)
+	ListRelatedPostCandidates(ctx context.Context) ([]ListRelatedPostCandidatesRow, error)
+	ListRelatedProjectCandidates(ctx context.Context) ([]ListRelatedProjectCandidatesRow, error)
+	ListSeries(ctx context.Context) ([]Series, error)
+	ListSeriesPosts(ctx context.Context, series_id int32) ([]Post, error)
+	ListSessionPageViews(ctx context.Context, viewed_at time.Time) ([]ListSessionPageViewsRow, error)
 	ListSessionsByUser(ctx context.Context, userID sql.NullInt32) ([]Session, error)
+	ListTags(ctx context.Context) ([]ListTagsRow, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
 	PatchUser(ctx context.Context, arg PatchUserParams) (User, error)
+	PostSlugTaken(ctx context.Context, slug string) (bool, error)
+	ProjectSlugTaken(ctx context.Context, slug string) (bool, error)
+	RecordNotFound(ctx context.Context, arg RecordNotFoundParams) error
+	RemovePostReaction(ctx context.Context, arg RemovePostReactionParams) (int64, error)
+	RemoveProjectReaction(ctx context.Context, arg RemoveProjectReactionParams) (int64, error)
+	ResolvePostSlugRedirect(ctx context.Context, old_slug string) (string, error)
+	ResolveProjectSlugRedirect(ctx context.Context, old_slug string) (string, error)
+	SetCommentStatus(ctx context.Context, arg SetCommentStatusParams) (int64, error)
+	SetPostCoverImageMeta(ctx context.Context, arg SetPostCoverImageMetaParams) (Post, error)
+	SetProjectImageMeta(ctx context.Context, arg SetProjectImageMetaParams) (Project, error)
+	SetProjectSortOrder(ctx context.Context, arg SetProjectSortOrderParams) (int64, error)
+	TouchProject(ctx context.Context, id int32) error
+	UpdateMediaAltText(ctx context.Context, arg UpdateMediaAltTextParams) (Media, error)
 	UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, error)
 	UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error)
+	UpdateProjectUpdate(ctx context.Context, arg UpdateProjectUpdateParams) (ProjectUpdate, error)
+	UpdateRedirect(ctx context.Context, arg UpdateRedirectParams) (Redirect, error)
+	UpdateSeries(ctx context.Context, arg UpdateSeriesParams) (Series, error)
+	UpsertPostBySlug(ctx context.Context, arg UpsertPostBySlugParams) (Post, error)
+	UpsertProjectBySlug(ctx context.Context, arg UpsertProjectBySlugParams) (Project, error)
 }
 
 var _ Querier = (*Queries)(nil)
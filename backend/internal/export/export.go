@@ -0,0 +1,255 @@
+// Package export renders the public API to static files for hosts that cannot
+// run the Go server (GitHub Pages). Every file is produced by calling the real
+// handlers in-process, so the static output matches the live API byte for byte.
+//
+// Layout, relative to the output directory (the client's API base):
+//
+//	projects.json                 GET /projects (all pages, concatenated)
+//	projects/<slug>.json          GET /projects/{slug}
+//	projects/<slug>/updates.json  GET /projects/{slug}/updates (all pages, concatenated)
+//	posts/index.json              {"page_size", "pages", "total"}
+//	posts/page/<n>.json           GET /posts?limit=<page_size>&offset=<(n-1)*page_size>
+//	posts/<slug>.json             GET /posts/{slug}
+//	tags.json                     GET /tags
+//	tags/<tag>.json               GET /tags/{tag}
+//	series.json                   GET /series
+//	series/<slug>.json            GET /series/{slug}
+//	feed.xml, feed.json           GET /feed.xml, GET /feed.json
+//	sitemap.xml                   GET /sitemap.xml
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// projectPageSize is the page size used to walk GET /projects.
+const projectPageSize = 500
+
+// Exporter renders pages from Handler into Out.
+type Exporter struct {
+	Handler  http.Handler
+	Out      *Writer
+	PageSize int
+}
+
+type pageIndex struct {
+	PageSize int `json:"page_size"`
+	Pages    int `json:"pages"`
+	Total    int `json:"total"`
+}
+
+// Run exports every public resource.
+func (e *Exporter) Run() error {
+	if e.PageSize <= 0 {
+		e.PageSize = 10
+	}
+	steps := []func() error{e.projects, e.posts, e.tags, e.series, e.feeds}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return e.Out.Finish()
+}
+
+func (e *Exporter) projects() error {
+	all, err := e.collect("/projects", projectPageSize)
+	if err != nil {
+		return err
+	}
+	if err := e.writeJSON("projects.json", all); err != nil {
+		return err
+	}
+	for _, raw := range all {
+		slug, err := slugOf(raw)
+		if err != nil {
+			return err
+		}
+		if !safeName(slug) {
+			continue
+		}
+		if err := e.copy("/projects/"+url.PathEscape(slug), "projects/"+slug+".json"); err != nil {
+			return err
+		}
+		updates, err := e.collect("/projects/"+url.PathEscape(slug)+"/updates", e.PageSize)
+		if err != nil {
+			return err
+		}
+		if err := e.writeJSON("projects/"+slug+"/updates.json", updates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) posts() error {
+	all, err := e.collect("/posts", e.PageSize)
+	if err != nil {
+		return err
+	}
+
+	pages := (len(all) + e.PageSize - 1) / e.PageSize
+	if pages == 0 {
+		pages = 1 // an empty first page keeps the client's first fetch valid
+	}
+	for n := 1; n <= pages; n++ {
+		lo := (n - 1) * e.PageSize
+		hi := lo + e.PageSize
+		if hi > len(all) {
+			hi = len(all)
+		}
+		if err := e.writeJSON(fmt.Sprintf("posts/page/%d.json", n), all[lo:hi]); err != nil {
+			return err
+		}
+	}
+	if err := e.writeJSON("posts/index.json", pageIndex{PageSize: e.PageSize, Pages: pages, Total: len(all)}); err != nil {
+		return err
+	}
+
+	for _, raw := range all {
+		slug, err := slugOf(raw)
+		if err != nil {
+			return err
+		}
+		if !safeName(slug) {
+			continue
+		}
+		if err := e.copy("/posts/"+url.PathEscape(slug), "posts/"+slug+".json"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) tags() error {
+	body, err := e.get("/tags")
+	if err != nil {
+		return err
+	}
+	if err := e.Out.Write("tags.json", body); err != nil {
+		return err
+	}
+
+	var tags []struct {
+		Tag string `json:"tag"`
+	}
+	if err := json.Unmarshal(body, &tags); err != nil {
+		return fmt.Errorf("decode /tags: %w", err)
+	}
+	for _, t := range tags {
+		if !safeName(t.Tag) {
+			continue // not representable as a file name
+		}
+		if err := e.copy("/tags/"+url.PathEscape(t.Tag), "tags/"+t.Tag+".json"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) series() error {
+	body, err := e.get("/series")
+	if err != nil {
+		return err
+	}
+	if err := e.Out.Write("series.json", body); err != nil {
+		return err
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(body, &list); err != nil {
+		return fmt.Errorf("decode /series: %w", err)
+	}
+	for _, raw := range list {
+		slug, err := slugOf(raw)
+		if err != nil {
+			return err
+		}
+		if !safeName(slug) {
+			continue
+		}
+		if err := e.copy("/series/"+url.PathEscape(slug), "series/"+slug+".json"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) feeds() error {
+	for _, name := range []string{"feed.xml", "feed.json", "sitemap.xml"} {
+		if err := e.copy("/"+name, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect walks a paginated list endpoint until a short page is returned.
+func (e *Exporter) collect(path string, pageSize int) ([]json.RawMessage, error) {
+	all := []json.RawMessage{}
+	for offset := 0; ; offset += pageSize {
+		q := url.Values{}
+		q.Set("limit", strconv.Itoa(pageSize))
+		q.Set("offset", strconv.Itoa(offset))
+		body, err := e.get(path + "?" + q.Encode())
+		if err != nil {
+			return nil, err
+		}
+		var page []json.RawMessage
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", path, err)
+		}
+		all = append(all, page...)
+		if len(page) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+func (e *Exporter) copy(path, rel string) error {
+	body, err := e.get(path)
+	if err != nil {
+		return err
+	}
+	return e.Out.Write(rel, body)
+}
+
+// writeJSON encodes v the same way the handlers do (json.Encoder, trailing newline).
+func (e *Exporter) writeJSON(rel string, v interface{}) error {
+	var b strings.Builder
+	if err := json.NewEncoder(&b).Encode(v); err != nil {
+		return err
+	}
+	return e.Out.Write(rel, []byte(b.String()))
+}
+
+func (e *Exporter) get(path string) ([]byte, error) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	e.Handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %d %s", path, rec.Code, strings.TrimSpace(rec.Body.String()))
+	}
+	return rec.Body.Bytes(), nil
+}
+
+func slugOf(raw json.RawMessage) (string, error) {
+	var v struct {
+		Slug string `json:"slug"`
+	}
+	if err := json.Unmarshal(raw, &v); err != nil || v.Slug == "" {
+		return "", fmt.Errorf("list item without a slug: %s", raw)
+	}
+	return v.Slug, nil
+}
+
+// safeName reports whether s can be used as a single path segment on disk.
+func safeName(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, `/\`)
+}
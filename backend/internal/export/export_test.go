@@ -0,0 +1,118 @@
+package export
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fakeAPI() http.Handler {
+	mux := http.NewServeMux()
+	routes := map[string]string{
+		"/projects":               `[{"slug":"alpha"}]`,
+		"/projects/alpha":         `{"slug":"alpha"}`,
+		"/projects/alpha/updates": `[{"id":1}]`,
+		"/posts/one":              `{"slug":"one"}`,
+		"/posts/two":              `{"slug":"two"}`,
+		"/posts/three":            `{"slug":"three"}`,
+		"/tags":                   `[{"tag":"go"},{"tag":"a/b"}]`,
+		"/tags/go":                `{"tag":"go"}`,
+		"/series":                 `[{"slug":"intro"}]`,
+		"/series/intro":           `{"slug":"intro","posts":[]}`,
+		"/feed.xml":               `<rss/>`,
+		"/feed.json":              `{}`,
+		"/sitemap.xml":            `<urlset/>`,
+	}
+	for path, body := range routes {
+		body := body
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/posts" {
+				return
+			}
+			_, _ = w.Write([]byte(body + "\n"))
+		})
+	}
+	mux.HandleFunc("/posts", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			_, _ = w.Write([]byte(`[{"slug":"one"},{"slug":"two"}]` + "\n"))
+		case "2":
+			_, _ = w.Write([]byte(`[{"slug":"three"}]` + "\n"))
+		default:
+			_, _ = w.Write([]byte("[]\n"))
+		}
+	})
+	return mux
+}
+
+func runExport(t *testing.T, dir string) *Writer {
+	t.Helper()
+	out, err := NewWriter(dir, false)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	e := &Exporter{Handler: fakeAPI(), Out: out, PageSize: 2}
+	if err := e.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return out
+}
+
+func TestExportLayout(t *testing.T) {
+	dir := t.TempDir()
+	out := runExport(t, dir)
+
+	want := []string{
+		"feed.json", "feed.xml",
+		"posts/index.json", "posts/one.json", "posts/page/1.json", "posts/page/2.json",
+		"posts/three.json", "posts/two.json",
+		"projects.json", "projects/alpha.json", "projects/alpha/updates.json",
+		"series.json", "series/intro.json",
+		"sitemap.xml", "tags.json", "tags/go.json",
+	}
+	if got := strings.Join(out.Paths(), ","); got != strings.Join(want, ",") {
+		t.Errorf("paths = %v\nwant    %v", out.Paths(), want)
+	}
+
+	index, _ := os.ReadFile(filepath.Join(dir, "posts/index.json"))
+	if strings.TrimSpace(string(index)) != `{"page_size":2,"pages":2,"total":3}` {
+		t.Errorf("posts/index.json = %s", index)
+	}
+	page2, _ := os.ReadFile(filepath.Join(dir, "posts/page/2.json"))
+	if strings.TrimSpace(string(page2)) != `[{"slug":"three"}]` {
+		t.Errorf("posts/page/2.json = %s", page2)
+	}
+}
+
+func TestExportKeepsUnchangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	runExport(t, dir)
+
+	path := filepath.Join(dir, "projects.json")
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(dir, "posts", "deleted.json")
+	if err := os.WriteFile(stale, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runExport(t, dir)
+	if out.Stats.Written != 0 {
+		t.Errorf("second export wrote %d file(s); want 0", out.Stats.Written)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(old) {
+		t.Errorf("unchanged file mtime moved from %v to %v", old, info.ModTime())
+	}
+	if _, err := os.Stat(stale); err != nil {
+		t.Errorf("file not written by the exporter was removed: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package export
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ManifestFile records the hash of every file written by the last export.
+const ManifestFile = ".export-manifest.json"
+
+// Stats counts what a Writer did.
+type Stats struct {
+	Written   int
+	Unchanged int
+	Removed   int
+}
+
+// Writer writes files under Dir, skipping files whose content is unchanged so
+// that their modification times survive re-exports.
+type Writer struct {
+	Dir    string
+	DryRun bool
+
+	prev  map[string]string
+	next  map[string]string
+	Stats Stats
+}
+
+// NewWriter loads the previous manifest from dir, if any.
+func NewWriter(dir string, dryRun bool) (*Writer, error) {
+	w := &Writer{Dir: dir, DryRun: dryRun, prev: map[string]string{}, next: map[string]string{}}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return w, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &w.prev); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write stores data at the slash-separated path rel, unless the file already
+// holds exactly that content.
+func (w *Writer) Write(rel string, data []byte) error {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	w.next[rel] = hash
+
+	path := filepath.Join(w.Dir, filepath.FromSlash(rel))
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+		w.Stats.Unchanged++
+		return nil
+	}
+	w.Stats.Written++
+	if w.DryRun {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Finish removes files the previous export wrote that this one did not, then
+// saves the new manifest.
+func (w *Writer) Finish() error {
+	var stale []string
+	for rel := range w.prev {
+		if _, ok := w.next[rel]; !ok {
+			stale = append(stale, rel)
+		}
+	}
+	sort.Strings(stale)
+	for _, rel := range stale {
+		w.Stats.Removed++
+		if w.DryRun {
+			continue
+		}
+		err := os.Remove(filepath.Join(w.Dir, filepath.FromSlash(rel)))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if w.DryRun {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(w.next, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(w.Dir, ManifestFile)
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, append(data, '\n')) {
+		return nil
+	}
+	if err := os.MkdirAll(w.Dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Paths returns the files written (or kept) by this export, sorted.
+func (w *Writer) Paths() []string {
+	paths := make([]string, 0, len(w.next))
+	for rel := range w.next {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,230 @@
+// Package feed renders RSS 2.0, JSON Feed 1.1 and sitemap documents for the
+// public site.
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSiteURL  = "https://onnwee.github.io"
+	defaultSiteName = "onnwee"
+)
+
+// SiteURL returns SITE_URL without a trailing slash.
+func SiteURL() string {
+	if v := strings.TrimSpace(os.Getenv("SITE_URL")); v != "" {
+		return strings.TrimRight(v, "/")
+	}
+	return defaultSiteURL
+}
+
+// SiteName returns SITE_NAME, used as the feed title.
+func SiteName() string {
+	if v := strings.TrimSpace(os.Getenv("SITE_NAME")); v != "" {
+		return v
+	}
+	return defaultSiteName
+}
+
+// PostURL and ProjectURL return the client routes for a post and a project.
+func PostURL(site, slug string) string    { return site + "/blog/" + slug }
+func ProjectURL(site, slug string) string { return site + "/projects/" + slug }
+
+// Feed is the format-neutral description of a feed.
+type Feed struct {
+	Title       string
+	Description string
+	SiteURL     string
+	FeedURL     string
+	Items       []Item
+}
+
+// Item is one feed entry.
+type Item struct {
+	ID        string
+	Title     string
+	URL       string
+	Summary   string
+	Tags      []string
+	Published time.Time
+	Updated   time.Time
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// RSS renders f as an RSS 2.0 document.
+func RSS(f Feed) ([]byte, error) {
+	ch := rssChannel{
+		Title:       f.Title,
+		Link:        f.SiteURL,
+		Description: f.Description,
+		AtomLink:    atomLink{Href: f.FeedURL, Rel: "self", Type: "application/rss+xml"},
+	}
+	if latest := latestUpdate(f.Items); !latest.IsZero() {
+		ch.LastBuildDate = latest.UTC().Format(time.RFC1123Z)
+	}
+	for _, it := range f.Items {
+		item := rssItem{
+			Title:       it.Title,
+			Link:        it.URL,
+			GUID:        rssGUID{Value: it.URL, IsPermaLink: true},
+			Description: it.Summary,
+			Categories:  it.Tags,
+		}
+		if !it.Published.IsZero() {
+			item.PubDate = it.Published.UTC().Format(time.RFC1123Z)
+		}
+		ch.Items = append(ch.Items, item)
+	}
+	return marshalXML(rss{Version: "2.0", Atom: "http://www.w3.org/2005/Atom", Channel: ch})
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	Summary       string   `json:"summary,omitempty"`
+	ContentText   string   `json:"content_text"`
+	Tags          []string `json:"tags,omitempty"`
+	DatePublished string   `json:"date_published,omitempty"`
+	DateModified  string   `json:"date_modified,omitempty"`
+}
+
+// JSON renders f as a JSON Feed 1.1 document.
+func JSON(f Feed) ([]byte, error) {
+	out := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.SiteURL,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Items:       make([]jsonFeedItem, 0, len(f.Items)),
+	}
+	for _, it := range f.Items {
+		item := jsonFeedItem{
+			ID:          it.ID,
+			URL:         it.URL,
+			Title:       it.Title,
+			Summary:     it.Summary,
+			ContentText: it.Summary,
+			Tags:        it.Tags,
+		}
+		if !it.Published.IsZero() {
+			item.DatePublished = it.Published.UTC().Format(time.RFC3339)
+		}
+		if !it.Updated.IsZero() {
+			item.DateModified = it.Updated.UTC().Format(time.RFC3339)
+		}
+		out.Items = append(out.Items, item)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// URL is one sitemap entry.
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlset struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Sitemap renders urls as a sitemaps.org urlset.
+func Sitemap(urls []URL) ([]byte, error) {
+	set := urlset{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, u := range urls {
+		su := sitemapURL{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			su.LastMod = u.LastMod.UTC().Format("2006-01-02")
+		}
+		set.URLs = append(set.URLs, su)
+	}
+	return marshalXML(set)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	enc := xml.NewEncoder(&b)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+func latestUpdate(items []Item) time.Time {
+	var latest time.Time
+	for _, it := range items {
+		t := it.Updated
+		if t.IsZero() {
+			t = it.Published
+		}
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
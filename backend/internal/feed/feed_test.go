@@ -0,0 +1,90 @@
+package feed
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleFeed() Feed {
+	published := time.Date(2025, 5, 27, 0, 0, 0, 0, time.UTC)
+	return Feed{
+		Title:   "onnwee",
+		SiteURL: "https://example.com",
+		FeedURL: "https://example.com/api/feed.xml",
+		Items: []Item{{
+			ID:        "https://example.com/blog/hello",
+			Title:     "Hello <World> & friends",
+			URL:       "https://example.com/blog/hello",
+			Summary:   "First post",
+			Tags:      []string{"go", "mdx"},
+			Published: published,
+			Updated:   published.Add(time.Hour),
+		}},
+	}
+}
+
+func TestRSS(t *testing.T) {
+	out, err := RSS(sampleFeed())
+	if err != nil {
+		t.Fatalf("RSS returned error: %v", err)
+	}
+	s := string(out)
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">`,
+		`<title>Hello &lt;World&gt; &amp; friends</title>`,
+		`<guid isPermaLink="true">https://example.com/blog/hello</guid>`,
+		`<category>mdx</category>`,
+		`<pubDate>Tue, 27 May 2025 00:00:00 +0000</pubDate>`,
+		`<lastBuildDate>Tue, 27 May 2025 01:00:00 +0000</lastBuildDate>`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("RSS output missing %q\n%s", want, s)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	out, err := JSON(sampleFeed())
+	if err != nil {
+		t.Fatalf("JSON returned error: %v", err)
+	}
+	var doc struct {
+		Version string `json:"version"`
+		Items   []struct {
+			ID            string `json:"id"`
+			DatePublished string `json:"date_published"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if doc.Version != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("version = %q", doc.Version)
+	}
+	if len(doc.Items) != 1 || doc.Items[0].DatePublished != "2025-05-27T00:00:00Z" {
+		t.Errorf("items = %+v", doc.Items)
+	}
+}
+
+func TestSitemap(t *testing.T) {
+	out, err := Sitemap([]URL{
+		{Loc: "https://example.com/"},
+		{Loc: "https://example.com/projects/a", LastMod: time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Sitemap returned error: %v", err)
+	}
+	s := string(out)
+	if !strings.Contains(s, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`) {
+		t.Errorf("missing urlset namespace:\n%s", s)
+	}
+	if !strings.Contains(s, "<lastmod>2025-01-02</lastmod>") {
+		t.Errorf("missing lastmod:\n%s", s)
+	}
+	if strings.Count(s, "<url>") != 2 {
+		t.Errorf("want 2 urls:\n%s", s)
+	}
+}
@@ -0,0 +1,41 @@
+package httpcache
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MatchWeak reports whether an If-None-Match header value lists etag, using
+// the weak comparison of RFC 9110: W/ prefixes are ignored on both sides.
+func MatchWeak(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range splitTags(header) {
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// NotModified reports whether a GET can be answered with 304 Not Modified.
+// If-None-Match is checked against etag and, when present, decides alone;
+// otherwise If-Modified-Since is compared with modified at the one-second
+// resolution of HTTP dates. A zero modified disables the date check.
+func NotModified(r *http.Request, etag string, modified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etag != "" && MatchWeak(inm, etag)
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || modified.IsZero() {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(t)
+}
@@ -0,0 +1,58 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMatchWeak(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{``, false},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", W/"abc"`, true},
+		{`*`, true},
+		{`"xyz"`, false},
+	}
+	for _, tt := range tests {
+		if got := MatchWeak(tt.header, `"abc"`); got != tt.want {
+			t.Errorf("MatchWeak(%q) = %v; want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2025, 3, 1, 12, 0, 0, 500, time.UTC)
+	tests := []struct {
+		name     string
+		inm, ims string
+		modified time.Time
+		want     bool
+	}{
+		{"no validators", "", "", modified, false},
+		{"etag match", `"abc"`, "", modified, true},
+		{"etag mismatch", `"xyz"`, "", modified, false},
+		{"etag wins over date", `"xyz"`, "Sat, 01 Mar 2025 12:00:00 GMT", modified, false},
+		{"same second", "", "Sat, 01 Mar 2025 12:00:00 GMT", modified, true},
+		{"modified later", "", "Sat, 01 Mar 2025 11:59:59 GMT", modified, false},
+		{"bad date", "", "yesterday", modified, false},
+		{"no last-modified", "", "Sat, 01 Mar 2025 12:00:00 GMT", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.inm != "" {
+			r.Header.Set("If-None-Match", tt.inm)
+		}
+		if tt.ims != "" {
+			r.Header.Set("If-Modified-Since", tt.ims)
+		}
+		if got := NotModified(r, `"abc"`, tt.modified); got != tt.want {
+			t.Errorf("%s: NotModified = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
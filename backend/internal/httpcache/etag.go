@@ -0,0 +1,60 @@
+// Package httpcache implements the entity tags, precondition headers and
+// Cache-Control policies used for optimistic concurrency and conditional
+// requests.
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ETag returns a strong entity tag derived from parts, e.g. a resource kind,
+// its ID and its updated_at.
+func ETag(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// MatchStrong reports whether an If-Match header value lists etag, using the
+// strong comparison of RFC 9110: weak tags never match. "*" matches any
+// current representation.
+func MatchStrong(header, etag string) bool {
+	for _, tag := range splitTags(header) {
+		if tag == "*" || (!strings.HasPrefix(tag, "W/") && tag == etag) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTags splits a comma-separated list of entity tags. Commas inside the
+// quoted part of a tag are kept.
+func splitTags(header string) []string {
+	var tags []string
+	for header != "" {
+		header = strings.TrimLeft(header, " \t,")
+		if header == "" {
+			break
+		}
+		end := len(header)
+		start := 0
+		if strings.HasPrefix(header, "W/") {
+			start = 2
+		}
+		if start < len(header) && header[start] == '"' {
+			if i := strings.IndexByte(header[start+1:], '"'); i >= 0 {
+				end = start + i + 2
+			}
+		} else if i := strings.IndexByte(header, ','); i >= 0 {
+			end = i
+		}
+		tags = append(tags, strings.TrimSpace(header[:end]))
+		header = header[end:]
+	}
+	return tags
+}
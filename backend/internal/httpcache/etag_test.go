@@ -0,0 +1,39 @@
+package httpcache
+
+import "testing"
+
+func TestETag(t *testing.T) {
+	a := ETag("project", "1", "2025-01-01T00:00:00Z")
+	if a != ETag("project", "1", "2025-01-01T00:00:00Z") {
+		t.Error("ETag is not deterministic")
+	}
+	if a == ETag("project", "12025-01-01T00:00:00Z") {
+		t.Error("ETag parts are not separated")
+	}
+	if a[0] != '"' || a[len(a)-1] != '"' {
+		t.Errorf("ETag %s is not a quoted strong tag", a)
+	}
+}
+
+func TestMatchStrong(t *testing.T) {
+	const tag = `"abc"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{``, false},
+		{`"abc"`, true},
+		{`W/"abc"`, false},
+		{`"xyz", "abc"`, true},
+		{`"xyz",W/"abc"`, false},
+		{`*`, true},
+		{`"xyz"`, false},
+		{`"a,b", "abc"`, true},
+		{`abc`, false},
+	}
+	for _, tt := range tests {
+		if got := MatchStrong(tt.header, tag); got != tt.want {
+			t.Errorf("MatchStrong(%q) = %v; want %v", tt.header, got, tt.want)
+		}
+	}
+}
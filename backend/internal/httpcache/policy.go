@@ -0,0 +1,106 @@
+package httpcache
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy is a Cache-Control policy for a route. The zero value renders as
+// "max-age=0": storable, but revalidated on every use.
+type Policy struct {
+	Public               bool
+	MaxAge               time.Duration
+	SMaxAge              time.Duration
+	StaleWhileRevalidate time.Duration
+	StaleIfError         time.Duration
+	NoCache              bool
+	NoStore              bool
+}
+
+// ParsePolicy parses a Cache-Control value such as
+// "public, max-age=60, stale-while-revalidate=300". Only the response
+// directives a shared cache in front of the API needs are accepted.
+func ParsePolicy(s string) (Policy, error) {
+	var p Policy
+	for _, d := range strings.Split(s, ",") {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" {
+			continue
+		}
+		name, value, hasValue := strings.Cut(d, "=")
+		var dst *time.Duration
+		switch name {
+		case "public":
+			p.Public = true
+		case "no-cache":
+			p.NoCache = true
+		case "no-store":
+			p.NoStore = true
+		case "max-age":
+			dst = &p.MaxAge
+		case "s-maxage":
+			dst = &p.SMaxAge
+		case "stale-while-revalidate":
+			dst = &p.StaleWhileRevalidate
+		case "stale-if-error":
+			dst = &p.StaleIfError
+		default:
+			return Policy{}, fmt.Errorf("unsupported directive %q", name)
+		}
+		if dst == nil {
+			if hasValue {
+				return Policy{}, fmt.Errorf("directive %q takes no value", name)
+			}
+			continue
+		}
+		secs, err := strconv.Atoi(value)
+		if err != nil || secs < 0 {
+			return Policy{}, fmt.Errorf("directive %q needs a number of seconds", name)
+		}
+		*dst = time.Duration(secs) * time.Second
+	}
+	return p, nil
+}
+
+// String renders the policy as a Cache-Control value.
+func (p Policy) String() string {
+	if p.NoStore {
+		return "no-store"
+	}
+	var parts []string
+	if p.Public {
+		parts = append(parts, "public")
+	}
+	if p.NoCache {
+		parts = append(parts, "no-cache")
+	}
+	parts = append(parts, "max-age="+strconv.Itoa(int(p.MaxAge/time.Second)))
+	add := func(name string, d time.Duration) {
+		if d > 0 {
+			parts = append(parts, name+"="+strconv.Itoa(int(d/time.Second)))
+		}
+	}
+	add("s-maxage", p.SMaxAge)
+	add("stale-while-revalidate", p.StaleWhileRevalidate)
+	add("stale-if-error", p.StaleIfError)
+	return strings.Join(parts, ", ")
+}
+
+// PolicyFromEnv reads a policy from the environment variable key, falling
+// back to def when it is unset or invalid.
+func PolicyFromEnv(key string, def Policy) Policy {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	p, err := ParsePolicy(v)
+	if err != nil {
+		log.Printf("httpcache: ignoring %s: %v", key, err)
+		return def
+	}
+	return p
+}
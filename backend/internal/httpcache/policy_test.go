@@ -0,0 +1,46 @@
+package httpcache
+
+import "testing"
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"public, max-age=60, stale-while-revalidate=300", "public, max-age=60, stale-while-revalidate=300"},
+		{"Public,S-MaxAge=600,max-age=30", "public, max-age=30, s-maxage=600"},
+		{"max-age=0, stale-if-error=86400", "max-age=0, stale-if-error=86400"},
+		{"no-cache", "no-cache, max-age=0"},
+		{"public, no-store, max-age=60", "no-store"},
+		{"", "max-age=0"},
+	}
+	for _, tt := range tests {
+		p, err := ParsePolicy(tt.in)
+		if err != nil {
+			t.Errorf("ParsePolicy(%q): %v", tt.in, err)
+			continue
+		}
+		if got := p.String(); got != tt.want {
+			t.Errorf("ParsePolicy(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParsePolicyInvalid(t *testing.T) {
+	for _, in := range []string{"private", "max-age", "max-age=-1", "max-age=1m", "public=1"} {
+		if _, err := ParsePolicy(in); err == nil {
+			t.Errorf("ParsePolicy(%q) accepted an invalid policy", in)
+		}
+	}
+}
+
+func TestPolicyFromEnv(t *testing.T) {
+	def := Policy{Public: true}
+	t.Setenv("CACHE_TEST", "max-age=5")
+	if got := PolicyFromEnv("CACHE_TEST", def).String(); got != "max-age=5" {
+		t.Errorf("PolicyFromEnv = %q; want max-age=5", got)
+	}
+	t.Setenv("CACHE_TEST", "bogus")
+	if got := PolicyFromEnv("CACHE_TEST", def); got != def {
+		t.Errorf("PolicyFromEnv with an invalid value = %v; want the default", got)
+	}
+}
@@ -0,0 +1,216 @@
+// Package imagemeta computes what the client needs to lay out and blur-up an
+// image before it loads: intrinsic size, a BlurHash, a tiny base64 preview
+// and the dominant color.
+package imagemeta
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// Meta is stored as JSON next to the image URL it was computed for.
+type Meta struct {
+	// Src is the image URL the metadata belongs to.
+	Src           string `json:"src"`
+	Width         int    `json:"width"`
+	Height        int    `json:"height"`
+	BlurHash      string `json:"blurhash"`
+	Placeholder   string `json:"placeholder"`
+	DominantColor string `json:"dominant_color"`
+}
+
+const (
+	// placeholderSize bounds the longer side of the base64 preview.
+	placeholderSize = 16
+	// sampleSize bounds the longer side of the copy the BlurHash and the
+	// dominant color are computed from.
+	sampleSize = 64
+)
+
+// Compute derives the metadata of a decoded image. Src is left empty.
+func Compute(img image.Image) Meta {
+	b := img.Bounds()
+	sample := imaging.Fit(img, sampleSize, sampleSize, imaging.Box)
+	xComp, yComp := 4, 3
+	if b.Dy() > b.Dx() {
+		xComp, yComp = 3, 4
+	}
+	return Meta{
+		Width:         b.Dx(),
+		Height:        b.Dy(),
+		BlurHash:      BlurHash(sample, xComp, yComp),
+		Placeholder:   Placeholder(img),
+		DominantColor: DominantColor(sample),
+	}
+}
+
+// Stale reports whether stored metadata does not describe src: it is missing
+// for a set image, present for no image, or computed for another URL.
+func Stale(stored json.RawMessage, src string) bool {
+	if len(stored) == 0 || string(stored) == "null" {
+		return src != ""
+	}
+	var m Meta
+	if err := json.Unmarshal(stored, &m); err != nil {
+		return true
+	}
+	return m.Src != src
+}
+
+// Placeholder returns the image scaled down to at most 16 pixels a side, as
+// a PNG data URI.
+func Placeholder(img image.Image) string {
+	small := imaging.Fit(img, placeholderSize, placeholderSize, imaging.Lanczos)
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := enc.Encode(&buf, small); err != nil {
+		return ""
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// DominantColor returns the most common color of the opaque pixels as
+// #rrggbb, or "" for a fully transparent image. Colors are grouped into
+// buckets of 16 levels per channel and the winning bucket is averaged.
+func DominantColor(img image.Image) string {
+	type bucket struct {
+		n       int
+		r, g, b int
+	}
+	buckets := map[int]*bucket{}
+	best := -1
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// Undo the alpha premultiplication
+			r, g, bl = r*0xffff/a>>8, g*0xffff/a>>8, bl*0xffff/a>>8
+			key := int(r>>4)<<8 | int(g>>4)<<4 | int(bl>>4)
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.n++
+			bk.r += int(r)
+			bk.g += int(g)
+			bk.b += int(bl)
+			if best < 0 || bk.n > buckets[best].n {
+				best = key
+			}
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	bk := buckets[best]
+	return fmt.Sprintf("#%02x%02x%02x", bk.r/bk.n, bk.g/bk.n, bk.b/bk.n)
+}
+
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// BlurHash encodes img with xComp×yComp components (1–9 each), following
+// the reference algorithm at https://blurha.sh.
+func BlurHash(img image.Image, xComp, yComp int) string {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return ""
+	}
+
+	// Linear RGB of every pixel, composited onto black
+	lin := make([][3]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			lin[y*w+x] = [3]float64{srgbToLinear(r >> 8), srgbToLinear(g >> 8), srgbToLinear(bl >> 8)}
+		}
+	}
+
+	factors := make([][3]float64, 0, xComp*yComp)
+	for j := 0; j < yComp; j++ {
+		for i := 0; i < xComp; i++ {
+			norm := 2.0
+			if i == 0 && j == 0 {
+				norm = 1
+			}
+			var f [3]float64
+			for y := 0; y < h; y++ {
+				cy := math.Cos(math.Pi * float64(j) * float64(y) / float64(h))
+				for x := 0; x < w; x++ {
+					basis := math.Cos(math.Pi*float64(i)*float64(x)/float64(w)) * cy
+					p := lin[y*w+x]
+					f[0] += basis * p[0]
+					f[1] += basis * p[1]
+					f[2] += basis * p[2]
+				}
+			}
+			scale := norm / float64(w*h)
+			factors = append(factors, [3]float64{f[0] * scale, f[1] * scale, f[2] * scale})
+		}
+	}
+
+	var out []byte
+	out = appendBase83(out, (xComp-1)+(yComp-1)*9, 1)
+
+	dc, ac := factors[0], factors[1:]
+	maxValue := 1.0
+	if len(ac) > 0 {
+		actualMax := 0.0
+		for _, f := range ac {
+			actualMax = math.Max(actualMax, math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2]))))
+		}
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maxValue = float64(quantisedMax+1) / 166
+		out = appendBase83(out, quantisedMax, 1)
+	} else {
+		out = appendBase83(out, 0, 1)
+	}
+
+	out = appendBase83(out, linearToSRGB(dc[0])<<16|linearToSRGB(dc[1])<<8|linearToSRGB(dc[2]), 4)
+	q := func(v float64) int {
+		return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maxValue, 0.5)*9+9.5))))
+	}
+	for _, f := range ac {
+		out = appendBase83(out, q(f[0])*19*19+q(f[1])*19+q(f[2]), 2)
+	}
+	return string(out)
+}
+
+func appendBase83(out []byte, value, length int) []byte {
+	for i := 1; i <= length; i++ {
+		digit := value / int(math.Pow(83, float64(length-i))) % 83
+		out = append(out, base83Chars[digit])
+	}
+	return out
+}
+
+func srgbToLinear(v uint32) float64 {
+	f := float64(v) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) int {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
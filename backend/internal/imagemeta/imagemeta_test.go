@@ -0,0 +1,169 @@
+package imagemeta
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/media"
+)
+
+func solid(w, h int, c color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestBlurHash(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+		x, y int
+		want string
+	}{
+		{"black", solid(4, 3, color.Black), 4, 3, "L00000fQfQfQfQfQfQfQfQfQfQfQ"},
+		{"white dc only", solid(8, 8, color.White), 1, 1, "00TSUA"},
+	}
+	for _, tt := range tests {
+		if got := BlurHash(tt.img, tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: BlurHash = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := BlurHash(image.NewNRGBA(image.Rect(0, 0, 0, 0)), 4, 3); got != "" {
+		t.Errorf("empty image: BlurHash = %q", got)
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			c := color.NRGBA{R: 0x20, G: 0x80, B: 0xc0, A: 0xff}
+			if x < 3 {
+				c = color.NRGBA{R: 0xff, A: 0xff}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	if got := DominantColor(img); got != "#2080c0" {
+		t.Errorf("DominantColor = %q, want #2080c0", got)
+	}
+	if got := DominantColor(solid(4, 4, color.Transparent)); got != "" {
+		t.Errorf("transparent: DominantColor = %q, want empty", got)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	m := Compute(solid(320, 160, color.NRGBA{R: 10, G: 20, B: 30, A: 255}))
+	if m.Width != 320 || m.Height != 160 {
+		t.Errorf("size = %dx%d", m.Width, m.Height)
+	}
+	if m.DominantColor != "#0a141e" {
+		t.Errorf("dominant color = %q", m.DominantColor)
+	}
+	if !strings.HasPrefix(m.BlurHash, "L") {
+		t.Errorf("blurhash %q should use 4x3 components", m.BlurHash)
+	}
+
+	const prefix = "data:image/png;base64,"
+	if !strings.HasPrefix(m.Placeholder, prefix) {
+		t.Fatalf("placeholder = %q", m.Placeholder)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(m.Placeholder, prefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 16 || cfg.Height != 8 {
+		t.Errorf("placeholder is %dx%d, want 16x8", cfg.Width, cfg.Height)
+	}
+}
+
+func TestStale(t *testing.T) {
+	stored := json.RawMessage(`{"src":"/a.png","width":1}`)
+	tests := []struct {
+		stored json.RawMessage
+		src    string
+		want   bool
+	}{
+		{nil, "", false},
+		{json.RawMessage("null"), "", false},
+		{nil, "/a.png", true},
+		{stored, "/a.png", false},
+		{stored, "/b.png", true},
+		{stored, "", true},
+		{json.RawMessage("{"), "/a.png", true},
+	}
+	for _, tt := range tests {
+		if got := Stale(tt.stored, tt.src); got != tt.want {
+			t.Errorf("Stale(%s, %q) = %v, want %v", tt.stored, tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+	data := encodePNG(t, solid(40, 20, color.White))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/img.png":
+			_, _ = w.Write(data)
+		case "/logo.svg":
+			_, _ = w.Write([]byte("<svg/>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	store := media.NewLocal(t.TempDir())
+	if err := store.Put(ctx, "abc/original.png", bytes.NewReader(data), media.TypePNG); err != nil {
+		t.Fatal(err)
+	}
+	l := NewLoader(&media.Library{Storage: store, BaseURL: "/api/media"}, srv.URL)
+
+	for _, src := range []string{srv.URL + "/img.png", "/img.png", "/api/media/abc/original.png"} {
+		m, err := l.Load(ctx, src)
+		if err != nil {
+			t.Errorf("Load(%q): %v", src, err)
+			continue
+		}
+		if m.Src != src || m.Width != 40 || m.Height != 20 {
+			t.Errorf("Load(%q) = %+v", src, m)
+		}
+	}
+	for _, src := range []string{"/logo.svg", "/missing.png", "ftp://example.com/a.png", "data:image/png;base64,AAAA"} {
+		if _, err := l.Load(ctx, src); err == nil {
+			t.Errorf("Load(%q) succeeded", src)
+		}
+	}
+
+	if meta, err := l.LoadJSON(ctx, ""); meta != nil || err != nil {
+		t.Errorf("LoadJSON of no image = %s, %v", meta, err)
+	}
+}
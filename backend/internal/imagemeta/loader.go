@@ -0,0 +1,135 @@
+package imagemeta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the GIF decoder
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/media"
+)
+
+const (
+	// maxImageBytes bounds the download of a single image.
+	maxImageBytes = 20 << 20
+	// maxPixels bounds the decoded size of a single image.
+	maxPixels = 40_000_000
+	// fetchTimeout bounds fetching a remote image.
+	fetchTimeout = 10 * time.Second
+)
+
+// ErrUnsupported is returned for URLs that cannot be fetched or images that
+// cannot be decoded (e.g. SVG).
+var ErrUnsupported = errors.New("imagemeta: unsupported image")
+
+// Loader fetches images by the URL stored in a project or post and computes
+// their metadata. Media library URLs are read from storage, absolute
+// http(s) URLs are downloaded and site-relative paths are resolved against
+// SiteURL.
+type Loader struct {
+	Media   *media.Library
+	SiteURL string
+	Client  *http.Client
+}
+
+// NewLoader returns a Loader with a client that gives up after fetchTimeout.
+func NewLoader(lib *media.Library, siteURL string) *Loader {
+	return &Loader{Media: lib, SiteURL: siteURL, Client: &http.Client{Timeout: fetchTimeout}}
+}
+
+// Load fetches src and returns its metadata.
+func (l *Loader) Load(ctx context.Context, src string) (Meta, error) {
+	rc, err := l.open(ctx, src)
+	if err != nil {
+		return Meta{}, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxImageBytes+1))
+	if err != nil {
+		return Meta{}, err
+	}
+	if len(data) > maxImageBytes {
+		return Meta{}, fmt.Errorf("imagemeta: %s is larger than %d bytes", src, maxImageBytes)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Meta{}, ErrUnsupported
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return Meta{}, fmt.Errorf("imagemeta: %s is %dx%d, too large", src, cfg.Width, cfg.Height)
+	}
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return Meta{}, ErrUnsupported
+	}
+
+	m := Compute(img)
+	m.Src = src
+	return m, nil
+}
+
+// LoadJSON returns the stored form of src's metadata: nil for no image,
+// otherwise the encoded Meta.
+func (l *Loader) LoadJSON(ctx context.Context, src string) (json.RawMessage, error) {
+	if src == "" {
+		return nil, nil
+	}
+	m, err := l.Load(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+func (l *Loader) open(ctx context.Context, src string) (io.ReadCloser, error) {
+	if l.Media != nil {
+		prefix := strings.TrimSuffix(l.Media.BaseURL, "/") + "/"
+		if strings.HasPrefix(src, prefix) {
+			return l.Media.Storage.Open(ctx, strings.TrimPrefix(src, prefix))
+		}
+	}
+
+	u, err := url.Parse(src)
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if u.Scheme == "" && strings.HasPrefix(src, "/") && !strings.HasPrefix(src, "//") {
+		if u, err = url.Parse(strings.TrimSuffix(l.SiteURL, "/") + src); err != nil {
+			return nil, ErrUnsupported
+		}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, ErrUnsupported
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/webp,image/png,image/jpeg,image/gif;q=0.8")
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("imagemeta: GET %s: %s", u, resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,145 @@
+package listquery
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// JSONFields returns the JSON keys of a struct value, following embedded
+// structs. It is used to derive the allowlist for sparse fieldsets.
+func JSONFields(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var out []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			out = append(out, JSONFields(reflect.Zero(f.Type).Interface())...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+// ParseFields reads ?fields=a,b,c and validates each name against allowed.
+// A nil result means the full representation was requested.
+func ParseFields(values url.Values, allowed []string) ([]string, error) {
+	return parseNameList(values, "fields", allowed)
+}
+
+// ParseInclude reads ?include=a,b and validates each name against allowed.
+func ParseInclude(values url.Values, allowed []string) ([]string, error) {
+	return parseNameList(values, "include", allowed)
+}
+
+func parseNameList(values url.Values, param string, allowed []string) ([]string, error) {
+	raw := values.Get(param)
+	if raw == "" {
+		return nil, nil
+	}
+	ok := make(map[string]bool, len(allowed))
+	for _, a := range allowed {
+		ok[a] = true
+	}
+	var out []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !ok[name] {
+			return nil, errorf(param, "unknown field %q", name)
+		}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+// Includes reports whether name was requested via ParseInclude.
+func Includes(include []string, name string) bool {
+	for _, n := range include {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Select trims the JSON representation of v (an object or a slice of objects)
+// down to the given keys. With no fields, v is returned unchanged.
+func Select(v interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		keep[f] = true
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	trim := func(obj map[string]json.RawMessage) map[string]json.RawMessage {
+		for k := range obj {
+			if !keep[k] {
+				delete(obj, k)
+			}
+		}
+		return obj
+	}
+
+	if len(raw) > 0 && raw[0] == '[' {
+		var list []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		for i := range list {
+			list[i] = trim(list[i])
+		}
+		return list, nil
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	return trim(obj), nil
+}
+
+// Columns narrows a comma-separated select list to a fieldset. Each column in
+// lazy that fields does not name is replaced by its placeholder, e.g. NULL, so
+// the row still scans but the value is never read. With no fields, columns is
+// returned unchanged.
+func Columns(columns string, fields []string, lazy map[string]string) string {
+	if len(fields) == 0 {
+		return columns
+	}
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		keep[f] = true
+	}
+	cols := strings.Split(columns, ", ")
+	for i, c := range cols {
+		if placeholder, ok := lazy[c]; ok && !keep[c] {
+			cols[i] = placeholder + " AS " + c
+		}
+	}
+	return strings.Join(cols, ", ")
+}
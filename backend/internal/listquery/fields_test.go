@@ -0,0 +1,94 @@
+package listquery
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+type fieldsBase struct {
+	ID    int32  `json:"id"`
+	Title string `json:"title"`
+}
+
+type fieldsResponse struct {
+	fieldsBase
+	Content string  `json:"content"`
+	Author  *string `json:"author,omitempty"`
+	hidden  string
+}
+
+func TestJSONFields(t *testing.T) {
+	got := JSONFields(fieldsResponse{})
+	want := []string{"id", "title", "content", "author"}
+	if len(got) != len(want) {
+		t.Fatalf("JSONFields = %v; want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("JSONFields[%d] = %s; want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	items := []fieldsResponse{
+		{fieldsBase: fieldsBase{ID: 1, Title: "one"}, Content: "long"},
+		{fieldsBase: fieldsBase{ID: 2, Title: "two"}, Content: "longer"},
+	}
+
+	out, err := Select(items, []string{"id", "title"})
+	if err != nil {
+		t.Fatalf("Select returned error: %v", err)
+	}
+	raw, _ := json.Marshal(out)
+	want := `[{"id":1,"title":"one"},{"id":2,"title":"two"}]`
+	if string(raw) != want {
+		t.Errorf("Select = %s; want %s", raw, want)
+	}
+
+	out, err = Select(items[0], nil)
+	if err != nil {
+		t.Fatalf("Select returned error: %v", err)
+	}
+	if _, ok := out.(fieldsResponse); !ok {
+		t.Errorf("Select without fields should return the value unchanged, got %T", out)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	allowed := JSONFields(fieldsResponse{})
+
+	fields, err := ParseFields(url.Values{"fields": {"id, title"}}, allowed)
+	if err != nil {
+		t.Fatalf("ParseFields returned error: %v", err)
+	}
+	if len(fields) != 2 || fields[0] != "id" || fields[1] != "title" {
+		t.Errorf("ParseFields = %v; want [id title]", fields)
+	}
+
+	if _, err := ParseFields(url.Values{"fields": {"id,password_hash"}}, allowed); err == nil {
+		t.Error("ParseFields accepted a field outside the allowlist")
+	}
+
+	if _, err := ParseInclude(url.Values{"include": {"comments"}}, []string{"author"}); err == nil {
+		t.Error("ParseInclude accepted an unknown relation")
+	}
+}
+
+func TestColumns(t *testing.T) {
+	lazy := map[string]string{"content": "''", "embed": "NULL"}
+	tests := []struct {
+		fields []string
+		want   string
+	}{
+		{nil, "id, title, content, embed"},
+		{[]string{"id", "title"}, "id, title, '' AS content, NULL AS embed"},
+		{[]string{"title", "content"}, "id, title, content, NULL AS embed"},
+	}
+	for _, tt := range tests {
+		if got := Columns("id, title, content, embed", tt.fields, lazy); got != tt.want {
+			t.Errorf("Columns(%v) = %q; want %q", tt.fields, got, tt.want)
+		}
+	}
+}
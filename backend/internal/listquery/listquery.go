@@ -0,0 +1,498 @@
+// Package listquery parses the sort, filter and pagination parameters shared by
+// list endpoints and compiles them into parameterized SQL.
+//
+// Supported query parameters:
+//
+//	sort=-created_at,title            comma-separated fields, "-" for DESC
+//	filter[field]=value               shorthand for filter[field][eq]=value
+//	filter[field][op]=value           op is one of the operators below
+//	limit=N&offset=M                  pagination
+//
+// A plain date as an upper bound (lte, between) covers that whole day, and as
+// a lower bound of gt it starts after it. Results are always ordered by a
+// unique column last, so pages neither repeat nor skip tied rows.
+//
+// Every field must be allowlisted in the resource's Spec. Values are always
+// passed as bind parameters; only allowlisted column names reach the SQL text.
+package listquery
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// FieldType controls how filter values are parsed and which operators apply.
+type FieldType int
+
+const (
+	Text FieldType = iota
+	Int
+	Bool
+	Time
+	TextArray
+)
+
+// Operators understood by the filter syntax.
+const (
+	OpEq       = "eq"
+	OpNe       = "ne"
+	OpGt       = "gt"
+	OpGte      = "gte"
+	OpLt       = "lt"
+	OpLte      = "lte"
+	OpIn       = "in"
+	OpContains = "contains"
+	OpOverlaps = "overlaps"
+	OpBetween  = "between"
+)
+
+var allowedOps = map[FieldType][]string{
+	Text:      {OpEq, OpNe, OpIn, OpContains},
+	Int:       {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn},
+	Bool:      {OpEq, OpNe},
+	Time:      {OpEq, OpGt, OpGte, OpLt, OpLte, OpBetween},
+	TextArray: {OpContains, OpOverlaps},
+}
+
+var sqlOps = map[string]string{
+	OpEq:  "=",
+	OpNe:  "<>",
+	OpGt:  ">",
+	OpGte: ">=",
+	OpLt:  "<",
+	OpLte: "<=",
+}
+
+// Field describes a query-visible attribute of a resource.
+type Field struct {
+	Column     string
+	Type       FieldType
+	Sortable   bool
+	Filterable bool
+}
+
+// Spec is the per-resource allowlist of fields and pagination defaults.
+type Spec struct {
+	Fields       map[string]Field
+	DefaultSort  string
+	DefaultLimit int32
+	MaxLimit     int32
+	// TieBreaker is a unique column ordered by last; defaults to the column
+	// of the "id" field.
+	TieBreaker string
+}
+
+// Range is the value of a between filter. To is exclusive when the bound
+// was a plain date, so the whole day is included.
+type Range struct {
+	From, To    time.Time
+	ToExclusive bool
+}
+
+// SortTerm is a single ORDER BY entry.
+type SortTerm struct {
+	Field string
+	Desc  bool
+}
+
+// Filter is a single parsed filter predicate.
+type Filter struct {
+	Field string
+	Op    string
+	Value interface{}
+}
+
+// Query is the parsed and validated form of a list request.
+type Query struct {
+	Sort    []SortTerm
+	Filters []Filter
+	Limit   int32
+	Offset  int32
+
+	spec Spec
+}
+
+// Error is returned for invalid list parameters and is safe to show to clients.
+type Error struct {
+	Param   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Param + ": " + e.Message
+}
+
+func errorf(param, format string, args ...interface{}) *Error {
+	return &Error{Param: param, Message: fmt.Sprintf(format, args...)}
+}
+
+// Parse validates values against spec and returns the resulting Query.
+// Parameters other than sort, filter[...], limit and offset are ignored.
+func Parse(values url.Values, spec Spec) (Query, error) {
+	q := Query{Limit: spec.DefaultLimit, spec: spec}
+	if q.Limit <= 0 {
+		q.Limit = 10
+	}
+	maxLimit := spec.MaxLimit
+	if maxLimit <= 0 {
+		maxLimit = 100
+	}
+
+	if v := values.Get("limit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n <= 0 {
+			return Query{}, errorf("limit", "must be a positive integer")
+		}
+		if int32(n) > maxLimit {
+			return Query{}, errorf("limit", "must not exceed %d", maxLimit)
+		}
+		q.Limit = int32(n)
+	}
+
+	if v := values.Get("offset"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 0 {
+			return Query{}, errorf("offset", "must be a non-negative integer")
+		}
+		q.Offset = int32(n)
+	}
+
+	sortParam := values.Get("sort")
+	if sortParam == "" {
+		sortParam = spec.DefaultSort
+	}
+	terms, err := parseSort(sortParam, spec)
+	if err != nil {
+		return Query{}, err
+	}
+	q.Sort = terms
+
+	// Iterate in a stable order so the generated SQL is deterministic.
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if strings.HasPrefix(k, "filter[") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field, op, err := parseFilterKey(key)
+		if err != nil {
+			return Query{}, err
+		}
+		for _, raw := range values[key] {
+			f, err := newFilter(key, field, op, raw, spec)
+			if err != nil {
+				return Query{}, err
+			}
+			q.Filters = append(q.Filters, f)
+		}
+	}
+
+	return q, nil
+}
+
+func parseSort(param string, spec Spec) ([]SortTerm, error) {
+	if param == "" {
+		return nil, nil
+	}
+	var terms []SortTerm
+	for _, part := range strings.Split(param, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		term := SortTerm{Field: part}
+		if strings.HasPrefix(part, "-") {
+			term = SortTerm{Field: part[1:], Desc: true}
+		}
+		f, ok := spec.Fields[term.Field]
+		if !ok {
+			return nil, errorf("sort", "unknown field %q", term.Field)
+		}
+		if !f.Sortable {
+			return nil, errorf("sort", "field %q is not sortable", term.Field)
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// parseFilterKey splits "filter[field]" or "filter[field][op]".
+func parseFilterKey(key string) (string, string, error) {
+	rest := strings.TrimPrefix(key, "filter[")
+	end := strings.Index(rest, "]")
+	if end <= 0 {
+		return "", "", errorf(key, "malformed filter parameter")
+	}
+	field := rest[:end]
+	rest = rest[end+1:]
+	if rest == "" {
+		return field, "", nil
+	}
+	if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") || len(rest) < 3 {
+		return "", "", errorf(key, "malformed filter parameter")
+	}
+	return field, rest[1 : len(rest)-1], nil
+}
+
+func newFilter(param, name, op, raw string, spec Spec) (Filter, error) {
+	field, ok := spec.Fields[name]
+	if !ok {
+		return Filter{}, errorf(param, "unknown field %q", name)
+	}
+	if !field.Filterable {
+		return Filter{}, errorf(param, "field %q is not filterable", name)
+	}
+	if op == "" {
+		op = OpEq
+		if field.Type == TextArray {
+			op = OpContains
+		}
+	}
+	if !opAllowed(field.Type, op) {
+		return Filter{}, errorf(param, "operator %q is not supported for field %q (allowed: %s)",
+			op, name, strings.Join(allowedOps[field.Type], ", "))
+	}
+
+	var value interface{}
+	var err error
+	switch {
+	case op == OpIn || field.Type == TextArray:
+		value, err = parseList(raw, field.Type)
+	case op == OpBetween:
+		value, err = parseRange(raw)
+	default:
+		value, err = parseValue(raw, field.Type)
+	}
+	if err != nil {
+		return Filter{}, errorf(param, "%s", err.Error())
+	}
+	if field.Type == Time && isDate(raw) {
+		// A day as a bound takes in all of it: <= becomes < the next day,
+		// and = the whole day
+		switch op {
+		case OpEq:
+			day := value.(time.Time)
+			op, value = OpBetween, Range{From: day, To: day.AddDate(0, 0, 1), ToExclusive: true}
+		case OpLte:
+			op, value = OpLt, value.(time.Time).AddDate(0, 0, 1)
+		case OpGt:
+			op, value = OpGte, value.(time.Time).AddDate(0, 0, 1)
+		}
+	}
+	return Filter{Field: name, Op: op, Value: value}, nil
+}
+
+func opAllowed(t FieldType, op string) bool {
+	for _, o := range allowedOps[t] {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func parseValue(raw string, t FieldType) (interface{}, error) {
+	switch t {
+	case Int:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", raw)
+		}
+		return n, nil
+	case Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %q", raw)
+		}
+		return b, nil
+	case Time:
+		return parseTime(raw)
+	default:
+		return raw, nil
+	}
+}
+
+func parseList(raw string, t FieldType) (interface{}, error) {
+	parts := strings.Split(raw, ",")
+	if t == Int {
+		out := make([]int64, 0, len(parts))
+		for _, p := range parts {
+			n, err := strconv.ParseInt(strings.TrimSpace(p), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer %q", p)
+			}
+			out = append(out, n)
+		}
+		return out, nil
+	}
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("empty list")
+	}
+	return out, nil
+}
+
+func parseRange(raw string) (interface{}, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("between expects two comma-separated dates")
+	}
+	from, err := parseTime(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, err
+	}
+	upper := strings.TrimSpace(parts[1])
+	to, err := parseTime(upper)
+	if err != nil {
+		return nil, err
+	}
+	if isDate(upper) {
+		return Range{From: from, To: to.AddDate(0, 0, 1), ToExclusive: true}, nil
+	}
+	return Range{From: from, To: to}, nil
+}
+
+const dateLayout = "2006-01-02"
+
+// isDate reports whether raw is a plain YYYY-MM-DD date.
+func isDate(raw string) bool {
+	_, err := time.Parse(dateLayout, raw)
+	return err == nil
+}
+
+// parseTime accepts RFC 3339 timestamps or plain YYYY-MM-DD dates (UTC).
+func parseTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(dateLayout, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD or RFC 3339)", raw)
+}
+
+// Build appends the compiled filters, ordering and pagination to a base
+// SELECT statement. conds are extra predicates that use $1..$len(args);
+// placeholders for filters continue from there.
+func (q Query) Build(sel string, conds []string, args []interface{}) (string, []interface{}) {
+	where := append([]string{}, conds...)
+	out := append([]interface{}{}, args...)
+
+	next := func(v interface{}) string {
+		out = append(out, v)
+		return "$" + strconv.Itoa(len(out))
+	}
+
+	for _, f := range q.Filters {
+		col := q.spec.Fields[f.Field].Column
+		switch f.Op {
+		case OpIn:
+			where = append(where, fmt.Sprintf("%s = ANY(%s)", col, next(arrayArg(f.Value))))
+		case OpContains:
+			if q.spec.Fields[f.Field].Type == TextArray {
+				where = append(where, fmt.Sprintf("%s @> %s", col, next(arrayArg(f.Value))))
+			} else {
+				where = append(where, fmt.Sprintf("%s ILIKE %s", col, next("%"+escapeLike(f.Value.(string))+"%")))
+			}
+		case OpOverlaps:
+			where = append(where, fmt.Sprintf("%s && %s", col, next(arrayArg(f.Value))))
+		case OpBetween:
+			r := f.Value.(Range)
+			if r.ToExclusive {
+				where = append(where, fmt.Sprintf("%s >= %s AND %s < %s", col, next(r.From), col, next(r.To)))
+			} else {
+				where = append(where, fmt.Sprintf("%s BETWEEN %s AND %s", col, next(r.From), next(r.To)))
+			}
+		default:
+			where = append(where, fmt.Sprintf("%s %s %s", col, sqlOps[f.Op], next(f.Value)))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(sel)
+	if len(where) > 0 {
+		b.WriteString(" WHERE ")
+		b.WriteString(strings.Join(where, " AND "))
+	}
+	var order []string
+	tie, tieDesc := q.tieBreaker(), false
+	for i, s := range q.Sort {
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		col := q.spec.Fields[s.Field].Column
+		if col == tie {
+			tie = ""
+		}
+		if i == 0 {
+			tieDesc = s.Desc
+		}
+		order = append(order, col+" "+dir)
+	}
+	if tie != "" {
+		// Follows the first term, so newest-first lists stay newest first
+		if tieDesc {
+			order = append(order, tie+" DESC")
+		} else {
+			order = append(order, tie+" ASC")
+		}
+	}
+	if len(order) > 0 {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(strings.Join(order, ", "))
+	}
+	b.WriteString(" LIMIT " + next(q.Limit))
+	b.WriteString(" OFFSET " + next(q.Offset))
+	return b.String(), out
+}
+
+// NextLink returns a Link header value pointing at the next page of a
+// request with the given query, or "" when returned rows did not fill this
+// one. A full last page still gets a link, to an empty page. The target is
+// a query-only reference, so it resolves behind any path prefix.
+func (q Query) NextLink(query url.Values, returned int) string {
+	if returned < int(q.Limit) {
+		return ""
+	}
+	values := url.Values{}
+	for k, v := range query {
+		values[k] = v
+	}
+	values.Set("limit", strconv.Itoa(int(q.Limit)))
+	values.Set("offset", strconv.Itoa(int(q.Offset+q.Limit)))
+	return "<?" + values.Encode() + `>; rel="next"`
+}
+
+// tieBreaker returns the unique column ordered by last, or "" for none.
+func (q Query) tieBreaker() string {
+	if q.spec.TieBreaker != "" {
+		return q.spec.TieBreaker
+	}
+	if f, ok := q.spec.Fields["id"]; ok {
+		return f.Column
+	}
+	return ""
+}
+
+// arrayArg wraps list values so database/sql can bind them as Postgres arrays.
+func arrayArg(v interface{}) interface{} {
+	return pq.Array(v)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
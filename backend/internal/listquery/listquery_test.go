@@ -0,0 +1,187 @@
+package listquery
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSpec = Spec{
+	Fields: map[string]Field{
+		"id":         {Column: "id", Type: Int, Sortable: true, Filterable: true},
+		"title":      {Column: "title", Type: Text, Sortable: true, Filterable: true},
+		"tags":       {Column: "tags", Type: TextArray, Filterable: true},
+		"created_at": {Column: "created_at", Type: Time, Sortable: true, Filterable: true},
+		"secret":     {Column: "secret", Type: Text},
+	},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 10,
+	MaxLimit:     50,
+}
+
+func TestParseAndBuild(t *testing.T) {
+	values, _ := url.ParseQuery("sort=-created_at,title&filter[tags]=go,sql&filter[created_at][gte]=2025-01-01&filter[title][contains]=50%25_off&limit=5&offset=10")
+
+	q, err := Parse(values, testSpec)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	sql, args := q.Build("SELECT id FROM posts", []string{"is_draft = $1"}, []interface{}{false})
+
+	want := "SELECT id FROM posts WHERE is_draft = $1 AND created_at >= $2 AND tags @> $3 AND title ILIKE $4 ORDER BY created_at DESC, title ASC, id DESC LIMIT $5 OFFSET $6"
+	if sql != want {
+		t.Errorf("Build SQL =\n%s\nwant\n%s", sql, want)
+	}
+	if len(args) != 6 {
+		t.Fatalf("Build returned %d args; want 6", len(args))
+	}
+	if args[3] != `%50\%\_off%` {
+		t.Errorf("contains arg = %v; want escaped LIKE pattern", args[3])
+	}
+	if args[4] != int32(5) || args[5] != int32(10) {
+		t.Errorf("pagination args = %v, %v; want 5, 10", args[4], args[5])
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	q, err := Parse(url.Values{}, testSpec)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	sql, _ := q.Build("SELECT id FROM posts", nil, nil)
+	want := "SELECT id FROM posts ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2"
+	if sql != want {
+		t.Errorf("Build SQL = %s; want %s", sql, want)
+	}
+	if q.Limit != 10 || q.Offset != 0 {
+		t.Errorf("defaults = limit %d offset %d; want 10, 0", q.Limit, q.Offset)
+	}
+}
+
+func TestParseBetween(t *testing.T) {
+	values := url.Values{"filter[created_at][between]": {"2025-01-01,2025-02-01T00:00:00Z"}}
+	q, err := Parse(values, testSpec)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	sql, args := q.Build("SELECT id FROM posts", nil, nil)
+	if !strings.Contains(sql, "created_at BETWEEN $1 AND $2") {
+		t.Errorf("Build SQL = %s; want BETWEEN clause", sql)
+	}
+	if len(args) != 4 {
+		t.Errorf("Build returned %d args; want 4", len(args))
+	}
+
+	// A plain date as the upper bound includes that whole day
+	values = url.Values{"filter[created_at][between]": {"2025-01-01,2025-06-30"}}
+	if q, err = Parse(values, testSpec); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	sql, args = q.Build("SELECT id FROM posts", nil, nil)
+	if !strings.Contains(sql, "created_at >= $1 AND created_at < $2") {
+		t.Errorf("Build SQL = %s; want an exclusive upper bound", sql)
+	}
+	if to := args[1].(time.Time); !to.Equal(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("upper bound = %v; want the start of the next day", to)
+	}
+
+	values = url.Values{"filter[created_at][lte]": {"2025-06-30"}}
+	if q, err = Parse(values, testSpec); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if sql, _ = q.Build("SELECT id FROM posts", nil, nil); !strings.Contains(sql, "created_at < $1") {
+		t.Errorf("Build SQL = %s; want lte on a date to cover the day", sql)
+	}
+
+	values = url.Values{"filter[created_at][eq]": {"2025-06-30"}}
+	if q, err = Parse(values, testSpec); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	sql, args = q.Build("SELECT id FROM posts", nil, nil)
+	if !strings.Contains(sql, "created_at >= $1 AND created_at < $2") {
+		t.Errorf("Build SQL = %s; want eq on a date to cover the day", sql)
+	}
+	from, to := args[0].(time.Time), args[1].(time.Time)
+	if !from.Equal(time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("range = %v, %v; want 2025-06-30 up to 2025-07-01", from, to)
+	}
+}
+
+func TestTieBreaker(t *testing.T) {
+	tests := []struct {
+		sort string
+		want string
+	}{
+		{"title", "ORDER BY title ASC, id ASC"},
+		{"-created_at", "ORDER BY created_at DESC, id DESC"},
+		{"-id,title", "ORDER BY id DESC, title ASC LIMIT"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(url.Values{"sort": {tt.sort}}, testSpec)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tt.sort, err)
+		}
+		if sql, _ := q.Build("SELECT id FROM posts", nil, nil); !strings.Contains(sql, tt.want) {
+			t.Errorf("sort=%s: Build SQL = %s; want %s", tt.sort, sql, tt.want)
+		}
+	}
+
+	spec := testSpec
+	spec.TieBreaker = "slug"
+	q, _ := Parse(url.Values{"sort": {"title"}}, spec)
+	if sql, _ := q.Build("SELECT id FROM posts", nil, nil); !strings.Contains(sql, "ORDER BY title ASC, slug ASC") {
+		t.Errorf("custom tie breaker: Build SQL = %s", sql)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		param string
+	}{
+		{"unknown sort field", "sort=nope", "sort"},
+		{"unsortable field", "sort=tags", "sort"},
+		{"unknown filter field", "filter[nope]=1", "filter[nope]"},
+		{"not filterable", "filter[secret]=x", "filter[secret]"},
+		{"bad operator", "filter[title][gt]=a", "filter[title][gt]"},
+		{"bad integer", "filter[id]=abc", "filter[id]"},
+		{"bad date", "filter[created_at][lt]=yesterday", "filter[created_at][lt]"},
+		{"malformed key", "filter[id=1", "filter[id"},
+		{"limit too large", "limit=51", "limit"},
+		{"negative offset", "offset=-1", "offset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			_, err := Parse(values, testSpec)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded; want error", tt.query)
+			}
+			qe, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("Parse(%q) error type %T; want *Error", tt.query, err)
+			}
+			if qe.Param != tt.param {
+				t.Errorf("Parse(%q) error param = %q; want %q", tt.query, qe.Param, tt.param)
+			}
+		})
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	u, _ := url.Parse("/projects?sort=title&limit=2&offset=4")
+	q, err := Parse(u.Query(), testSpec)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if got := q.NextLink(u.Query(), 1); got != "" {
+		t.Errorf("NextLink of a short page = %q; want none", got)
+	}
+	if got, want := q.NextLink(u.Query(), 2), `<?limit=2&offset=6&sort=title>; rel="next"`; got != want {
+		t.Errorf("NextLink = %q; want %q", got, want)
+	}
+}
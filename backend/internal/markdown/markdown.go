@@ -0,0 +1,51 @@
+// Package markdown renders untrusted Markdown (reader comments) to HTML that is
+// safe to embed in a page.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// md renders CommonMark plus strikethrough and bare-URL autolinks. Raw HTML in
+// the input is dropped by goldmark (no html.WithUnsafe).
+var md = goldmark.New(
+	goldmark.WithExtensions(extension.Strikethrough, extension.Linkify),
+)
+
+// policy is the allowlist applied after rendering: inline formatting, code,
+// quotes and lists, plus http(s)/mailto links that open as untrusted content.
+// Headings and images are not allowed in comments.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("p", "br", "strong", "em", "del", "code", "pre", "blockquote", "ul", "ol", "li", "hr")
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	p.AllowAttrs("start").Matching(regexp.MustCompile(`^[0-9]+$`)).OnElements("ol")
+	return p
+}()
+
+// Render converts Markdown to sanitized HTML.
+func Render(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(policy.Sanitize(buf.String())), nil
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// CountLinks counts URLs in raw Markdown, whether written bare or as link
+// targets. It is used as a spam signal.
+func CountLinks(src string) int {
+	return len(linkPattern.FindAllStringIndex(src, -1))
+}
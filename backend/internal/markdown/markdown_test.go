@@ -0,0 +1,80 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:  "inline formatting",
+			input: "Hello **bold** and *em* and `code`",
+			want:  []string{"<strong>bold</strong>", "<em>em</em>", "<code>code</code>"},
+		},
+		{
+			name:    "raw html is dropped",
+			input:   "hi <script>alert(1)</script> <b onclick=\"x\">there</b>",
+			notWant: []string{"<script", "onclick", "alert(1)</script>"},
+		},
+		{
+			name:    "javascript links are removed",
+			input:   "[click](javascript:alert(1))",
+			notWant: []string{"javascript:"},
+		},
+		{
+			name:  "links are nofollow",
+			input: "[site](https://example.com)",
+			want:  []string{`href="https://example.com"`, `rel="nofollow noopener"`, `target="_blank"`},
+		},
+		{
+			name:  "bare urls are linked",
+			input: "see https://example.com/a",
+			want:  []string{`<a href="https://example.com/a"`},
+		},
+		{
+			name:    "images and headings are not allowed",
+			input:   "# Title\n\n![x](https://example.com/x.png)",
+			notWant: []string{"<h1", "<img"},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := Render(tt.input)
+		if err != nil {
+			t.Errorf("%s: Render returned error: %v", tt.name, err)
+			continue
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(got, w) {
+				t.Errorf("%s: output %q missing %q", tt.name, got, w)
+			}
+		}
+		for _, nw := range tt.notWant {
+			if strings.Contains(got, nw) {
+				t.Errorf("%s: output %q should not contain %q", tt.name, got, nw)
+			}
+		}
+	}
+}
+
+func TestCountLinks(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"no links here", 0},
+		{"one https://a.example", 1},
+		{"[a](http://a.example) and www.b.example and HTTPS://C.EXAMPLE", 3},
+	}
+	for _, tt := range tests {
+		if got := CountLinks(tt.input); got != tt.want {
+			t.Errorf("CountLinks(%q) = %d; want %d", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package media validates uploaded images, strips their metadata and renders
+// the resized variants served by the media library.
+package media
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+)
+
+// Supported upload types.
+const (
+	TypeJPEG = "image/jpeg"
+	TypePNG  = "image/png"
+	TypeWebP = "image/webp"
+)
+
+// DefaultMaxBytes is the default upload size limit.
+const DefaultMaxBytes = 10 << 20
+
+// maxPixels bounds the decoded size, so a small file cannot claim a huge
+// canvas.
+const maxPixels = 40_000_000
+
+// VariantWidths are the widths variants are rendered at. Widths at or above
+// the original's are skipped; an image narrower than all of them gets one
+// variant at its own width.
+var VariantWidths = []int{320, 640, 1280, 1920}
+
+const (
+	originalJPEGQuality = 90
+	variantJPEGQuality  = 82
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type: use JPEG, PNG or WebP")
+	ErrInvalidImage    = errors.New("file is not a valid image")
+	ErrTooManyPixels   = errors.New("image dimensions are too large")
+)
+
+// Variant is one stored rendition of an asset.
+type Variant struct {
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	SizeBytes   int64  `json:"size_bytes"`
+}
+
+// Object is an encoded image ready to be stored.
+type Object struct {
+	Variant
+	Data []byte
+}
+
+// Asset is a processed upload: the original re-encoded without metadata and
+// its variants, all keyed below a directory named after the upload's hash.
+type Asset struct {
+	Original Object
+	Variants []Object
+}
+
+// Objects returns the original followed by the variants.
+func (a *Asset) Objects() []Object {
+	return append([]Object{a.Original}, a.Variants...)
+}
+
+// DetectType returns the sniffed content type of data if it is a supported
+// upload type. The type the client claimed is not trusted.
+func DetectType(data []byte) (string, error) {
+	switch ct := http.DetectContentType(data); ct {
+	case TypeJPEG, TypePNG, TypeWebP:
+		return ct, nil
+	}
+	return "", ErrUnsupportedType
+}
+
+// Process decodes an upload and renders the asset. Decoding and re-encoding
+// drops EXIF, XMP and every other metadata block; the EXIF orientation of
+// JPEGs is applied to the pixels first.
+func Process(data []byte) (*Asset, error) {
+	ct, err := DetectType(data)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	sum := sha256.Sum256(data)
+	dir := hex.EncodeToString(sum[:8])
+
+	original, err := encode(img, ct, originalJPEGQuality)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	asset := &Asset{Original: object(dir+"/original"+extension(ct), ct, b.Dx(), b.Dy(), original)}
+
+	for _, w := range variantWidths(b.Dx()) {
+		resized := img
+		if w != b.Dx() {
+			resized = imaging.Resize(img, w, 0, imaging.Lanczos)
+		}
+		rb := resized.Bounds()
+		key := dir + "/w" + strconv.Itoa(w)
+		jpg, err := encode(resized, TypeJPEG, variantJPEGQuality)
+		if err != nil {
+			return nil, err
+		}
+		// The WebP is lossless, so it is only worth offering when it beats
+		// the JPEG; for photos it rarely does
+		if webp, err := encode(resized, TypeWebP, 0); err == nil && len(webp) < len(jpg) {
+			asset.Variants = append(asset.Variants, object(key+extension(TypeWebP), TypeWebP, rb.Dx(), rb.Dy(), webp))
+		}
+		asset.Variants = append(asset.Variants, object(key+extension(TypeJPEG), TypeJPEG, rb.Dx(), rb.Dy(), jpg))
+	}
+	return asset, nil
+}
+
+func variantWidths(width int) []int {
+	var out []int
+	for _, w := range VariantWidths {
+		if w < width {
+			out = append(out, w)
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, width)
+	}
+	return out
+}
+
+func object(key, ct string, w, h int, data []byte) Object {
+	return Object{
+		Variant: Variant{Key: key, ContentType: ct, Width: w, Height: h, SizeBytes: int64(len(data))},
+		Data:    data,
+	}
+}
+
+// encode writes img as ct. WebP output is lossless, as no pure Go lossy
+// encoder exists; JPEG output has transparency flattened onto white.
+func encode(img image.Image, ct string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch ct {
+	case TypeJPEG:
+		flat := imaging.New(img.Bounds().Dx(), img.Bounds().Dy(), color.White)
+		flat = imaging.Overlay(flat, img, image.Point{}, 1)
+		err = jpeg.Encode(&buf, flat, &jpeg.Options{Quality: quality})
+	case TypePNG:
+		err = png.Encode(&buf, img)
+	case TypeWebP:
+		err = encodeWebP(&buf, img)
+	default:
+		err = ErrUnsupportedType
+	}
+	return buf.Bytes(), err
+}
+
+// encodeWebP turns a panic in the encoder, which some noisy images trigger,
+// into an error.
+func encodeWebP(w io.Writer, img image.Image) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("webp: %v", r)
+		}
+	}()
+	return nativewebp.Encode(w, img, nil)
+}
+
+// ContentType returns the content type for a stored key's extension.
+func ContentType(key string) string {
+	for _, ct := range []string{TypeJPEG, TypePNG, TypeWebP} {
+		ext := extension(ct)
+		if len(key) > len(ext) && key[len(key)-len(ext):] == ext {
+			return ct
+		}
+	}
+	return "application/octet-stream"
+}
+
+func extension(ct string) string {
+	switch ct {
+	case TypeJPEG:
+		return ".jpg"
+	case TypePNG:
+		return ".png"
+	case TypeWebP:
+		return ".webp"
+	}
+	return ""
+}
@@ -0,0 +1,230 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+)
+
+func pngImage(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 128})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// jpegWithOrientation encodes a w×h JPEG carrying an EXIF orientation tag.
+func jpegWithOrientation(t *testing.T, w, h int, orientation byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h)), nil); err != nil {
+		t.Fatal(err)
+	}
+	exif := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08" +
+		"\x00\x01" + // one IFD entry
+		"\x01\x12\x00\x03\x00\x00\x00\x01\x00" + string([]byte{orientation}) + "\x00\x00" +
+		"\x00\x00\x00\x00")
+	segment := append([]byte{0xFF, 0xE1, 0, byte(len(exif) + 2)}, exif...)
+	data := buf.Bytes()
+	return append(append([]byte{0xFF, 0xD8}, segment...), data[2:]...)
+}
+
+func TestProcessVariants(t *testing.T) {
+	asset, err := Process(pngImage(t, 800, 400))
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if asset.Original.ContentType != TypePNG || asset.Original.Width != 800 || asset.Original.Height != 400 {
+		t.Errorf("original = %+v", asset.Original.Variant)
+	}
+
+	want := []struct {
+		suffix string
+		ct     string
+		w, h   int
+	}{
+		{"/w320.webp", TypeWebP, 320, 160},
+		{"/w320.jpg", TypeJPEG, 320, 160},
+		{"/w640.webp", TypeWebP, 640, 320},
+		{"/w640.jpg", TypeJPEG, 640, 320},
+	}
+	if len(asset.Variants) != len(want) {
+		t.Fatalf("got %d variants, want %d", len(asset.Variants), len(want))
+	}
+	dir := asset.Original.Key[:len(asset.Original.Key)-len("/original.png")]
+	for i, v := range asset.Variants {
+		if v.Key != dir+want[i].suffix || v.ContentType != want[i].ct || v.Width != want[i].w || v.Height != want[i].h {
+			t.Errorf("variant %d = %+v, want %+v", i, v.Variant, want[i])
+		}
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(v.Data))
+		if err != nil {
+			t.Errorf("variant %s does not decode: %v", v.Key, err)
+			continue
+		}
+		if cfg.Width != v.Width || cfg.Height != v.Height || "image/"+format != v.ContentType {
+			t.Errorf("variant %s decodes as %s %dx%d", v.Key, format, cfg.Width, cfg.Height)
+		}
+		if v.SizeBytes != int64(len(v.Data)) {
+			t.Errorf("variant %s size = %d, want %d", v.Key, v.SizeBytes, len(v.Data))
+		}
+	}
+}
+
+// noisyImage encodes a w×h PNG of a gradient with grain, like a photo,
+// which lossless WebP cannot shrink below a JPEG.
+func noisyImage(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	seed := uint32(1)
+	for i := range img.Pix {
+		seed = seed*1664525 + 1013904223
+		img.Pix[i] = uint8((i/4)%w/2) + uint8(seed>>28)
+	}
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessSkipsLargerWebP(t *testing.T) {
+	asset, err := Process(noisyImage(t, 400, 200))
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(asset.Variants) != 1 || asset.Variants[0].ContentType != TypeJPEG {
+		for _, v := range asset.Variants {
+			t.Logf("%s: %d bytes", v.Key, v.SizeBytes)
+		}
+		t.Fatalf("got %d variants, want the JPEG only", len(asset.Variants))
+	}
+}
+
+func TestProcessSmallImage(t *testing.T) {
+	asset, err := Process(pngImage(t, 100, 50))
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(asset.Variants) != 2 {
+		t.Fatalf("got %d variants, want one per format", len(asset.Variants))
+	}
+	for _, v := range asset.Variants {
+		if v.Width != 100 || v.Height != 50 {
+			t.Errorf("variant %s is %dx%d, want the original size", v.Key, v.Width, v.Height)
+		}
+	}
+}
+
+func TestProcessStripsEXIF(t *testing.T) {
+	data := jpegWithOrientation(t, 40, 20, 6)
+	if !bytes.Contains(data, []byte("Exif")) {
+		t.Fatal("fixture has no EXIF segment")
+	}
+	asset, err := Process(data)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	// Orientation 6 rotates the pixels a quarter turn
+	if asset.Original.Width != 20 || asset.Original.Height != 40 {
+		t.Errorf("original is %dx%d, want 20x40", asset.Original.Width, asset.Original.Height)
+	}
+	for _, obj := range asset.Objects() {
+		if bytes.Contains(obj.Data, []byte("Exif")) {
+			t.Errorf("%s still carries EXIF", obj.Key)
+		}
+	}
+}
+
+func TestProcessRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"gif", []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;"), ErrUnsupportedType},
+		{"text", []byte("hello"), ErrUnsupportedType},
+		{"truncated png", []byte("\x89PNG\r\n\x1a\nnot really"), ErrInvalidImage},
+	}
+	for _, tt := range tests {
+		if _, err := Process(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"abc/original.png", true},
+		{"abc/w640.webp", true},
+		{"", false},
+		{".", false},
+		{"..", false},
+		{"../etc/passwd", false},
+		{"abc/../../x", false},
+		{"/abs/path", false},
+		{"abc//x", false},
+		{`abc\x`, false},
+	}
+	for _, tt := range tests {
+		if got := ValidKey(tt.key); got != tt.want {
+			t.Errorf("ValidKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	l := NewLocal(t.TempDir())
+
+	if err := l.Put(ctx, "abc/original.png", bytes.NewReader([]byte("data")), TypePNG); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	rc, err := l.Open(ctx, "abc/original.png")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(got) != "data" {
+		t.Errorf("read %q", got)
+	}
+	if _, ok := rc.(io.Seeker); !ok {
+		t.Error("local objects should be seekable")
+	}
+
+	if err := l.Delete(ctx, "abc/original.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := l.Open(ctx, "abc/original.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after delete: %v", err)
+	}
+	if err := l.Delete(ctx, "abc/original.png"); err != nil {
+		t.Errorf("Delete of a missing key: %v", err)
+	}
+	if _, err := l.Open(ctx, "abc"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open of a directory: %v", err)
+	}
+	if err := l.Put(ctx, "../escape", bytes.NewReader(nil), TypePNG); err == nil {
+		t.Error("Put accepted a key outside the root")
+	}
+}
@@ -0,0 +1,129 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned by Storage.Open for a key that holds nothing.
+var ErrNotFound = errors.New("media: object not found")
+
+// Storage keeps media objects under slash-separated keys. Local is the only
+// backend for now; an S3-compatible one only has to implement these three
+// methods.
+type Storage interface {
+	// Put stores data under key, replacing whatever was there.
+	Put(ctx context.Context, key string, data io.Reader, contentType string) error
+	// Open returns the object stored under key. The reader is also an
+	// io.Seeker when the backend supports ranged reads.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object under key. Deleting a missing key is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidKey reports whether key is a relative, clean path that cannot escape
+// the storage root.
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	return path.Clean(key) == key && key != "." && !strings.HasPrefix(key, "../") && key != ".."
+}
+
+// Local stores objects as files below a directory.
+type Local struct {
+	Dir string
+}
+
+// NewLocal returns a Local rooted at dir.
+func NewLocal(dir string) *Local {
+	return &Local{Dir: dir}
+}
+
+func (l *Local) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", errors.New("media: invalid key " + key)
+	}
+	return filepath.Join(l.Dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes to a temporary file and renames it into place, so readers never
+// see a partial object.
+func (l *Local) Put(_ context.Context, key string, data io.Reader, _ string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (l *Local) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if info, err := f.Stat(); err != nil || info.IsDir() {
+		_ = f.Close()
+		return nil, ErrNotFound
+	}
+	return f, nil
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	// Drop the asset's directory once its last object is gone
+	_ = os.Remove(filepath.Dir(p))
+	return nil
+}
+
+// Library is the media storage together with the settings of the upload and
+// serving routes.
+type Library struct {
+	Storage Storage
+	// BaseURL is the URL objects are served under, e.g. /api/media.
+	BaseURL string
+	// MaxBytes limits the size of an upload.
+	MaxBytes int64
+}
+
+// URL returns the public URL of the object under key.
+func (l *Library) URL(key string) string {
+	return strings.TrimSuffix(l.BaseURL, "/") + "/" + key
+}
@@ -0,0 +1,57 @@
+// Package mergepatch applies JSON Merge Patch documents (RFC 7396). Members
+// of the patch replace those of the target, objects merge recursively, and a
+// null member removes the key. Any patch that is not an object replaces the
+// target as a whole.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ContentType is the media type of a merge patch document.
+const ContentType = "application/merge-patch+json"
+
+// Apply returns target with patch merged in.
+func Apply(target, patch []byte) ([]byte, error) {
+	var t, p interface{}
+	if len(bytes.TrimSpace(target)) > 0 {
+		if err := decode(target, &t); err != nil {
+			return nil, err
+		}
+	}
+	if err := decode(patch, &p); err != nil {
+		return nil, err
+	}
+	return json.Marshal(Merge(t, p))
+}
+
+// Merge applies a decoded patch to a decoded target. Neither is modified.
+func Merge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	out := make(map[string]interface{}, len(t)+len(p))
+	if ok {
+		for k, v := range t {
+			out[k] = v
+		}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		out[k] = Merge(out[k], v)
+	}
+	return out
+}
+
+// decode keeps numbers as json.Number so large integers survive the round trip.
+func decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -24,6 +24,15 @@ var (
 		[]string{"event_name"},
 	)
 
+	// CommentCounter tracks submitted comments by their initial status
+	CommentCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "comments_submitted_total",
+			Help: "Total number of submitted comments by initial moderation status",
+		},
+		[]string{"status"},
+	)
+
 	// HTTPRequestDuration tracks request duration by route and method
 	HTTPRequestDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -52,6 +61,44 @@ var (
 		},
 		[]string{"operation"},
 	)
+
+	// ResponseCacheHits and ResponseCacheMisses count lookups in the
+	// in-process response cache
+	ResponseCacheHits = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "response_cache_hits_total",
+			Help: "Total number of requests served from the response cache",
+		},
+	)
+	ResponseCacheMisses = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "response_cache_misses_total",
+			Help: "Total number of cacheable requests not found in the response cache",
+		},
+	)
+
+	// ResponseCacheEvictions counts entries dropped by reason (capacity, expired, invalidated)
+	ResponseCacheEvictions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "response_cache_evictions_total",
+			Help: "Total number of response cache entries dropped by reason",
+		},
+		[]string{"reason"},
+	)
+
+	// ResponseCacheEntries and ResponseCacheBytes track the response cache size
+	ResponseCacheEntries = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "response_cache_entries",
+			Help: "Number of entries in the response cache",
+		},
+	)
+	ResponseCacheBytes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "response_cache_bytes",
+			Help: "Bytes held by the response cache",
+		},
+	)
 )
 
 // IncrementPageView increments the page view counter
@@ -66,6 +113,11 @@ func IncrementEvent(eventName string) {
 	}
 }
 
+// IncrementComment increments the submitted comment counter
+func IncrementComment(status string) {
+	CommentCounter.WithLabelValues(status).Inc()
+}
+
 // ObserveHTTPRequestDuration records HTTP request duration
 func ObserveHTTPRequestDuration(route, method, statusCode string, durationSeconds float64) {
 	HTTPRequestDuration.WithLabelValues(route, method, statusCode).Observe(durationSeconds)
@@ -80,3 +132,24 @@ func IncrementHTTPRequests(route, method, statusCode string) {
 func ObserveDBQueryDuration(operation string, durationSeconds float64) {
 	DBQueryDuration.WithLabelValues(operation).Observe(durationSeconds)
 }
+
+// IncrementResponseCacheHit counts a response served from the cache
+func IncrementResponseCacheHit() {
+	ResponseCacheHits.Inc()
+}
+
+// IncrementResponseCacheMiss counts a cacheable request the cache could not serve
+func IncrementResponseCacheMiss() {
+	ResponseCacheMisses.Inc()
+}
+
+// IncrementResponseCacheEviction counts an entry dropped from the cache
+func IncrementResponseCacheEviction(reason string) {
+	ResponseCacheEvictions.WithLabelValues(reason).Inc()
+}
+
+// SetResponseCacheSize records the current size of the cache
+func SetResponseCacheSize(entries int, bytes int64) {
+	ResponseCacheEntries.Set(float64(entries))
+	ResponseCacheBytes.Set(float64(bytes))
+}
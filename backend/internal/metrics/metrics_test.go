@@ -36,3 +36,17 @@ func TestObserveDBQueryDuration(_ *testing.T) {
 	ObserveDBQueryDuration("get_project_by_slug", 0.005)
 	ObserveDBQueryDuration("create_project", 0.010)
 }
+
+func TestIncrementComment(_ *testing.T) {
+	// Test that the function doesn't panic with various inputs
+	IncrementComment("pending")
+	IncrementComment("spam")
+}
+
+func TestResponseCacheMetrics(_ *testing.T) {
+	// Test that the functions don't panic
+	IncrementResponseCacheHit()
+	IncrementResponseCacheMiss()
+	IncrementResponseCacheEviction("capacity")
+	SetResponseCacheSize(3, 1024)
+}
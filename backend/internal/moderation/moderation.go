@@ -0,0 +1,99 @@
+// Package moderation decides the initial status of reader comments using a few
+// cheap spam heuristics. Anything it lets through still lands in the
+// moderation queue unless the author is signed in.
+package moderation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/markdown"
+)
+
+// Comment statuses.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusSpam     = "spam"
+	StatusDeleted  = "deleted"
+)
+
+// Statuses lists every valid status.
+var Statuses = []string{StatusPending, StatusApproved, StatusSpam, StatusDeleted}
+
+// Reasons recorded in comments.spam_reasons.
+const (
+	ReasonHoneypot = "honeypot"
+	ReasonLinks    = "too_many_links"
+	ReasonShouting = "all_caps"
+)
+
+const (
+	// MaxLinks is the most links a comment may carry before it is marked spam.
+	MaxLinks = 2
+	// RateLimit comments per RateWindow are accepted from one IP.
+	RateLimit  = 5
+	RateWindow = 10 * time.Minute
+)
+
+// Input is what the heuristics look at.
+type Input struct {
+	Body          string
+	Honeypot      string
+	Authenticated bool
+}
+
+// Verdict is the initial status for a comment and why.
+type Verdict struct {
+	Status  string
+	Reasons []string
+}
+
+// Check applies the heuristics. Signed-in authors skip the queue; anonymous
+// comments start as pending, or as spam when a heuristic fires.
+func Check(in Input) Verdict {
+	var reasons []string
+	if strings.TrimSpace(in.Honeypot) != "" {
+		reasons = append(reasons, ReasonHoneypot)
+	}
+	if markdown.CountLinks(in.Body) > MaxLinks {
+		reasons = append(reasons, ReasonLinks)
+	}
+	if shouting(in.Body) {
+		reasons = append(reasons, ReasonShouting)
+	}
+
+	switch {
+	case len(reasons) > 0:
+		return Verdict{Status: StatusSpam, Reasons: reasons}
+	case in.Authenticated:
+		return Verdict{Status: StatusApproved, Reasons: []string{}}
+	default:
+		return Verdict{Status: StatusPending, Reasons: []string{}}
+	}
+}
+
+// ValidStatus reports whether s is a known comment status.
+func ValidStatus(s string) bool {
+	for _, v := range Statuses {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// shouting reports whether a reasonably long body is written in capitals.
+func shouting(body string) bool {
+	letters, upper := 0, 0
+	for _, r := range body {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			letters++
+			upper++
+		case r >= 'a' && r <= 'z':
+			letters++
+		}
+	}
+	return letters >= 20 && upper*10 >= letters*9
+}
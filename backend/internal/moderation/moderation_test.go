@@ -0,0 +1,44 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          Input
+		wantStatus  string
+		wantReasons []string
+	}{
+		{"clean anonymous", Input{Body: "Nice post, thanks!"}, StatusPending, []string{}},
+		{"clean signed in", Input{Body: "Thanks all", Authenticated: true}, StatusApproved, []string{}},
+		{"honeypot", Input{Body: "hello", Honeypot: "http://spam.example"}, StatusSpam, []string{ReasonHoneypot}},
+		{"two links ok", Input{Body: "see https://a.example and https://b.example"}, StatusPending, []string{}},
+		{"three links", Input{Body: "https://a.example https://b.example www.c.example"}, StatusSpam, []string{ReasonLinks}},
+		{"shouting", Input{Body: "BUY CHEAP WATCHES TODAY ONLY"}, StatusSpam, []string{ReasonShouting}},
+		{"short caps ok", Input{Body: "LGTM"}, StatusPending, []string{}},
+		{"signed in spam", Input{Body: "x", Honeypot: "y", Authenticated: true}, StatusSpam, []string{ReasonHoneypot}},
+	}
+	for _, tt := range tests {
+		got := Check(tt.in)
+		if got.Status != tt.wantStatus {
+			t.Errorf("%s: status = %q; want %q", tt.name, got.Status, tt.wantStatus)
+		}
+		if !reflect.DeepEqual(got.Reasons, tt.wantReasons) {
+			t.Errorf("%s: reasons = %v; want %v", tt.name, got.Reasons, tt.wantReasons)
+		}
+	}
+}
+
+func TestValidStatus(t *testing.T) {
+	for _, s := range Statuses {
+		if !ValidStatus(s) {
+			t.Errorf("ValidStatus(%q) = false", s)
+		}
+	}
+	if ValidStatus("archived") {
+		t.Error(`ValidStatus("archived") = true`)
+	}
+}
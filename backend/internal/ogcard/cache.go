@@ -0,0 +1,105 @@
+package ogcard
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Cache keeps rendered cards as files under Dir/{kind}/{id}-{fingerprint}.png.
+// A record has at most one card: storing a new fingerprint removes the
+// others, and Invalidate removes them all.
+type Cache struct {
+	Dir string
+}
+
+func (c *Cache) pattern(kind string, id int32) string {
+	return filepath.Join(c.Dir, kind, strconv.Itoa(int(id))+"-*.png")
+}
+
+func (c *Cache) path(kind string, id int32, fingerprint string) string {
+	return filepath.Join(c.Dir, kind, strconv.Itoa(int(id))+"-"+fingerprint+".png")
+}
+
+// Get returns the stored card, or an error satisfying
+// errors.Is(err, os.ErrNotExist).
+func (c *Cache) Get(kind string, id int32, fingerprint string) ([]byte, error) {
+	return os.ReadFile(c.path(kind, id, fingerprint))
+}
+
+// Put stores a card through a temporary file, so readers never see a partial
+// image, and drops the record's older cards.
+func (c *Cache) Put(kind string, id int32, fingerprint string, data []byte) error {
+	dir := filepath.Join(c.Dir, kind)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".card-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	p := c.path(kind, id, fingerprint)
+	old, _ := filepath.Glob(c.pattern(kind, id))
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return err
+	}
+	for _, f := range old {
+		if f != p {
+			_ = os.Remove(f)
+		}
+	}
+	return nil
+}
+
+// Invalidate removes every card of a record.
+func (c *Cache) Invalidate(kind string, id int32) error {
+	files, err := filepath.Glob(c.pattern(kind, id))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Purge removes every card of a kind.
+func (c *Cache) Purge(kind string) error {
+	return os.RemoveAll(filepath.Join(c.Dir, kind))
+}
+
+// Generator renders cards through the cache.
+type Generator struct {
+	Renderer *Renderer
+	Cache    *Cache
+}
+
+// PNG returns the card of a record and its fingerprint. A card that cannot
+// be cached is logged and still returned.
+func (g *Generator) PNG(kind string, id int32, c Card) ([]byte, string, error) {
+	fingerprint := g.Renderer.Fingerprint(c)
+	if data, err := g.Cache.Get(kind, id, fingerprint); err == nil {
+		return data, fingerprint, nil
+	}
+	data, err := g.Renderer.PNG(c)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := g.Cache.Put(kind, id, fingerprint, data); err != nil {
+		log.Printf("Failed to cache %s card %d: %v", kind, id, err)
+	}
+	return data, fingerprint, nil
+}
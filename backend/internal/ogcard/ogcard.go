@@ -0,0 +1,409 @@
+// Package ogcard renders the 1200×630 Open Graph preview images of projects
+// and posts in the site's terminal style, with the Go fonts embedded in
+// golang.org/x/image, and caches them on disk.
+package ogcard
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Width and Height are the size of a card, the one Open Graph and Twitter
+// recommend for large previews.
+const (
+	Width  = 1200
+	Height = 630
+)
+
+// renderVersion is part of every fingerprint; bump it when the layout
+// changes so cached cards are redrawn.
+const renderVersion = "1"
+
+// Card is what a preview shows.
+type Card struct {
+	// Path is shown in the prompt, e.g. ~/projects/onnwee.
+	Path  string   `json:"path"`
+	Title string   `json:"title"`
+	Emoji string   `json:"emoji"`
+	Tags  []string `json:"tags"`
+	// Color is the accent: green, pink, cyan or yellow. Anything else is
+	// drawn green.
+	Color string `json:"color"`
+	// Site is shown in the window title, e.g. onnwee.github.io.
+	Site string `json:"site"`
+}
+
+// Catppuccin Mocha, as in client/src/index.css and TerminalCard.
+var (
+	accents = map[string]color.RGBA{
+		"green":  {166, 227, 161, 255},
+		"pink":   {245, 194, 231, 255},
+		"cyan":   {148, 226, 213, 255},
+		"yellow": {249, 226, 175, 255},
+	}
+	crust     = color.RGBA{17, 17, 27, 255}
+	base      = color.RGBA{30, 30, 46, 255}
+	surface   = color.RGBA{37, 39, 58, 255}
+	border    = color.RGBA{59, 63, 92, 255}
+	text      = color.RGBA{205, 214, 244, 255}
+	textMuted = color.RGBA{166, 173, 200, 255}
+	red       = color.RGBA{243, 139, 168, 255}
+)
+
+// accentOf returns the accent of a card and the color of its glitch shadow.
+func accentOf(name string) (color.RGBA, color.RGBA) {
+	accent, ok := accents[name]
+	if !ok {
+		name, accent = "green", accents["green"]
+	}
+	if name == "pink" {
+		return accent, accents["cyan"]
+	}
+	return accent, accents["pink"]
+}
+
+// Renderer draws cards. It is safe for concurrent use.
+type Renderer struct {
+	mono, bold *opentype.Font
+	// emoji is an optional fallback font; the Go fonts have no emoji.
+	emoji *opentype.Font
+}
+
+// NewRenderer parses the embedded fonts and, when emojiFont is not empty,
+// a fallback font for emoji (e.g. the monochrome Noto Emoji).
+func NewRenderer(emojiFont []byte) (*Renderer, error) {
+	mono, err := opentype.Parse(gomono.TTF)
+	if err != nil {
+		return nil, err
+	}
+	bold, err := opentype.Parse(gomonobold.TTF)
+	if err != nil {
+		return nil, err
+	}
+	r := &Renderer{mono: mono, bold: bold}
+	if len(emojiFont) > 0 {
+		if r.emoji, err = opentype.Parse(emojiFont); err != nil {
+			return nil, fmt.Errorf("ogcard: emoji font: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// Fingerprint identifies the image Render draws for c, for cache keys and
+// entity tags.
+func (r *Renderer) Fingerprint(c Card) string {
+	data, _ := json.Marshal(c)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%t\x00", renderVersion, r.emoji != nil)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// PNG renders c and encodes it.
+func (r *Renderer) PNG(c Card) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, r.Render(c)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Layout of the terminal window, in pixels.
+const (
+	margin     = 48
+	titleBarH  = 56
+	padX       = 96
+	promptY    = margin + titleBarH + 72
+	titleTop   = promptY + 56
+	tagsY      = Height - margin - 36
+	titleSize  = 56
+	titleLines = 3
+	emojiSize  = 72
+)
+
+// Render draws c.
+func (r *Renderer) Render(c Card) *image.RGBA {
+	accent, glitch := accentOf(c.Color)
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	fill(img, img.Bounds(), crust)
+
+	// Window with its title bar
+	win := image.Rect(margin, margin, Width-margin, Height-margin)
+	fill(img, win, accent)
+	fill(img, win.Inset(2), base)
+	bar := image.Rect(win.Min.X+2, win.Min.Y+2, win.Max.X-2, win.Min.Y+titleBarH)
+	fill(img, bar, surface)
+	fill(img, image.Rect(bar.Min.X, bar.Max.Y, bar.Max.X, bar.Max.Y+2), border)
+	for i, dot := range []color.RGBA{red, accents["yellow"], accents["green"]} {
+		circle(img, bar.Min.X+32+i*32, bar.Min.Y+bar.Dy()/2, 9, dot)
+	}
+
+	small := r.faces(24)
+	barTitle := "onnwee@" + c.Site
+	if c.Site == "" {
+		barTitle = "onnwee"
+	}
+	barTitle = truncate(small, barTitle, bar.Dx()-240)
+	drawText(img, small, barTitle, (Width-measure(small, barTitle))/2, bar.Min.Y+bar.Dy()/2+8, textMuted)
+
+	// Prompt with a block cursor
+	prompt := r.faces(30)
+	x := padX
+	x = drawText(img, prompt, "$ ", x, promptY, accent)
+	x = drawText(img, prompt, truncate(prompt, "cat "+c.Path, Width-2*padX-40), x, promptY, textMuted)
+	fill(img, image.Rect(x+6, promptY-26, x+24, promptY+6), accent)
+
+	// Emoji and title, with a chromatic split and displaced slices
+	titleFaces := r.boldFaces(titleSize)
+	top := titleTop
+	textX := padX
+	if emoji := r.emojiFaces(emojiSize); emoji != nil && c.Emoji != "" && measure(emoji, c.Emoji) > 0 {
+		drawText(img, emoji, c.Emoji, padX, top+emojiSize, accent)
+		textX = padX + measure(emoji, c.Emoji) + 28
+	}
+	lines := wrap(titleFaces, c.Title, Width-padX-textX, titleLines)
+	lineH := titleSize * 5 / 4
+	for i, line := range lines {
+		y := top + titleSize + i*lineH
+		drawText(img, titleFaces, line, textX-4, y, withAlpha(glitch, 150))
+		drawText(img, titleFaces, line, textX+4, y, withAlpha(accent, 150))
+		drawText(img, titleFaces, line, textX, y, text)
+	}
+	if len(lines) > 0 {
+		glitchSlices(img, c.Title, image.Rect(win.Min.X+2, top, win.Max.X-2, top+len(lines)*lineH+16))
+	}
+
+	// Tags as outlined chips, as many as fit
+	tagFaces := r.faces(24)
+	x = padX
+	for _, tag := range c.Tags {
+		label := "#" + tag
+		w := measure(tagFaces, label) + 32
+		if x+w > Width-padX {
+			break
+		}
+		chip := image.Rect(x, tagsY-36, x+w, tagsY+12)
+		fill(img, chip, accent)
+		fill(img, chip.Inset(2), surface)
+		drawText(img, tagFaces, label, x+16, tagsY-4, accent)
+		x += w + 16
+	}
+
+	scanlines(img)
+	return img
+}
+
+// faces returns the regular Go Mono face, followed by the emoji fallback.
+func (r *Renderer) faces(size float64) []font.Face {
+	return r.withEmoji(newFace(r.mono, size), size)
+}
+
+func (r *Renderer) boldFaces(size float64) []font.Face {
+	return r.withEmoji(newFace(r.bold, size), size)
+}
+
+// emojiFaces returns nil without an emoji font.
+func (r *Renderer) emojiFaces(size float64) []font.Face {
+	if r.emoji == nil {
+		return nil
+	}
+	return []font.Face{newFace(r.emoji, size)}
+}
+
+func (r *Renderer) withEmoji(primary font.Face, size float64) []font.Face {
+	if r.emoji == nil {
+		return []font.Face{primary}
+	}
+	return []font.Face{primary, newFace(r.emoji, size)}
+}
+
+func newFace(f *opentype.Font, size float64) font.Face {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		// Only fails for a non-positive size
+		panic(err)
+	}
+	return face
+}
+
+// faceFor returns the first face with a glyph for r, or nil. Invisible
+// joiners and variation selectors of emoji sequences have none.
+func faceFor(faces []font.Face, r rune) font.Face {
+	if unicode.Is(unicode.Variation_Selector, r) || r == '\u200d' {
+		return nil
+	}
+	for _, f := range faces {
+		if _, ok := f.GlyphAdvance(r); ok {
+			return f
+		}
+	}
+	return nil
+}
+
+// measure returns the advance of s in pixels, skipping runes no face has.
+func measure(faces []font.Face, s string) int {
+	var w fixed.Int26_6
+	for _, r := range s {
+		if f := faceFor(faces, r); f != nil {
+			adv, _ := f.GlyphAdvance(r)
+			w += adv
+		}
+	}
+	return w.Ceil()
+}
+
+// drawText draws s with its baseline at y and returns the x it ended at.
+func drawText(dst draw.Image, faces []font.Face, s string, x, y int, c color.Color) int {
+	d := font.Drawer{Dst: dst, Src: image.NewUniform(c), Dot: fixed.P(x, y)}
+	for _, r := range s {
+		if d.Face = faceFor(faces, r); d.Face != nil {
+			d.DrawString(string(r))
+		}
+	}
+	return d.Dot.X.Ceil()
+}
+
+// truncate shortens s with an ellipsis to fit in width pixels.
+func truncate(faces []font.Face, s string, width int) string {
+	if measure(faces, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && measure(faces, string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimRight(string(runes), " ") + "…"
+}
+
+// wrap breaks s into at most maxLines lines of width pixels, breaking
+// inside words that are too long on their own. The last line is truncated
+// when the text does not fit.
+func wrap(faces []font.Face, s string, width, maxLines int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if measure(faces, candidate) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		// Break a word wider than a line
+		for measure(faces, word) > width {
+			runes := []rune(word)
+			n := len(runes) - 1
+			for n > 1 && measure(faces, string(runes[:n])) > width {
+				n--
+			}
+			lines = append(lines, string(runes[:n]))
+			word = string(runes[n:])
+		}
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	if len(lines) > maxLines {
+		last := strings.Join(lines[maxLines-1:], " ")
+		lines = append(lines[:maxLines-1], truncate(faces, last, width))
+	}
+	return lines
+}
+
+func fill(dst *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(dst, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func withAlpha(c color.RGBA, a uint8) color.NRGBA {
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: a}
+}
+
+// circle fills an anti-aliased disc.
+func circle(dst *image.RGBA, cx, cy, radius int, c color.RGBA) {
+	for y := cy - radius - 1; y <= cy+radius+1; y++ {
+		for x := cx - radius - 1; x <= cx+radius+1; x++ {
+			dx, dy := float64(x-cx)+0.5, float64(y-cy)+0.5
+			cover := float64(radius) + 0.5 - math.Sqrt(dx*dx+dy*dy)
+			if cover <= 0 {
+				continue
+			}
+			if cover > 1 {
+				cover = 1
+			}
+			dst.Set(x, y, blend(dst.RGBAAt(x, y), c, cover))
+		}
+	}
+}
+
+// glitchSlices shifts a few horizontal bands of r sideways. The bands are
+// derived from seed, so a card is always drawn the same way.
+func glitchSlices(img *image.RGBA, seed string, r image.Rectangle) {
+	h := fnv.New32a()
+	h.Write([]byte(seed))
+	state := h.Sum32()
+	next := func(n int) int {
+		state = state*1664525 + 1013904223
+		return int(state>>8) % n
+	}
+
+	row := make([]byte, r.Dx()*4)
+	for i := 0; i < 3; i++ {
+		bandH := 3 + next(6)
+		y0 := r.Min.Y + next(max(1, r.Dy()-bandH))
+		shift := next(25) - 12
+		if shift == 0 {
+			shift = 8
+		}
+		for y := y0; y < y0+bandH && y < r.Max.Y; y++ {
+			off := img.PixOffset(r.Min.X, y)
+			copy(row, img.Pix[off:off+len(row)])
+			for x := 0; x < r.Dx(); x++ {
+				src := x - shift
+				if src < 0 || src >= r.Dx() {
+					continue
+				}
+				copy(img.Pix[off+x*4:off+x*4+4], row[src*4:src*4+4])
+			}
+		}
+	}
+}
+
+// scanlines darkens every third row, like an old CRT.
+func scanlines(img *image.RGBA) {
+	for y := 0; y < Height; y += 3 {
+		off := img.PixOffset(0, y)
+		for x := 0; x < Width*4; x += 4 {
+			for c := 0; c < 3; c++ {
+				p := &img.Pix[off+x+c]
+				*p = uint8(int(*p) * 7 / 8)
+			}
+		}
+	}
+}
+
+func blend(dst, src color.RGBA, a float64) color.RGBA {
+	mix := func(d, s uint8) uint8 { return uint8(float64(d)*(1-a) + float64(s)*a + 0.5) }
+	return color.RGBA{mix(dst.R, src.R), mix(dst.G, src.G), mix(dst.B, src.B), 255}
+}
@@ -0,0 +1,146 @@
+package ogcard
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newRenderer(t *testing.T) *Renderer {
+	t.Helper()
+	r, err := NewRenderer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRenderPNG(t *testing.T) {
+	r := newRenderer(t)
+	data, err := r.PNG(Card{
+		Path:  "~/projects/onnwee",
+		Title: strings.Repeat("A very long project title ", 10),
+		Emoji: "🧪",
+		Tags:  []string{"go", "react", "postgres"},
+		Color: "pink",
+		Site:  "onnwee.github.io",
+	})
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != Width || cfg.Height != Height {
+		t.Errorf("card is %dx%d, want %dx%d", cfg.Width, cfg.Height, Width, Height)
+	}
+}
+
+func TestRenderAccent(t *testing.T) {
+	r := newRenderer(t)
+	for name, want := range accents {
+		img := r.Render(Card{Title: "x", Color: name})
+		// The window border is drawn in the accent (off the scanlines)
+		if got := img.RGBAAt(margin, Height/2+1); got != want {
+			t.Errorf("%s: border = %v, want %v", name, got, want)
+		}
+	}
+	img := r.Render(Card{Title: "x", Color: "purple"})
+	if got := img.RGBAAt(margin, Height/2+1); got != accents["green"] {
+		t.Errorf("unknown color: border = %v, want green", got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	r := newRenderer(t)
+	c := Card{Path: "~/blog/hello", Title: "Hello", Tags: []string{"go"}}
+	fp := r.Fingerprint(c)
+	if fp != r.Fingerprint(c) {
+		t.Error("fingerprint is not stable")
+	}
+	changed := c
+	changed.Tags = []string{"go", "web"}
+	if r.Fingerprint(changed) == fp {
+		t.Error("fingerprint ignores tags")
+	}
+	changed = c
+	changed.Title = "Hello!"
+	if r.Fingerprint(changed) == fp {
+		t.Error("fingerprint ignores the title")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	r := newRenderer(t)
+	faces := r.boldFaces(titleSize)
+	width := 600
+
+	lines := wrap(faces, "Short title", width, titleLines)
+	if len(lines) != 1 || lines[0] != "Short title" {
+		t.Errorf("short title wrapped as %q", lines)
+	}
+
+	lines = wrap(faces, strings.Repeat("word ", 40), width, titleLines)
+	if len(lines) != titleLines {
+		t.Fatalf("got %d lines, want %d", len(lines), titleLines)
+	}
+	if !strings.HasSuffix(lines[len(lines)-1], "…") {
+		t.Errorf("last line %q is not truncated", lines[len(lines)-1])
+	}
+
+	lines = wrap(faces, strings.Repeat("x", 60), width, titleLines)
+	for _, l := range lines {
+		if measure(faces, l) > width {
+			t.Errorf("line %q is wider than %d", l, width)
+		}
+	}
+	if len(lines) < 2 {
+		t.Errorf("long word was not broken: %q", lines)
+	}
+}
+
+func TestCache(t *testing.T) {
+	const projectKind = "projects"
+	c := &Cache{Dir: t.TempDir()}
+
+	if _, err := c.Get(projectKind, 1, "a"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Get of a missing card: %v", err)
+	}
+	if err := c.Put(projectKind, 1, "a", []byte("one")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put(projectKind, 12, "a", []byte("twelve")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put(projectKind, 1, "b", []byte("two")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(projectKind, 1, "a"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("older card was kept: %v", err)
+	}
+	if got, err := c.Get(projectKind, 1, "b"); err != nil || string(got) != "two" {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+
+	if err := c.Invalidate(projectKind, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(projectKind, 1, "b"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("card survived Invalidate: %v", err)
+	}
+	if _, err := c.Get(projectKind, 12, "a"); err != nil {
+		t.Errorf("Invalidate of 1 dropped 12: %v", err)
+	}
+
+	if err := c.Purge(projectKind); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(c.Dir, projectKind)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Purge left the directory: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package postlinks finds the projects a post refers to. Links written as
+// /projects/{slug} in the post body (relative, or absolute on SITE_URL) are
+// stored as "detected" rows in post_projects and rebuilt on every save;
+// links attached by hand are "manual" and left alone.
+package postlinks
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+)
+
+// Sources recorded in post_projects.source.
+const (
+	SourceManual   = "manual"
+	SourceDetected = "detected"
+)
+
+var linkRe = regexp.MustCompile(`((?i:https?)://[^\s/"'()<>\]]+)?/(?:api/)?projects/([a-z0-9]+(?:-[a-z0-9]+)*)`)
+
+// Detect returns the project slugs linked from content, in order of first
+// appearance. Absolute links only count when they point at siteURL.
+func Detect(content, siteURL string) []string {
+	site := strings.ToLower(strings.TrimRight(siteURL, "/"))
+	seen := map[string]bool{}
+	var slugs []string
+	for _, m := range linkRe.FindAllStringSubmatchIndex(content, -1) {
+		if m[2] >= 0 {
+			if strings.ToLower(content[m[2]:m[3]]) != site {
+				continue
+			}
+		} else if m[0] > 0 && !boundary(content[m[0]-1]) {
+			// Part of a longer path or a foreign URL, e.g. /docs/projects/x
+			continue
+		}
+		if m[1] < len(content) && continues(content[m[1]]) {
+			continue
+		}
+		slug := content[m[4]:m[5]]
+		if !seen[slug] {
+			seen[slug] = true
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs
+}
+
+// boundary reports whether c may precede a relative link.
+func boundary(c byte) bool {
+	return !isAlnum(c) && c != '/' && c != '.' && c != '-' && c != '_'
+}
+
+// continues reports whether c would make the slug longer than matched, as
+// in /projects/Foo or /projects/foo_bar.
+func continues(c byte) bool {
+	return isAlnum(c) || c == '_'
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// Sync replaces the detected links of a post with those found in content.
+// Slugs that match no project are ignored.
+func Sync(ctx context.Context, q *db.Queries, postID int32, content, siteURL string) error {
+	if err := q.ClearDetectedPostProjects(ctx, postID); err != nil {
+		return err
+	}
+	slugs := Detect(content, siteURL)
+	if len(slugs) == 0 {
+		return nil
+	}
+	ids, err := q.ListProjectIDsBySlugs(ctx, slugs)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := q.AttachPostProject(ctx, db.AttachPostProjectParams{
+			PostID:    postID,
+			ProjectID: id,
+			Source:    SourceDetected,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package postlinks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	const site = "https://onnwee.github.io"
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"See [the app](/projects/alpha) for more.", "alpha"},
+		{"<a href=\"/projects/alpha-two\">x</a>", "alpha-two"},
+		{"API: /api/projects/beta", "beta"},
+		{"Full link https://onnwee.github.io/projects/gamma.", "gamma"},
+		{"Other site https://example.com/projects/delta", ""},
+		{"Nested /docs/projects/epsilon", ""},
+		{"Relative ../projects/zeta", ""},
+		{"Upper /projects/Eta and /projects/theta_x", ""},
+		{"Twice /projects/a then /projects/b then /projects/a", "a,b"},
+		{"/projects/", ""},
+		{"/projects/iota#readme and /projects/kappa?tab=1", "iota,kappa"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(Detect(tt.content, site), ","); got != tt.want {
+			t.Errorf("Detect(%q) = %q; want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestDetectSiteURLCase(t *testing.T) {
+	got := Detect("HTTPS://Onnwee.GitHub.io/projects/alpha", "https://onnwee.github.io/")
+	if strings.Join(got, ",") != "alpha" {
+		t.Errorf("Detect = %v; want [alpha]", got)
+	}
+}
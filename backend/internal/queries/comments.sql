@@ -0,0 +1,26 @@
+-- name: CreateComment :one
+INSERT INTO comments (
+    post_id, parent_id, user_id, author_name, author_email,
+    body, body_html, status, spam_reasons, ip_hash, user_agent
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetCommentByID :one
+SELECT * FROM comments
+WHERE id = $1;
+
+-- name: ListApprovedCommentsByPost :many
+SELECT * FROM comments
+WHERE post_id = $1 AND status = 'approved'
+ORDER BY created_at ASC, id ASC;
+
+-- name: CountRecentCommentsByIP :one
+SELECT COUNT(*) FROM comments
+WHERE ip_hash = $1 AND created_at > $2;
+
+-- name: SetCommentStatus :execrows
+UPDATE comments
+SET status = sqlc.arg(status),
+    updated_at = NOW()
+WHERE id = ANY(sqlc.arg(ids)::int[]);
@@ -0,0 +1,17 @@
+-- name: InsertDomainEvent :one
+INSERT INTO domain_events (name, payload, origin)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListDomainEventsAfter :many
+SELECT * FROM domain_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: GetLatestDomainEventID :one
+SELECT COALESCE(MAX(id), 0)::BIGINT AS latest_id FROM domain_events;
+
+-- name: DeleteDomainEventsBefore :execrows
+DELETE FROM domain_events
+WHERE created_at < $1;
@@ -0,0 +1,28 @@
+-- name: ListMedia :many
+SELECT * FROM media
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetMedia :one
+SELECT * FROM media
+WHERE id = $1;
+
+-- name: GetMediaByKey :one
+SELECT * FROM media
+WHERE key = $1;
+
+-- name: CreateMedia :one
+INSERT INTO media (key, filename, content_type, size_bytes, width, height, alt_text, variants)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: UpdateMediaAltText :one
+UPDATE media
+SET alt_text = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteMedia :one
+DELETE FROM media
+WHERE id = $1
+RETURNING *;
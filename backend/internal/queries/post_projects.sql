@@ -0,0 +1,29 @@
+-- name: AttachPostProject :exec
+INSERT INTO post_projects (post_id, project_id, source)
+VALUES ($1, $2, $3)
+ON CONFLICT (post_id, project_id) DO UPDATE
+SET source = CASE WHEN post_projects.source = 'manual' THEN 'manual' ELSE EXCLUDED.source END;
+
+-- name: DetachPostProject :execrows
+DELETE FROM post_projects
+WHERE post_id = $1 AND project_id = $2;
+
+-- name: ClearDetectedPostProjects :exec
+DELETE FROM post_projects
+WHERE post_id = $1 AND source = 'detected';
+
+-- name: ListProjectsByPost :many
+SELECT j.* FROM post_projects pp
+JOIN projects j ON j.id = pp.project_id
+WHERE pp.post_id = $1 AND j.status <> 'draft'
+ORDER BY j.title;
+
+-- name: ListPublishedPostsByProject :many
+SELECT p.* FROM post_projects pp
+JOIN posts p ON p.id = pp.post_id
+WHERE pp.project_id = $1 AND p.is_draft = FALSE
+ORDER BY COALESCE(p.published_at, p.created_at) DESC, p.id DESC;
+
+-- name: ListProjectIDsBySlugs :many
+SELECT id FROM projects
+WHERE slug = ANY(sqlc.arg(slugs)::text[]);
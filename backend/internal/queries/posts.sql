@@ -7,21 +7,69 @@ LIMIT $1 OFFSET $2;
 -- name: GetPostBySlug :one
 SELECT * FROM posts WHERE slug = $1;
 
+-- name: GetPostByID :one
+SELECT * FROM posts WHERE id = $1;
+
 -- name: CreatePost :one
-INSERT INTO posts (title, slug, summary, content, tags, is_draft, user_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO posts (title, slug, summary, content, tags, is_draft, user_id, cover_image)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: UpdatePost :one
 UPDATE posts
 SET title = $2,
+    slug = $7,
     summary = $3,
     content = $4,
     tags = $5,
     is_draft = $6,
+    cover_image = $8,
     updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
 -- name: DeletePost :exec
 DELETE FROM posts WHERE id = $1;
+
+-- name: ListAllPosts :many
+SELECT * FROM posts
+ORDER BY slug;
+
+-- name: UpsertPostBySlug :one
+INSERT INTO posts (title, slug, summary, content, tags, is_draft, published_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (slug) DO UPDATE
+SET title = EXCLUDED.title,
+    summary = EXCLUDED.summary,
+    content = EXCLUDED.content,
+    tags = EXCLUDED.tags,
+    is_draft = EXCLUDED.is_draft,
+    published_at = EXCLUDED.published_at,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ListPublishedPosts :many
+SELECT * FROM posts
+WHERE is_draft = FALSE
+ORDER BY COALESCE(published_at, created_at) DESC, id DESC;
+
+-- name: ListPostsByTag :many
+SELECT * FROM posts
+WHERE is_draft = FALSE AND sqlc.arg(tag)::text = ANY(tags)
+ORDER BY COALESCE(published_at, created_at) DESC, id DESC;
+
+-- name: GetPostByIDForUpdate :one
+SELECT * FROM posts
+WHERE id = $1
+FOR UPDATE;
+
+-- name: SetPostCoverImageMeta :one
+UPDATE posts
+SET cover_image_meta = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: ListPostsWithStaleCoverImageMeta :many
+SELECT * FROM posts
+WHERE cover_image <> COALESCE(cover_image_meta->>'src', '')
+ORDER BY id;
@@ -0,0 +1,33 @@
+-- name: ListProjectUpdates :many
+SELECT * FROM project_updates
+WHERE project_id = $1
+ORDER BY published_at DESC, id DESC;
+
+-- name: CreateProjectUpdate :one
+INSERT INTO project_updates (project_id, title, body, body_html, version, link, published_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: UpdateProjectUpdate :one
+UPDATE project_updates
+SET title = $3,
+    body = $4,
+    body_html = $5,
+    version = $6,
+    link = $7,
+    published_at = $8,
+    updated_at = NOW()
+WHERE id = $1 AND project_id = $2
+RETURNING *;
+
+-- name: DeleteProjectUpdate :execrows
+DELETE FROM project_updates
+WHERE id = $1 AND project_id = $2;
+
+-- name: ListRecentProjectUpdates :many
+SELECT u.*, p.slug AS project_slug, p.title AS project_title
+FROM project_updates u
+JOIN projects p ON p.id = u.project_id
+WHERE u.published_at <= NOW() AND p.status <> 'draft'
+ORDER BY u.published_at DESC, u.id DESC
+LIMIT $1;
@@ -1,27 +1,32 @@
 -- name: ListProjects :many
 SELECT * FROM projects
-ORDER BY created_at DESC;
+ORDER BY featured DESC, sort_order, created_at DESC;
 
 -- name: GetProjectBySlug :one
 SELECT * FROM projects
 WHERE slug = $1;
 
+-- name: GetProjectByID :one
+SELECT * FROM projects
+WHERE id = $1;
+
 -- name: CreateProject :one
 INSERT INTO projects (
     title, slug, description, repo_url, live_url,
     summary, tags, footer, href, external, color, emoji, content, image, embed,
-    user_id
+    user_id, status, featured, sort_order
 )
 VALUES (
     $1, $2, $3, $4, $5,
     $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
-    $16
+    $16, $17, $18, $19
 )
 RETURNING *;
 
 -- name: UpdateProject :one
 UPDATE projects
 SET title = $2,
+    slug = $16,
     description = $3,
     repo_url = $4,
     live_url = $5,
@@ -35,6 +40,9 @@ SET title = $2,
     content = $13,
     image = $14,
     embed = $15,
+    status = $17,
+    featured = $18,
+    sort_order = $19,
     updated_at = NOW()
 WHERE id = $1
 RETURNING *;
@@ -42,3 +50,60 @@ RETURNING *;
 -- name: DeleteProject :exec
 DELETE FROM projects
 WHERE id = $1;
+
+-- name: UpsertProjectBySlug :one
+INSERT INTO projects (
+    title, slug, summary, tags, footer, href, external, color, emoji, content, image, embed,
+    status, featured, sort_order
+)
+VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+)
+ON CONFLICT (slug) DO UPDATE
+SET title = EXCLUDED.title,
+    summary = EXCLUDED.summary,
+    tags = EXCLUDED.tags,
+    footer = EXCLUDED.footer,
+    href = EXCLUDED.href,
+    external = EXCLUDED.external,
+    color = EXCLUDED.color,
+    emoji = EXCLUDED.emoji,
+    content = EXCLUDED.content,
+    image = EXCLUDED.image,
+    embed = EXCLUDED.embed,
+    status = EXCLUDED.status,
+    featured = EXCLUDED.featured,
+    sort_order = EXCLUDED.sort_order,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ListProjectsByTag :many
+SELECT * FROM projects
+WHERE sqlc.arg(tag)::text = ANY(tags) AND status <> 'draft'
+ORDER BY featured DESC, sort_order, created_at DESC;
+
+-- name: TouchProject :exec
+UPDATE projects
+SET updated_at = NOW()
+WHERE id = $1;
+
+-- name: SetProjectSortOrder :execrows
+UPDATE projects
+SET sort_order = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetProjectByIDForUpdate :one
+SELECT * FROM projects
+WHERE id = $1
+FOR UPDATE;
+
+-- name: SetProjectImageMeta :one
+UPDATE projects
+SET image_meta = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: ListProjectsWithStaleImageMeta :many
+SELECT * FROM projects
+WHERE COALESCE(image, '') <> COALESCE(image_meta->>'src', '')
+ORDER BY id;
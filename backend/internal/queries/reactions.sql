@@ -0,0 +1,47 @@
+-- name: AddPostReaction :execrows
+INSERT INTO reactions (post_id, kind, visitor_hash)
+VALUES ($1, $2, $3)
+ON CONFLICT (post_id, kind, visitor_hash) WHERE post_id IS NOT NULL DO NOTHING;
+
+-- name: RemovePostReaction :execrows
+DELETE FROM reactions
+WHERE post_id = $1 AND kind = $2 AND visitor_hash = $3;
+
+-- name: ListPostReactionsByVisitor :many
+SELECT kind FROM reactions
+WHERE post_id = $1 AND visitor_hash = $2
+ORDER BY kind;
+
+-- name: AdjustPostReactionCount :one
+UPDATE posts
+SET reaction_counts = jsonb_set(
+    reaction_counts,
+    ARRAY[sqlc.arg(kind)::text],
+    to_jsonb(GREATEST(COALESCE((reaction_counts ->> sqlc.arg(kind)::text)::int, 0) + sqlc.arg(delta)::int, 0))
+)
+WHERE id = sqlc.arg(id)
+RETURNING reaction_counts;
+
+-- name: AddProjectReaction :execrows
+INSERT INTO reactions (project_id, kind, visitor_hash)
+VALUES ($1, $2, $3)
+ON CONFLICT (project_id, kind, visitor_hash) WHERE project_id IS NOT NULL DO NOTHING;
+
+-- name: RemoveProjectReaction :execrows
+DELETE FROM reactions
+WHERE project_id = $1 AND kind = $2 AND visitor_hash = $3;
+
+-- name: ListProjectReactionsByVisitor :many
+SELECT kind FROM reactions
+WHERE project_id = $1 AND visitor_hash = $2
+ORDER BY kind;
+
+-- name: AdjustProjectReactionCount :one
+UPDATE projects
+SET reaction_counts = jsonb_set(
+    reaction_counts,
+    ARRAY[sqlc.arg(kind)::text],
+    to_jsonb(GREATEST(COALESCE((reaction_counts ->> sqlc.arg(kind)::text)::int, 0) + sqlc.arg(delta)::int, 0))
+)
+WHERE id = sqlc.arg(id)
+RETURNING reaction_counts;
@@ -0,0 +1,50 @@
+-- name: ListRedirects :many
+SELECT * FROM redirects
+ORDER BY source;
+
+-- name: GetRedirectByID :one
+SELECT * FROM redirects
+WHERE id = $1;
+
+-- name: CreateRedirect :one
+INSERT INTO redirects (source, target, status_code)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: UpdateRedirect :one
+UPDATE redirects
+SET source = $2,
+    target = $3,
+    status_code = $4,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteRedirect :execrows
+DELETE FROM redirects
+WHERE id = $1;
+
+-- name: IncrementRedirectHits :exec
+UPDATE redirects
+SET hit_count = hit_count + 1,
+    last_hit_at = NOW()
+WHERE id = $1;
+
+-- name: RecordNotFound :exec
+-- A new path is only added while fewer than max_paths are tracked.
+INSERT INTO not_found_paths (path, hits, last_referrer)
+SELECT $1, 1, $2
+WHERE EXISTS (SELECT 1 FROM not_found_paths WHERE path = $1)
+   OR (SELECT COUNT(*) FROM not_found_paths) < sqlc.arg(max_paths)::bigint
+ON CONFLICT (path) DO UPDATE
+SET hits = not_found_paths.hits + 1,
+    last_referrer = COALESCE(EXCLUDED.last_referrer, not_found_paths.last_referrer),
+    last_seen_at = NOW();
+
+-- name: DeleteNotFoundPath :execrows
+DELETE FROM not_found_paths
+WHERE path = $1;
+
+-- name: DeleteNotFoundPathsSeenBefore :execrows
+DELETE FROM not_found_paths
+WHERE last_seen_at < $1;
@@ -0,0 +1,32 @@
+-- name: ListRelatedPostCandidates :many
+SELECT id, slug, title, summary, tags,
+    tsvector_to_array(to_tsvector('english', title || ' ' || COALESCE(summary, '') || ' ' || content))::text[] AS terms
+FROM posts
+WHERE is_draft = FALSE;
+
+-- name: ListRelatedProjectCandidates :many
+SELECT id, slug, title, summary, tags,
+    tsvector_to_array(to_tsvector('english',
+        title || ' ' || COALESCE(summary, '') || ' ' || COALESCE(description, '') || ' ' || COALESCE(content, '')
+    ))::text[] AS terms
+FROM projects
+WHERE status <> 'draft';
+
+-- name: ListSessionPageViews :many
+SELECT DISTINCT session_id, path FROM page_views
+WHERE session_id IS NOT NULL AND viewed_at >= $1;
+
+-- name: ListRelatedOverrides :many
+SELECT * FROM related_overrides
+ORDER BY id;
+
+-- name: CreateRelatedOverride :one
+INSERT INTO related_overrides (source_type, source_id, target_type, target_id, action)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (source_type, source_id, target_type, target_id) DO UPDATE
+SET action = EXCLUDED.action
+RETURNING *;
+
+-- name: DeleteRelatedOverride :execrows
+DELETE FROM related_overrides
+WHERE id = $1;
@@ -0,0 +1,48 @@
+-- name: ListSeries :many
+SELECT * FROM series
+ORDER BY title;
+
+-- name: GetSeriesBySlug :one
+SELECT * FROM series
+WHERE slug = $1;
+
+-- name: GetSeriesByID :one
+SELECT * FROM series
+WHERE id = $1;
+
+-- name: CreateSeries :one
+INSERT INTO series (title, slug, description)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: UpdateSeries :one
+UPDATE series
+SET title = $2,
+    slug = $3,
+    description = $4,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSeries :execrows
+DELETE FROM series
+WHERE id = $1;
+
+-- name: ClearSeriesPosts :exec
+DELETE FROM series_posts
+WHERE series_id = $1;
+
+-- name: AddSeriesPost :exec
+INSERT INTO series_posts (series_id, post_id, position)
+VALUES ($1, $2, $3);
+
+-- name: ListSeriesPosts :many
+SELECT p.* FROM series_posts sp
+JOIN posts p ON p.id = sp.post_id
+WHERE sp.series_id = $1
+ORDER BY sp.position;
+
+-- name: GetSeriesByPost :one
+SELECT s.* FROM series s
+JOIN series_posts sp ON sp.series_id = s.id
+WHERE sp.post_id = $1;
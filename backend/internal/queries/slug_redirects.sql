@@ -0,0 +1,55 @@
+-- name: CreatePostSlugRedirect :exec
+INSERT INTO slug_redirects (old_slug, post_id)
+VALUES ($1, $2)
+ON CONFLICT (old_slug) WHERE post_id IS NOT NULL DO UPDATE
+SET post_id = EXCLUDED.post_id,
+    created_at = NOW();
+
+-- name: DeletePostSlugRedirect :exec
+DELETE FROM slug_redirects
+WHERE old_slug = $1 AND post_id IS NOT NULL;
+
+-- name: ResolvePostSlugRedirect :one
+SELECT p.slug FROM slug_redirects r
+JOIN posts p ON p.id = r.post_id
+WHERE r.old_slug = $1;
+
+-- name: PostSlugTaken :one
+SELECT EXISTS (
+    SELECT 1 FROM posts WHERE slug = $1
+    UNION ALL
+    SELECT 1 FROM slug_redirects WHERE old_slug = $1 AND post_id IS NOT NULL
+);
+
+-- name: ListPostSlugRedirects :many
+SELECT old_slug FROM slug_redirects
+WHERE post_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: CreateProjectSlugRedirect :exec
+INSERT INTO slug_redirects (old_slug, project_id)
+VALUES ($1, $2)
+ON CONFLICT (old_slug) WHERE project_id IS NOT NULL DO UPDATE
+SET project_id = EXCLUDED.project_id,
+    created_at = NOW();
+
+-- name: DeleteProjectSlugRedirect :exec
+DELETE FROM slug_redirects
+WHERE old_slug = $1 AND project_id IS NOT NULL;
+
+-- name: ResolveProjectSlugRedirect :one
+SELECT j.slug FROM slug_redirects r
+JOIN projects j ON j.id = r.project_id
+WHERE r.old_slug = $1;
+
+-- name: ProjectSlugTaken :one
+SELECT EXISTS (
+    SELECT 1 FROM projects WHERE slug = $1
+    UNION ALL
+    SELECT 1 FROM slug_redirects WHERE old_slug = $1 AND project_id IS NOT NULL
+);
+
+-- name: ListProjectSlugRedirects :many
+SELECT old_slug FROM slug_redirects
+WHERE project_id = $1
+ORDER BY created_at DESC, id DESC;
@@ -0,0 +1,11 @@
+-- name: ListTags :many
+SELECT t.tag::text AS tag,
+       COUNT(*) FILTER (WHERE t.kind = 'post')::int AS post_count,
+       COUNT(*) FILTER (WHERE t.kind = 'project')::int AS project_count
+FROM (
+    SELECT unnest(tags) AS tag, 'post' AS kind FROM posts WHERE is_draft = FALSE
+    UNION ALL
+    SELECT unnest(tags) AS tag, 'project' AS kind FROM projects WHERE status <> 'draft'
+) t
+GROUP BY t.tag
+ORDER BY t.tag;
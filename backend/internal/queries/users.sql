@@ -40,4 +40,8 @@ SET
   email = COALESCE(sqlc.narg('email'), email),
   updated_at = now()
 WHERE id = sqlc.arg('id')
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: GetUsersByIDs :many
+SELECT * FROM users
+WHERE id = ANY(sqlc.arg('ids')::int[]);
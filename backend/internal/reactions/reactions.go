@@ -0,0 +1,69 @@
+// Package reactions identifies anonymous visitors for per-visitor reaction
+// dedupe without storing anything that could be traced back to them.
+package reactions
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Reaction kinds.
+const (
+	KindLike  = "like"
+	KindFire  = "fire"
+	KindHeart = "heart"
+)
+
+// Kinds lists every valid kind.
+var Kinds = []string{KindLike, KindFire, KindHeart}
+
+// ValidKind reports whether k is a known reaction kind.
+func ValidKind(k string) bool {
+	for _, v := range Kinds {
+		if k == v {
+			return true
+		}
+	}
+	return false
+}
+
+// VisitorHash returns a salted SHA-256 identifying a visitor by IP address
+// and user agent. The analytics session ID, which the client chooses, only
+// tells apart visitors that share both, so it cannot stand in for another
+// visitor's.
+func VisitorHash(salt, sessionID, ip, userAgent string) string {
+	key := "ip:" + ip + "|ua:" + userAgent
+	if s := strings.TrimSpace(sessionID); s != "" {
+		key += "|session:" + s
+	}
+	sum := sha256.Sum256([]byte(salt + "|" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+var (
+	saltOnce sync.Once
+	salt     string
+)
+
+// Salt returns REACTION_SALT. When it is unset a random salt is generated,
+// which means reactions cannot be matched to visitors after a restart.
+func Salt() string {
+	saltOnce.Do(func() {
+		salt = os.Getenv("REACTION_SALT")
+		if salt != "" {
+			return
+		}
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			log.Fatalf("reactions: generate salt: %v", err)
+		}
+		salt = hex.EncodeToString(b)
+		log.Println("⚠️ REACTION_SALT is not set; using a random salt until restart")
+	})
+	return salt
+}
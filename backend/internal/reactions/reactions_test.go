@@ -0,0 +1,57 @@
+package reactions
+
+import "testing"
+
+func TestValidKind(t *testing.T) {
+	tests := []struct {
+		kind string
+		want bool
+	}{
+		{"like", true},
+		{"fire", true},
+		{"heart", true},
+		{"Like", false},
+		{"", false},
+		{"dislike", false},
+	}
+	for _, tt := range tests {
+		if got := ValidKind(tt.kind); got != tt.want {
+			t.Errorf("ValidKind(%q) = %v, want %v", tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestVisitorHash(t *testing.T) {
+	base := VisitorHash("salt", "", "203.0.113.7", "Firefox")
+
+	tests := []struct {
+		name                  string
+		salt, session, ip, ua string
+		same                  bool
+	}{
+		{"same inputs", "salt", "", "203.0.113.7", "Firefox", true},
+		{"different ip", "salt", "", "203.0.113.8", "Firefox", false},
+		{"different user agent", "salt", "", "203.0.113.7", "Chrome", false},
+		{"different salt", "pepper", "", "203.0.113.7", "Firefox", false},
+		{"session id splits a shared network", "salt", "abc", "203.0.113.7", "Firefox", false},
+	}
+	for _, tt := range tests {
+		got := VisitorHash(tt.salt, tt.session, tt.ip, tt.ua)
+		if (got == base) != tt.same {
+			t.Errorf("%s: hash equal = %v, want %v", tt.name, got == base, tt.same)
+		}
+		if len(got) != 64 {
+			t.Errorf("%s: hash length = %d, want 64", tt.name, len(got))
+		}
+	}
+
+	// A session ID does not identify the visitor on its own: replaying
+	// someone else's from another network yields another visitor
+	a := VisitorHash("salt", "abc", "203.0.113.7", "Firefox")
+	if b := VisitorHash("salt", " abc ", "203.0.113.7", "Firefox"); a != b {
+		t.Errorf("session hash depends on surrounding spaces: %s != %s", a, b)
+	}
+	if b := VisitorHash("salt", "abc", "198.51.100.1", "Chrome"); a == b {
+		t.Errorf("session hash is the same across IP/UA: %s", a)
+	}
+}
@@ -0,0 +1,231 @@
+// Package redirects matches request paths against admin-managed redirect
+// rules. Rules are cached in memory and reloaded after changes or a TTL.
+package redirects
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Splat is replaced in a target by the part of the path matched by a
+// trailing /* in the source.
+const Splat = ":splat"
+
+// Rule is one redirect.
+type Rule struct {
+	ID         int32
+	Source     string
+	Target     string
+	StatusCode int
+}
+
+// ValidStatus reports whether code is a redirect status the rules may use.
+func ValidStatus(code int) bool {
+	switch code {
+	case 301, 302, 307, 308:
+		return true
+	}
+	return false
+}
+
+// Validate checks a rule before it is stored.
+func Validate(rule Rule) error {
+	if !strings.HasPrefix(rule.Source, "/") {
+		return errors.New("source must be a path starting with /")
+	}
+	if strings.ContainsAny(rule.Source, "?#") {
+		return errors.New("source must not contain a query or fragment")
+	}
+	if i := strings.Index(rule.Source, "*"); i >= 0 && (i != len(rule.Source)-1 || !strings.HasSuffix(rule.Source, "/*")) {
+		return errors.New("a wildcard is only allowed as a trailing /*")
+	}
+	if rule.Target == "" {
+		return errors.New("target is required")
+	}
+	if !strings.HasPrefix(rule.Target, "/") {
+		u, err := url.Parse(rule.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("target must be a path or an http(s) URL")
+		}
+	}
+	if strings.Contains(rule.Target, Splat) && !strings.HasSuffix(rule.Source, "/*") {
+		return errors.New(Splat + " needs a source ending in /*")
+	}
+	if normalize(rule.Source) == normalize(rule.Target) {
+		return errors.New("target must differ from source")
+	}
+	if !ValidStatus(rule.StatusCode) {
+		return errors.New("status_code must be one of 301, 302, 307, 308")
+	}
+	return nil
+}
+
+// maxHops is how many redirects CheckLoop follows before giving up on a
+// chain.
+const maxHops = 10
+
+// CheckLoop reports whether adding rule to rules, replacing the rule with the
+// same ID, would make a redirect loop or a chain longer than maxHops. The
+// chain is followed from the rule's source; external targets end it.
+func CheckLoop(rules []Rule, rule Rule) error {
+	set := make([]Rule, 0, len(rules)+1)
+	for _, r := range rules {
+		if rule.ID == 0 || r.ID != rule.ID {
+			set = append(set, r)
+		}
+	}
+	table := NewTable(append(set, rule))
+
+	path := normalize(strings.TrimSuffix(rule.Source, "/*"))
+	chain := []string{path}
+	seen := map[string]bool{path: true}
+	for hop := 0; hop < maxHops; hop++ {
+		_, location, ok := table.Match(path, "")
+		if !ok || !strings.HasPrefix(location, "/") {
+			return nil
+		}
+		if i := strings.Index(location, "?"); i >= 0 {
+			location = location[:i]
+		}
+		path = normalize(location)
+		chain = append(chain, path)
+		if seen[path] {
+			return errors.New("redirect loop: " + strings.Join(chain, " -> "))
+		}
+		seen[path] = true
+	}
+	return errors.New("redirect chain is longer than " + strconv.Itoa(maxHops) + " hops: " + strings.Join(chain, " -> "))
+}
+
+// Table is an immutable set of rules indexed for lookup.
+type Table struct {
+	exact    map[string]Rule
+	prefixes []Rule // longest source first
+}
+
+// NewTable indexes rules. Exact sources win over wildcards; among wildcards
+// the longest source wins.
+func NewTable(rules []Rule) *Table {
+	t := &Table{exact: map[string]Rule{}}
+	for _, r := range rules {
+		if strings.HasSuffix(r.Source, "/*") {
+			t.prefixes = append(t.prefixes, r)
+		} else {
+			t.exact[normalize(r.Source)] = r
+		}
+	}
+	sort.SliceStable(t.prefixes, func(i, j int) bool {
+		return len(t.prefixes[i].Source) > len(t.prefixes[j].Source)
+	})
+	return t
+}
+
+// Match returns the rule for path and the location to redirect to. The
+// query string, if any, is carried over unless the target sets its own.
+func (t *Table) Match(path, rawQuery string) (Rule, string, bool) {
+	p := normalize(path)
+	if r, ok := t.exact[p]; ok {
+		return r, withQuery(r.Target, rawQuery), true
+	}
+	for _, r := range t.prefixes {
+		prefix := strings.TrimSuffix(r.Source, "*") // keeps the slash
+		base := strings.TrimSuffix(prefix, "/")
+		var rest string
+		switch {
+		case strings.HasPrefix(p, prefix):
+			rest = strings.TrimPrefix(p, prefix)
+		case p == base:
+			rest = ""
+		default:
+			continue
+		}
+		target := r.Target
+		if rest == "" {
+			target = strings.ReplaceAll(target, "/"+Splat, "")
+		}
+		target = strings.ReplaceAll(target, Splat, rest)
+		return r, withQuery(target, rawQuery), true
+	}
+	return Rule{}, "", false
+}
+
+// Len returns the number of rules.
+func (t *Table) Len() int {
+	return len(t.exact) + len(t.prefixes)
+}
+
+func normalize(path string) string {
+	if len(path) > 1 {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+func withQuery(target, rawQuery string) string {
+	if rawQuery == "" || strings.Contains(target, "?") {
+		return target
+	}
+	return target + "?" + rawQuery
+}
+
+// Matcher serves lookups from a cached Table, reloading it through load
+// when it is older than ttl or has been invalidated.
+type Matcher struct {
+	load func(ctx context.Context) ([]Rule, error)
+	ttl  time.Duration
+
+	mu       sync.RWMutex
+	table    *Table
+	loadedAt time.Time
+}
+
+// NewMatcher creates a Matcher; nothing is loaded until the first lookup.
+func NewMatcher(load func(ctx context.Context) ([]Rule, error), ttl time.Duration) *Matcher {
+	return &Matcher{load: load, ttl: ttl}
+}
+
+// Match looks path up in the current table. If reloading fails the previous
+// table keeps serving.
+func (m *Matcher) Match(ctx context.Context, path, rawQuery string) (Rule, string, bool) {
+	return m.current(ctx).Match(path, rawQuery)
+}
+
+// Invalidate forces a reload on the next lookup.
+func (m *Matcher) Invalidate() {
+	m.mu.Lock()
+	m.loadedAt = time.Time{}
+	m.mu.Unlock()
+}
+
+func (m *Matcher) current(ctx context.Context) *Table {
+	m.mu.RLock()
+	table, fresh := m.table, m.table != nil && time.Since(m.loadedAt) < m.ttl
+	m.mu.RUnlock()
+	if fresh {
+		return table
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.table != nil && time.Since(m.loadedAt) < m.ttl {
+		return m.table // reloaded by another request meanwhile
+	}
+	rules, err := m.load(ctx)
+	m.loadedAt = time.Now() // on failure, retry after another ttl
+	if err != nil {
+		log.Printf("redirects: reload failed: %v", err)
+		if m.table == nil {
+			m.table = NewTable(nil)
+		}
+		return m.table
+	}
+	m.table = NewTable(rules)
+	return m.table
+}
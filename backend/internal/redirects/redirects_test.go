@@ -0,0 +1,138 @@
+package redirects
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{"exact path", Rule{Source: "/old", Target: "/new", StatusCode: 301}, false},
+		{"wildcard with splat", Rule{Source: "/blog/*", Target: "/posts/:splat", StatusCode: 308}, false},
+		{"external target", Rule{Source: "/gh", Target: "https://github.com/onnwee", StatusCode: 302}, false},
+		{"relative source", Rule{Source: "old", Target: "/new", StatusCode: 301}, true},
+		{"query in source", Rule{Source: "/old?x=1", Target: "/new", StatusCode: 301}, true},
+		{"inner wildcard", Rule{Source: "/a/*/b", Target: "/new", StatusCode: 301}, true},
+		{"bare wildcard suffix", Rule{Source: "/a*", Target: "/new", StatusCode: 301}, true},
+		{"splat without wildcard", Rule{Source: "/old", Target: "/new/:splat", StatusCode: 301}, true},
+		{"non-http target", Rule{Source: "/old", Target: "javascript:alert(1)", StatusCode: 301}, true},
+		{"self redirect", Rule{Source: "/same/", Target: "/same", StatusCode: 301}, true},
+		{"bad status", Rule{Source: "/old", Target: "/new", StatusCode: 303}, true},
+		{"missing target", Rule{Source: "/old", StatusCode: 301}, true},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.rule)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckLoop(t *testing.T) {
+	rules := []Rule{
+		{ID: 1, Source: "/a", Target: "/b", StatusCode: 301},
+		{ID: 2, Source: "/c", Target: "https://example.com/c", StatusCode: 301},
+		{ID: 3, Source: "/old/*", Target: "/new/:splat", StatusCode: 301},
+	}
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{"chain", Rule{Source: "/start", Target: "/a", StatusCode: 301}, false},
+		{"two-rule loop", Rule{Source: "/b", Target: "/a", StatusCode: 301}, true},
+		{"loop through query", Rule{Source: "/b", Target: "/a?from=b", StatusCode: 301}, true},
+		{"loop through wildcard", Rule{Source: "/new/*", Target: "/old/:splat", StatusCode: 301}, true},
+		{"growing wildcard", Rule{Source: "/x/*", Target: "/x/y/:splat", StatusCode: 301}, true},
+		{"external target ends chain", Rule{Source: "/d", Target: "/c", StatusCode: 301}, false},
+		{"update replaces old rule", Rule{ID: 1, Source: "/a", Target: "/e", StatusCode: 301}, false},
+		{"update closes loop", Rule{ID: 2, Source: "/b", Target: "/a", StatusCode: 301}, true},
+	}
+	for _, tt := range tests {
+		err := CheckLoop(rules, tt.rule)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: CheckLoop() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTableMatch(t *testing.T) {
+	table := NewTable([]Rule{
+		{ID: 1, Source: "/old", Target: "/new", StatusCode: 301},
+		{ID: 2, Source: "/blog/*", Target: "/posts/:splat", StatusCode: 308},
+		{ID: 3, Source: "/blog/archive/*", Target: "/archive", StatusCode: 302},
+		{ID: 4, Source: "/blog/special", Target: "/posts/special-post", StatusCode: 301},
+		{ID: 5, Source: "/search", Target: "/posts?sort=-created_at", StatusCode: 302},
+	})
+
+	tests := []struct {
+		path, query string
+		wantID      int32
+		wantLoc     string
+	}{
+		{"/old", "", 1, "/new"},
+		{"/old/", "", 1, "/new"},
+		{"/old", "utm=x", 1, "/new?utm=x"},
+		{"/blog/hello-world", "", 2, "/posts/hello-world"},
+		{"/blog/2020/05/post", "", 2, "/posts/2020/05/post"},
+		{"/blog", "", 2, "/posts"},
+		{"/blog/archive/2019", "", 3, "/archive"},
+		{"/blog/special", "", 4, "/posts/special-post"},
+		{"/search", "q=go", 5, "/posts?sort=-created_at"},
+		{"/older", "", 0, ""},
+		{"/blogger", "", 0, ""},
+	}
+	for _, tt := range tests {
+		rule, loc, ok := table.Match(tt.path, tt.query)
+		if tt.wantID == 0 {
+			if ok {
+				t.Errorf("Match(%q) = rule %d, want no match", tt.path, rule.ID)
+			}
+			continue
+		}
+		if !ok || rule.ID != tt.wantID || loc != tt.wantLoc {
+			t.Errorf("Match(%q, %q) = (%d, %q, %v), want (%d, %q, true)", tt.path, tt.query, rule.ID, loc, ok, tt.wantID, tt.wantLoc)
+		}
+	}
+}
+
+func TestMatcherCaching(t *testing.T) {
+	loads := 0
+	var fail bool
+	rules := []Rule{{ID: 1, Source: "/a", Target: "/b", StatusCode: 301}}
+	m := NewMatcher(func(context.Context) ([]Rule, error) {
+		loads++
+		if fail {
+			return nil, errors.New("db down")
+		}
+		return rules, nil
+	}, time.Hour)
+	ctx := context.Background()
+
+	if _, _, ok := m.Match(ctx, "/a", ""); !ok {
+		t.Fatal("expected /a to match")
+	}
+	m.Match(ctx, "/a", "")
+	if loads != 1 {
+		t.Errorf("loads = %d, want 1 (cached)", loads)
+	}
+
+	rules = []Rule{{ID: 2, Source: "/c", Target: "/d", StatusCode: 301}}
+	m.Invalidate()
+	if _, _, ok := m.Match(ctx, "/c", ""); !ok || loads != 2 {
+		t.Errorf("after Invalidate: match /c = %v, loads = %d, want true, 2", ok, loads)
+	}
+
+	// A failed reload keeps serving the previous rules
+	fail = true
+	m.Invalidate()
+	if _, _, ok := m.Match(ctx, "/c", ""); !ok {
+		t.Error("expected previous rules to survive a failed reload")
+	}
+}
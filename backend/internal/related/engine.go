@@ -0,0 +1,71 @@
+package related
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MaxLimit caps how many results Engine.Related returns.
+const MaxLimit = 20
+
+// Engine caches an Index and the results computed from it. The index is
+// rebuilt through load after Invalidate (content or overrides changed) or
+// once it is older than ttl (co-visits drift slowly).
+type Engine struct {
+	load    func(ctx context.Context) (Corpus, error)
+	ttl     time.Duration
+	weights Weights
+
+	mu      sync.Mutex
+	index   *Index
+	builtAt time.Time
+	results map[Key][]Result
+}
+
+// NewEngine creates an Engine; nothing is loaded until the first lookup.
+func NewEngine(load func(ctx context.Context) (Corpus, error), ttl time.Duration) *Engine {
+	return &Engine{load: load, ttl: ttl, weights: DefaultWeights}
+}
+
+// Invalidate drops the cached index and results.
+func (e *Engine) Invalidate() {
+	e.mu.Lock()
+	e.index = nil
+	e.results = nil
+	e.mu.Unlock()
+}
+
+// Related returns up to limit recommendations for the item typ/slug. found is
+// false when no such published item exists.
+func (e *Engine) Related(ctx context.Context, typ, slug string, limit int) (results []Result, found bool, err error) {
+	if limit <= 0 || limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.index == nil || time.Since(e.builtAt) >= e.ttl {
+		corpus, err := e.load(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		e.index = Build(corpus, e.weights)
+		e.builtAt = time.Now()
+		e.results = map[Key][]Result{}
+	}
+
+	k, ok := e.index.Lookup(typ, slug)
+	if !ok {
+		return nil, false, nil
+	}
+	all, ok := e.results[k]
+	if !ok {
+		all = e.index.Related(k, MaxLimit)
+		e.results[k] = all
+	}
+	if len(all) > limit {
+		all = all[:limit]
+	}
+	return all, true, nil
+}
@@ -0,0 +1,265 @@
+// Package related recommends posts and projects for "you may also like"
+// blocks. Candidates are scored by tag overlap, shared full-text terms and
+// co-visitation within analytics sessions; admins can pin or exclude
+// specific relations on top of the scores.
+package related
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Item types.
+const (
+	TypePost    = "post"
+	TypeProject = "project"
+)
+
+// Override actions.
+const (
+	ActionPin     = "pin"
+	ActionExclude = "exclude"
+)
+
+// ValidType reports whether t is a known item type.
+func ValidType(t string) bool {
+	return t == TypePost || t == TypeProject
+}
+
+// ValidAction reports whether a is a known override action.
+func ValidAction(a string) bool {
+	return a == ActionPin || a == ActionExclude
+}
+
+// Key identifies a post or project.
+type Key struct {
+	Type string
+	ID   int32
+}
+
+// Item is a candidate for recommendation.
+type Item struct {
+	Key
+	Slug    string
+	Title   string
+	Summary string
+	Tags    []string
+	// Terms are the lexemes of the item's tsvector.
+	Terms []string
+}
+
+// Override pins Target to the top of Source's list, or excludes it.
+type Override struct {
+	ID     int32
+	Source Key
+	Target Key
+	Action string
+}
+
+// Corpus is everything an Index is built from.
+type Corpus struct {
+	Items []Item
+	// Sessions maps an analytics session ID to the paths it viewed.
+	Sessions  map[string][]string
+	Overrides []Override
+}
+
+// Weights balances the three signals; they should sum to 1.
+type Weights struct {
+	Tags     float64
+	Terms    float64
+	CoVisits float64
+}
+
+// DefaultWeights favours explicit tags over inferred signals.
+var DefaultWeights = Weights{Tags: 0.5, Terms: 0.3, CoVisits: 0.2}
+
+// Result is one recommendation.
+type Result struct {
+	Type    string  `json:"type"`
+	Slug    string  `json:"slug"`
+	Title   string  `json:"title"`
+	Summary string  `json:"summary,omitempty"`
+	Score   float64 `json:"score"`
+	Pinned  bool    `json:"pinned"`
+}
+
+// Index holds a scored snapshot of the corpus.
+type Index struct {
+	weights   Weights
+	items     []Item
+	byKey     map[Key]int
+	slugs     map[string]Key
+	coVisits  map[Key]map[Key]int
+	overrides map[Key][]Override
+}
+
+func slugKey(typ, slug string) string {
+	return typ + ":" + slug
+}
+
+// Build indexes a corpus.
+func Build(c Corpus, w Weights) *Index {
+	ix := &Index{
+		weights:   w,
+		items:     c.Items,
+		byKey:     make(map[Key]int, len(c.Items)),
+		slugs:     make(map[string]Key, len(c.Items)),
+		coVisits:  map[Key]map[Key]int{},
+		overrides: map[Key][]Override{},
+	}
+	for i, it := range c.Items {
+		ix.byKey[it.Key] = i
+		ix.slugs[slugKey(it.Type, it.Slug)] = it.Key
+	}
+
+	for _, paths := range c.Sessions {
+		seen := map[Key]bool{}
+		for _, p := range paths {
+			typ, slug, ok := ParsePath(p)
+			if !ok {
+				continue
+			}
+			if k, ok := ix.slugs[slugKey(typ, slug)]; ok {
+				seen[k] = true
+			}
+		}
+		for a := range seen {
+			for b := range seen {
+				if a == b {
+					continue
+				}
+				if ix.coVisits[a] == nil {
+					ix.coVisits[a] = map[Key]int{}
+				}
+				ix.coVisits[a][b]++
+			}
+		}
+	}
+
+	sorted := append([]Override{}, c.Overrides...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	for _, o := range sorted {
+		ix.overrides[o.Source] = append(ix.overrides[o.Source], o)
+	}
+	return ix
+}
+
+// Lookup finds an item by type and slug.
+func (ix *Index) Lookup(typ, slug string) (Key, bool) {
+	k, ok := ix.slugs[slugKey(typ, slug)]
+	return k, ok
+}
+
+// Related returns up to limit recommendations for k: pinned items first, in
+// the order they were pinned, then the best scoring candidates.
+func (ix *Index) Related(k Key, limit int) []Result {
+	out := []Result{}
+	if _, ok := ix.byKey[k]; !ok || limit <= 0 {
+		return out
+	}
+
+	skip := map[Key]bool{k: true}
+	for _, o := range ix.overrides[k] {
+		if o.Action == ActionExclude {
+			skip[o.Target] = true
+		}
+	}
+	for _, o := range ix.overrides[k] {
+		i, ok := ix.byKey[o.Target]
+		if o.Action != ActionPin || !ok || skip[o.Target] {
+			continue
+		}
+		skip[o.Target] = true
+		r := toResult(ix.items[i], ix.score(k, o.Target))
+		r.Pinned = true
+		out = append(out, r)
+	}
+
+	var scored []Result
+	for _, it := range ix.items {
+		if skip[it.Key] {
+			continue
+		}
+		if sc := ix.score(k, it.Key); sc > 0 {
+			scored = append(scored, toResult(it, sc))
+		}
+	}
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		if scored[i].Type != scored[j].Type {
+			return scored[i].Type < scored[j].Type
+		}
+		return scored[i].Slug < scored[j].Slug
+	})
+	out = append(out, scored...)
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func (ix *Index) score(a, b Key) float64 {
+	ia, ib := ix.items[ix.byKey[a]], ix.items[ix.byKey[b]]
+	s := ix.weights.Tags*Jaccard(ia.Tags, ib.Tags) + ix.weights.Terms*Jaccard(ia.Terms, ib.Terms)
+	if co := ix.coVisits[a]; len(co) > 0 {
+		max := 0
+		for _, n := range co {
+			if n > max {
+				max = n
+			}
+		}
+		s += ix.weights.CoVisits * float64(co[b]) / float64(max)
+	}
+	return math.Round(s*1e4) / 1e4
+}
+
+func toResult(it Item, score float64) Result {
+	return Result{Type: it.Type, Slug: it.Slug, Title: it.Title, Summary: it.Summary, Score: score}
+}
+
+// Jaccard is |a ∩ b| / |a ∪ b| over case-insensitive sets; 0 when both are empty.
+func Jaccard(a, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[strings.ToLower(s)] = true
+	}
+	inter, union := 0, len(set)
+	seen := map[string]bool{}
+	for _, s := range b {
+		s = strings.ToLower(s)
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		if set[s] {
+			inter++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
+
+// ParsePath maps a viewed path to an item: /posts/{slug} and /blog/{slug}
+// are posts, /projects/{slug} is a project. An /api prefix is ignored.
+func ParsePath(path string) (typ, slug string, ok bool) {
+	path = strings.TrimPrefix(path, "/api")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", false
+	}
+	switch parts[0] {
+	case "posts", "blog":
+		return TypePost, parts[1], true
+	case "projects":
+		return TypeProject, parts[1], true
+	}
+	return "", "", false
+}
@@ -0,0 +1,145 @@
+package related
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want float64
+	}{
+		{[]string{"go", "web"}, []string{"go", "web"}, 1},
+		{[]string{"go", "web"}, []string{"Go", "rust"}, 1.0 / 3},
+		{[]string{"go"}, []string{"rust"}, 0},
+		{nil, nil, 0},
+		{[]string{"go", "go"}, []string{"go"}, 1},
+	}
+	for _, tt := range tests {
+		if got := Jaccard(tt.a, tt.b); got != tt.want {
+			t.Errorf("Jaccard(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path      string
+		typ, slug string
+		ok        bool
+	}{
+		{"/posts/hello", TypePost, "hello", true},
+		{"/blog/hello/", TypePost, "hello", true},
+		{"/api/projects/site", TypeProject, "site", true},
+		{"/posts", "", "", false},
+		{"/posts/hello/comments", "", "", false},
+		{"/tags/go", "", "", false},
+	}
+	for _, tt := range tests {
+		typ, slug, ok := ParsePath(tt.path)
+		if typ != tt.typ || slug != tt.slug || ok != tt.ok {
+			t.Errorf("ParsePath(%q) = (%q, %q, %v), want (%q, %q, %v)", tt.path, typ, slug, ok, tt.typ, tt.slug, tt.ok)
+		}
+	}
+}
+
+func corpus() Corpus {
+	post := func(id int32, slug string, tags, terms []string) Item {
+		return Item{Key: Key{TypePost, id}, Slug: slug, Title: slug, Tags: tags, Terms: terms}
+	}
+	return Corpus{
+		Items: []Item{
+			post(1, "go-intro", []string{"go", "beginner"}, []string{"go", "program", "intro"}),
+			post(2, "go-advanced", []string{"go"}, []string{"go", "program", "generic"}),
+			post(3, "cooking", []string{"food"}, []string{"pasta"}),
+			post(4, "unrelated", []string{"music"}, []string{"guitar"}),
+			{Key: Key{TypeProject, 1}, Slug: "go-tool", Title: "go-tool", Tags: []string{"go", "cli"}},
+		},
+		Sessions: map[string][]string{
+			"s1": {"/posts/go-intro", "/posts/cooking"},
+			"s2": {"/blog/go-intro", "/posts/cooking", "/posts/cooking"},
+			"s3": {"/posts/unknown", "/posts/go-intro"},
+		},
+	}
+}
+
+func slugs(rs []Result) []string {
+	out := make([]string, len(rs))
+	for i, r := range rs {
+		out[i] = r.Slug
+	}
+	return out
+}
+
+func TestIndexRelated(t *testing.T) {
+	ix := Build(corpus(), DefaultWeights)
+	k, ok := ix.Lookup(TypePost, "go-intro")
+	if !ok {
+		t.Fatal("Lookup(go-intro) failed")
+	}
+
+	got := slugs(ix.Related(k, 10))
+	want := []string{"go-advanced", "cooking", "go-tool"}
+	if len(got) != len(want) {
+		t.Fatalf("Related = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Related = %v, want %v", got, want)
+		}
+	}
+
+	if got := ix.Related(k, 1); len(got) != 1 || got[0].Slug != "go-advanced" {
+		t.Errorf("Related limit 1 = %v", slugs(got))
+	}
+	if got := ix.Related(Key{TypePost, 99}, 5); len(got) != 0 {
+		t.Errorf("Related(unknown) = %v, want empty", slugs(got))
+	}
+}
+
+func TestIndexOverrides(t *testing.T) {
+	c := corpus()
+	src := Key{TypePost, 1}
+	c.Overrides = []Override{
+		{ID: 2, Source: src, Target: Key{TypePost, 4}, Action: ActionPin},
+		{ID: 1, Source: src, Target: Key{TypePost, 2}, Action: ActionExclude},
+		{ID: 3, Source: src, Target: Key{TypePost, 2}, Action: ActionPin},  // excluded wins
+		{ID: 4, Source: src, Target: Key{TypePost, 42}, Action: ActionPin}, // gone
+	}
+	got := Build(c, DefaultWeights).Related(src, 10)
+	if s := slugs(got); len(s) != 3 || s[0] != "unrelated" || s[1] != "cooking" || s[2] != "go-tool" {
+		t.Fatalf("Related = %v, want [unrelated cooking go-tool]", s)
+	}
+	if !got[0].Pinned || got[1].Pinned {
+		t.Errorf("Pinned flags = %v, %v, want true, false", got[0].Pinned, got[1].Pinned)
+	}
+}
+
+func TestEngineCaching(t *testing.T) {
+	loads := 0
+	var fail error
+	e := NewEngine(func(context.Context) (Corpus, error) {
+		loads++
+		return corpus(), fail
+	}, time.Hour)
+	ctx := context.Background()
+
+	if _, found, err := e.Related(ctx, TypePost, "go-intro", 3); err != nil || !found {
+		t.Fatalf("Related = found %v, err %v", found, err)
+	}
+	if _, found, _ := e.Related(ctx, TypeProject, "go-intro", 3); found {
+		t.Error("project go-intro should not exist")
+	}
+	if loads != 1 {
+		t.Errorf("loads = %d, want 1", loads)
+	}
+
+	e.Invalidate()
+	fail = errors.New("db down")
+	if _, _, err := e.Related(ctx, TypePost, "go-intro", 3); err == nil {
+		t.Error("expected load error after Invalidate")
+	}
+}
@@ -0,0 +1,254 @@
+// Package respcache caches rendered GET responses in memory. Entries are
+// bounded by total size and age, and are dropped by tag when the content
+// they were built from changes.
+package respcache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/httpcache"
+	"github.com/onnwee/onnwee.github.io/backend/internal/metrics"
+)
+
+// Cache is an LRU of responses. A nil *Cache is valid and caches nothing.
+type Cache struct {
+	maxBytes int64
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List // of *entry, most recently used first
+	entries map[string]*entry
+	byTag   map[string]map[*entry]struct{}
+	size    int64
+	// gen moves on every invalidation, so a response rendered while one
+	// happened is served but not stored.
+	gen uint64
+}
+
+type entry struct {
+	key     string
+	header  http.Header
+	body    []byte
+	tags    []string
+	expires time.Time
+	elem    *list.Element
+}
+
+func (e *entry) size() int64 { return int64(len(e.key) + len(e.body)) }
+
+// New creates a cache holding at most maxBytes of response bodies, each for
+// at most ttl.
+func New(maxBytes int64, ttl time.Duration) *Cache {
+	return &Cache{
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		now:      time.Now,
+		lru:      list.New(),
+		entries:  map[string]*entry{},
+		byTag:    map[string]map[*entry]struct{}{},
+	}
+}
+
+type tagsKey struct{}
+
+type tagSet struct {
+	mu   sync.Mutex
+	tags []string
+}
+
+// Tag records what the response being rendered for ctx depends on, e.g.
+// "post:12". It does nothing outside a cached request.
+func Tag(ctx context.Context, tags ...string) {
+	if ts, ok := ctx.Value(tagsKey{}).(*tagSet); ok {
+		ts.mu.Lock()
+		ts.tags = append(ts.tags, tags...)
+		ts.mu.Unlock()
+	}
+}
+
+// Invalidate drops every entry carrying one of tags.
+func (c *Cache) Invalidate(tags ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for _, tag := range tags {
+		for e := range c.byTag[tag] {
+			c.remove(e, "invalidated")
+		}
+	}
+	c.observe()
+}
+
+// Purge drops every entry.
+func (c *Cache) Purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for _, e := range c.entries {
+		c.remove(e, "invalidated")
+	}
+	c.observe()
+}
+
+// Middleware serves GET requests from the cache, storing 200 responses on a
+// miss. Other methods pass through. If-None-Match is answered from the
+// cached ETag; a request with only If-Modified-Since goes to the handler,
+// which knows whether its Last-Modified can be trusted for that.
+func (c *Cache) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c == nil || r.Method != http.MethodGet || r.Header.Get("Authorization") != "" ||
+			(r.Header.Get("If-None-Match") == "" && r.Header.Get("If-Modified-Since") != "") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := r.URL.RequestURI()
+		e, gen, ok := c.get(key)
+		if ok {
+			metrics.IncrementResponseCacheHit()
+			c.write(w, r, e, "HIT")
+			return
+		}
+		metrics.IncrementResponseCacheMiss()
+
+		ts := &tagSet{}
+		inner := r.Clone(context.WithValue(r.Context(), tagsKey{}, ts))
+		inner.Header.Del("If-None-Match")
+		rec := &recorder{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(rec, inner)
+
+		if rec.status != http.StatusOK {
+			for k, v := range rec.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(rec.body.Bytes())
+			return
+		}
+		e = &entry{key: key, header: rec.header, body: rec.body.Bytes(), tags: ts.tags}
+		c.set(e, gen)
+		c.write(w, r, e, "MISS")
+	})
+}
+
+func (c *Cache) write(w http.ResponseWriter, r *http.Request, e *entry, status string) {
+	h := w.Header()
+	for k, v := range e.header {
+		h[k] = v
+	}
+	h.Set("X-Cache", status)
+	if inm := r.Header.Get("If-None-Match"); inm != "" && httpcache.MatchWeak(inm, e.header.Get("ETag")) {
+		h.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(e.body)
+}
+
+// get returns a live entry for key, and the generation a miss should be
+// stored under.
+func (c *Cache) get(key string) (*entry, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, c.gen, false
+	}
+	if !c.now().Before(e.expires) {
+		c.remove(e, "expired")
+		c.observe()
+		return nil, c.gen, false
+	}
+	c.lru.MoveToFront(e.elem)
+	return e, c.gen, true
+}
+
+// set stores e unless the cache was invalidated since gen or e alone would
+// not fit, evicting the least recently used entries to make room.
+func (c *Cache) set(e *entry, gen uint64) {
+	if e.size() > c.maxBytes {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen {
+		return
+	}
+	if old, ok := c.entries[e.key]; ok {
+		c.remove(old, "")
+	}
+	e.expires = c.now().Add(c.ttl)
+	e.elem = c.lru.PushFront(e)
+	c.entries[e.key] = e
+	for _, tag := range e.tags {
+		if c.byTag[tag] == nil {
+			c.byTag[tag] = map[*entry]struct{}{}
+		}
+		c.byTag[tag][e] = struct{}{}
+	}
+	c.size += e.size()
+	for c.size > c.maxBytes {
+		c.remove(c.lru.Back().Value.(*entry), "capacity")
+	}
+	c.observe()
+}
+
+// remove drops e, counting it as an eviction for a non-empty reason; c.mu
+// must be held.
+func (c *Cache) remove(e *entry, reason string) {
+	if c.entries[e.key] != e {
+		return
+	}
+	delete(c.entries, e.key)
+	c.lru.Remove(e.elem)
+	for _, tag := range e.tags {
+		delete(c.byTag[tag], e)
+		if len(c.byTag[tag]) == 0 {
+			delete(c.byTag, tag)
+		}
+	}
+	c.size -= e.size()
+	if reason != "" {
+		metrics.IncrementResponseCacheEviction(reason)
+	}
+}
+
+// observe publishes the cache size; c.mu must be held.
+func (c *Cache) observe() {
+	metrics.SetResponseCacheSize(len(c.entries), c.size)
+}
+
+// recorder buffers a response so it can be stored before it is sent.
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+	wrote  bool
+}
+
+func (r *recorder) Header() http.Header { return r.header }
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wrote {
+		r.status = status
+		r.wrote = true
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wrote = true
+	return r.body.Write(b)
+}
@@ -0,0 +1,174 @@
+package respcache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// counting returns a handler that renders its call count, tagged with tags.
+func counting(calls *int, tags ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		Tag(r.Context(), tags...)
+		w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, *calls))
+		fmt.Fprintf(w, "call %d", *calls)
+	})
+}
+
+func get(h http.Handler, target string, header ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddlewareHitAndInvalidate(t *testing.T) {
+	c := New(1<<20, time.Minute)
+	calls := 0
+	h := c.Middleware(counting(&calls, "post:1"))
+
+	if w := get(h, "/posts/a"); w.Body.String() != "call 1" || w.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("first request = %q (%s)", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+	if w := get(h, "/posts/a"); w.Body.String() != "call 1" || w.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("second request = %q (%s); want a hit", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+	if w := get(h, "/posts/a?fields=title"); w.Body.String() != "call 2" {
+		t.Errorf("a different query shared the entry: %q", w.Body.String())
+	}
+
+	c.Invalidate("post:2")
+	if w := get(h, "/posts/a"); w.Body.String() != "call 1" {
+		t.Errorf("an unrelated tag dropped the entry: %q", w.Body.String())
+	}
+	c.Invalidate("post:1")
+	if w := get(h, "/posts/a"); w.Body.String() != "call 3" {
+		t.Errorf("after invalidation = %q; want a fresh render", w.Body.String())
+	}
+}
+
+func TestMiddlewareConditional(t *testing.T) {
+	c := New(1<<20, time.Minute)
+	calls := 0
+	h := c.Middleware(counting(&calls))
+
+	// A miss still renders the full body before checking the client's tag
+	if w := get(h, "/p", "If-None-Match", `"v1"`); w.Code != http.StatusNotModified {
+		t.Errorf("matching If-None-Match on a miss = %d; want 304", w.Code)
+	}
+	if w := get(h, "/p", "If-None-Match", `"v1"`); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("matching If-None-Match on a hit = %d %q; want an empty 304", w.Code, w.Body.String())
+	}
+	if w := get(h, "/p", "If-None-Match", `"old"`); w.Code != http.StatusOK || w.Body.String() != "call 1" {
+		t.Errorf("stale If-None-Match = %d %q; want the cached body", w.Code, w.Body.String())
+	}
+	// If-Modified-Since alone is left to the handler
+	get(h, "/p", "If-Modified-Since", "Sat, 01 Mar 2025 12:00:00 GMT")
+	if calls != 2 {
+		t.Errorf("If-Modified-Since request was served from the cache")
+	}
+}
+
+func TestMiddlewareSkips(t *testing.T) {
+	c := New(1<<20, time.Minute)
+	calls := 0
+	h := c.Middleware(counting(&calls))
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest(http.MethodPost, "/p", nil)
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		get(h, "/p", "Authorization", "Bearer x")
+	}
+	if calls != 4 {
+		t.Errorf("calls = %d; want POST and authorized requests to bypass the cache", calls)
+	}
+
+	fails := 0
+	fail := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fails++
+		http.Error(w, "nope", http.StatusNotFound)
+	}))
+	get(fail, "/missing")
+	if w := get(fail, "/missing"); w.Code != http.StatusNotFound || fails != 2 {
+		t.Errorf("non-200 response was cached (code %d, %d calls)", w.Code, fails)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	c := New(1<<20, time.Minute)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	calls := 0
+	h := c.Middleware(counting(&calls))
+
+	get(h, "/p")
+	now = now.Add(59 * time.Second)
+	get(h, "/p")
+	now = now.Add(time.Second)
+	get(h, "/p")
+	if calls != 2 {
+		t.Errorf("calls = %d; want a re-render once the TTL passed", calls)
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	body := strings.Repeat("x", 100)
+	c := New(250, time.Minute)
+	calls := map[string]int{}
+	h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		_, _ = w.Write([]byte(body))
+	}))
+
+	get(h, "/a")
+	get(h, "/b")
+	get(h, "/a") // /a is now the most recently used
+	get(h, "/c") // evicts /b
+	get(h, "/a")
+	get(h, "/b")
+	if calls["/a"] != 1 || calls["/b"] != 2 {
+		t.Errorf("calls = %v; want /b evicted as least recently used", calls)
+	}
+	if c.size > c.maxBytes {
+		t.Errorf("size %d exceeds the %d byte bound", c.size, c.maxBytes)
+	}
+}
+
+func TestInvalidateDuringRender(t *testing.T) {
+	c := New(1<<20, time.Minute)
+	calls := 0
+	h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			// A write lands while this response is being built
+			c.Invalidate("post:1")
+		}
+		_, _ = w.Write([]byte("body"))
+	}))
+
+	get(h, "/p")
+	get(h, "/p")
+	if calls != 2 {
+		t.Error("a response rendered across an invalidation was stored")
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	var c *Cache
+	calls := 0
+	h := c.Middleware(counting(&calls))
+	get(h, "/p")
+	get(h, "/p")
+	c.Invalidate("x")
+	c.Purge()
+	if calls != 2 {
+		t.Errorf("nil cache stored a response")
+	}
+}
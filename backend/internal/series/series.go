@@ -0,0 +1,46 @@
+// Package series works out where a post sits in its series. Readers only
+// ever see published parts, so drafts are skipped when numbering and when
+// picking the previous and next posts.
+package series
+
+import "github.com/onnwee/onnwee.github.io/backend/internal/db"
+
+// Published filters posts (already in series order) down to published ones.
+func Published(posts []db.Post) []db.Post {
+	out := make([]db.Post, 0, len(posts))
+	for _, p := range posts {
+		if p.IsDraft.Valid && p.IsDraft.Bool {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// Nav is a post's place in its series.
+type Nav struct {
+	Position int // 1-based, among published posts
+	Total    int
+	Prev     *db.Post
+	Next     *db.Post
+}
+
+// Navigate locates postID in posts (in series order). ok is false when the
+// post is not a published member.
+func Navigate(posts []db.Post, postID int32) (nav Nav, ok bool) {
+	published := Published(posts)
+	for i := range published {
+		if published[i].ID != postID {
+			continue
+		}
+		nav = Nav{Position: i + 1, Total: len(published)}
+		if i > 0 {
+			nav.Prev = &published[i-1]
+		}
+		if i < len(published)-1 {
+			nav.Next = &published[i+1]
+		}
+		return nav, true
+	}
+	return Nav{}, false
+}
@@ -0,0 +1,60 @@
+package series
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+)
+
+func post(id int32, draft bool) db.Post {
+	return db.Post{ID: id, IsDraft: sql.NullBool{Bool: draft, Valid: true}}
+}
+
+func TestNavigate(t *testing.T) {
+	// 3 is a draft and must be invisible to readers
+	posts := []db.Post{post(1, false), post(2, false), post(3, true), post(4, false)}
+
+	tests := []struct {
+		id              int32
+		ok              bool
+		position, total int
+		prevID, nextID  int32
+	}{
+		{1, true, 1, 3, 0, 2},
+		{2, true, 2, 3, 1, 4},
+		{4, true, 3, 3, 2, 0},
+		{3, false, 0, 0, 0, 0},
+		{9, false, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		nav, ok := Navigate(posts, tt.id)
+		if ok != tt.ok {
+			t.Errorf("Navigate(%d) ok = %v, want %v", tt.id, ok, tt.ok)
+			continue
+		}
+		if nav.Position != tt.position || nav.Total != tt.total {
+			t.Errorf("Navigate(%d) = %d/%d, want %d/%d", tt.id, nav.Position, nav.Total, tt.position, tt.total)
+		}
+		if got := idOf(nav.Prev); got != tt.prevID {
+			t.Errorf("Navigate(%d) prev = %d, want %d", tt.id, got, tt.prevID)
+		}
+		if got := idOf(nav.Next); got != tt.nextID {
+			t.Errorf("Navigate(%d) next = %d, want %d", tt.id, got, tt.nextID)
+		}
+	}
+}
+
+func TestPublishedKeepsOrder(t *testing.T) {
+	got := Published([]db.Post{post(5, false), post(2, true), post(1, false)})
+	if len(got) != 2 || got[0].ID != 5 || got[1].ID != 1 {
+		t.Errorf("Published() = %v, want ids [5 1]", got)
+	}
+}
+
+func idOf(p *db.Post) int32 {
+	if p == nil {
+		return 0
+	}
+	return p.ID
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/respcache"
+)
+
+const (
+	defaultResponseCacheTTL   = time.Minute
+	defaultResponseCacheBytes = 32 << 20
+)
+
+// newResponseCache builds the response cache from RESPONSE_CACHE_TTL and
+// RESPONSE_CACHE_MAX_BYTES. A TTL of 0 disables it.
+func newResponseCache() *respcache.Cache {
+	ttl := defaultResponseCacheTTL
+	if v := strings.TrimSpace(os.Getenv("RESPONSE_CACHE_TTL")); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			log.Printf("Ignoring invalid RESPONSE_CACHE_TTL %q", v)
+		} else {
+			ttl = d
+		}
+	}
+	if ttl == 0 {
+		return nil
+	}
+
+	maxBytes := int64(defaultResponseCacheBytes)
+	if v := strings.TrimSpace(os.Getenv("RESPONSE_CACHE_MAX_BYTES")); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			log.Printf("Ignoring invalid RESPONSE_CACHE_MAX_BYTES %q", v)
+		} else {
+			maxBytes = n
+		}
+	}
+	return respcache.New(maxBytes, ttl)
+}
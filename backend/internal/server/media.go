@@ -0,0 +1,40 @@
+package server
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/media"
+)
+
+const (
+	defaultMediaDir = "media"
+	// defaultMediaBaseURL follows the API mount, as /media is an API route.
+	defaultMediaBaseURL = APIPrefix + "/media"
+)
+
+// newMediaLibrary builds the media library from MEDIA_DIR, MEDIA_BASE_URL and
+// MEDIA_MAX_BYTES. Objects are kept on the local disk.
+func newMediaLibrary() *media.Library {
+	dir := strings.TrimSpace(os.Getenv("MEDIA_DIR"))
+	if dir == "" {
+		dir = defaultMediaDir
+	}
+	baseURL := strings.TrimSpace(os.Getenv("MEDIA_BASE_URL"))
+	if baseURL == "" {
+		baseURL = defaultMediaBaseURL
+	}
+
+	maxBytes := int64(media.DefaultMaxBytes)
+	if v := strings.TrimSpace(os.Getenv("MEDIA_MAX_BYTES")); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			log.Printf("Ignoring invalid MEDIA_MAX_BYTES %q", v)
+		} else {
+			maxBytes = n
+		}
+	}
+	return &media.Library{Storage: media.NewLocal(dir), BaseURL: baseURL, MaxBytes: maxBytes}
+}
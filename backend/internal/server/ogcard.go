@@ -0,0 +1,40 @@
+package server
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/ogcard"
+)
+
+const defaultOGCacheDir = "cache/og"
+
+// newCardGenerator builds the Open Graph card generator from OG_CACHE_DIR and
+// OG_EMOJI_FONT. An emoji font that cannot be loaded is logged and skipped.
+func newCardGenerator() *ogcard.Generator {
+	dir := strings.TrimSpace(os.Getenv("OG_CACHE_DIR"))
+	if dir == "" {
+		dir = defaultOGCacheDir
+	}
+
+	var emoji []byte
+	if path := strings.TrimSpace(os.Getenv("OG_EMOJI_FONT")); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Ignoring OG_EMOJI_FONT: %v", err)
+		} else {
+			emoji = data
+		}
+	}
+	renderer, err := ogcard.NewRenderer(emoji)
+	if err != nil && emoji != nil {
+		log.Printf("Ignoring OG_EMOJI_FONT: %v", err)
+		renderer, err = ogcard.NewRenderer(nil)
+	}
+	if err != nil {
+		// The embedded fonts always parse
+		panic(err)
+	}
+	return &ogcard.Generator{Renderer: renderer, Cache: &ogcard.Cache{Dir: dir}}
+}
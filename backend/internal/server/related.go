@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/related"
+)
+
+const (
+	// relatedTTL bounds how long co-visit data may go stale.
+	relatedTTL = 15 * time.Minute
+	// coVisitWindow is how far back page views count as co-visits.
+	coVisitWindow = 90 * 24 * time.Hour
+)
+
+// loadRelatedCorpus snapshots published content, recent sessions and the
+// admin overrides for the related-content engine.
+func (s *Server) loadRelatedCorpus(ctx context.Context) (related.Corpus, error) {
+	var c related.Corpus
+
+	posts, err := s.DB.ListRelatedPostCandidates(ctx)
+	if err != nil {
+		return c, err
+	}
+	for _, p := range posts {
+		c.Items = append(c.Items, related.Item{
+			Key:     related.Key{Type: related.TypePost, ID: p.ID},
+			Slug:    p.Slug,
+			Title:   p.Title,
+			Summary: p.Summary.String,
+			Tags:    p.Tags,
+			Terms:   p.Terms,
+		})
+	}
+
+	projects, err := s.DB.ListRelatedProjectCandidates(ctx)
+	if err != nil {
+		return c, err
+	}
+	for _, p := range projects {
+		c.Items = append(c.Items, related.Item{
+			Key:     related.Key{Type: related.TypeProject, ID: p.ID},
+			Slug:    p.Slug,
+			Title:   p.Title,
+			Summary: p.Summary.String,
+			Tags:    p.Tags,
+			Terms:   p.Terms,
+		})
+	}
+
+	views, err := s.DB.ListSessionPageViews(ctx, time.Now().Add(-coVisitWindow))
+	if err != nil {
+		return c, err
+	}
+	c.Sessions = map[string][]string{}
+	for _, v := range views {
+		c.Sessions[v.SessionID.String] = append(c.Sessions[v.SessionID.String], v.Path)
+	}
+
+	overrides, err := s.DB.ListRelatedOverrides(ctx)
+	if err != nil {
+		return c, err
+	}
+	for _, o := range overrides {
+		c.Overrides = append(c.Overrides, related.Override{
+			ID:     o.ID,
+			Source: related.Key{Type: o.SourceType, ID: o.SourceID},
+			Target: related.Key{Type: o.TargetType, ID: o.TargetID},
+			Action: o.Action,
+		})
+	}
+	return c, nil
+}
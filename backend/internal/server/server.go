@@ -1,25 +1,100 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"os"
+	"time"
 
 	_ "github.com/lib/pq" // postgres driver for database/sql
+	"github.com/onnwee/onnwee.github.io/backend/internal/bus"
 	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/feed"
+	"github.com/onnwee/onnwee.github.io/backend/internal/imagemeta"
+	"github.com/onnwee/onnwee.github.io/backend/internal/media"
+	"github.com/onnwee/onnwee.github.io/backend/internal/ogcard"
+	"github.com/onnwee/onnwee.github.io/backend/internal/redirects"
+	"github.com/onnwee/onnwee.github.io/backend/internal/related"
+	"github.com/onnwee/onnwee.github.io/backend/internal/respcache"
 )
 
+// redirectTTL bounds how stale the cached redirect rules may get when they
+// are changed outside this process.
+const redirectTTL = time.Minute
+
 type Server struct {
-	DB *db.Queries
+	DB        *db.Queries
+	Conn      *sql.DB
+	Redirects *redirects.Matcher
+	Related   *related.Engine
+	// Cache holds rendered public responses; nil when disabled.
+	Cache *respcache.Cache
+	// Bus carries domain events between instances; cmd/server runs its
+	// listener.
+	Bus *bus.Bus
+	// Media stores uploaded images and their variants.
+	Media *media.Library
+	// Images computes the placeholders of project images and post covers.
+	Images *imagemeta.Loader
+	// Cards renders the Open Graph images of projects and posts.
+	Cards *ogcard.Generator
+}
+
+func InitDB() (*sql.DB, error) {
+	return sql.Open("postgres", os.Getenv("DATABASE_URL"))
 }
 
-func InitDB() (*db.Queries, error) {
-	conn, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+func NewServer(conn *sql.DB) *Server {
+	s := &Server{DB: db.New(conn), Conn: conn}
+	s.Redirects = redirects.NewMatcher(s.RedirectRules, redirectTTL)
+	s.Related = related.NewEngine(s.loadRelatedCorpus, relatedTTL)
+	s.Cache = newResponseCache()
+	s.Bus = bus.New(s.DB)
+	s.Media = newMediaLibrary()
+	s.Images = imagemeta.NewLoader(s.Media, feed.SiteURL())
+	s.Cards = newCardGenerator()
+	return s
+}
+
+// RedirectRules loads the stored redirect rules.
+func (s *Server) RedirectRules(ctx context.Context) ([]redirects.Rule, error) {
+	rows, err := s.DB.ListRedirects(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return db.New(conn), nil
+	rules := make([]redirects.Rule, 0, len(rows))
+	for _, r := range rows {
+		rules = append(rules, redirects.Rule{ID: r.ID, Source: r.Source, Target: r.Target, StatusCode: int(r.StatusCode)})
+	}
+	return rules, nil
 }
 
-func NewServer(queries *db.Queries) *Server {
-	return &Server{DB: queries}
+// WithTx runs fn against a transaction-scoped Queries, committing if fn
+// returns nil and rolling back otherwise.
+func (s *Server) WithTx(ctx context.Context, fn func(q *db.Queries) error) error {
+	tx, err := s.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(s.DB.WithTx(tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// WithEvents runs fn in a transaction like WithTx, with an outbox on the same
+// transaction: the events fn adds are stored only if the change commits, and
+// reach this instance's handlers once it has.
+func (s *Server) WithEvents(ctx context.Context, fn func(q *db.Queries, out *bus.Outbox) error) error {
+	var out *bus.Outbox
+	err := s.WithTx(ctx, func(q *db.Queries) error {
+		out = s.Bus.Outbox(q)
+		return fn(q, out)
+	})
+	if err != nil {
+		return err
+	}
+	out.Deliver(ctx)
+	return nil
 }
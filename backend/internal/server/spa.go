@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/feed"
+	"github.com/onnwee/onnwee.github.io/backend/internal/spa"
+)
+
+// APIPrefix is the path the API is served under, beside the client.
+const APIPrefix = "/api"
+
+// descriptionLength bounds generated meta descriptions, about what search
+// results show.
+const descriptionLength = 160
+
+// LoadPage returns the head metadata of a post or project page for
+// spa.Handler. Drafts are not found; a former slug redirects.
+func (s *Server) LoadPage(ctx context.Context, r spa.Route) (spa.Page, error) {
+	site, name := feed.SiteURL(), feed.SiteName()
+	author := map[string]interface{}{"@type": "Person", "name": name}
+
+	switch r.Kind {
+	case spa.KindPost:
+		post, err := s.DB.GetPostBySlug(ctx, r.Slug)
+		if err == sql.ErrNoRows {
+			return s.movedPage(ctx, r, s.DB.ResolvePostSlugRedirect)
+		} else if err != nil {
+			return spa.Page{}, err
+		}
+		if post.IsDraft.Bool {
+			return spa.Page{}, spa.ErrNotFound
+		}
+
+		description := post.Summary.String
+		if description == "" {
+			description = spa.Excerpt(post.Content, descriptionLength)
+		}
+		page := spa.Page{
+			Title:       post.Title + " | " + name,
+			Description: description,
+			URL:         feed.PostURL(site, url.PathEscape(post.Slug)),
+			Image:       site + APIPrefix + "/og/posts/" + url.PathEscape(post.Slug) + ".png",
+			Type:        "article",
+		}
+		published := post.PublishedAt
+		if !published.Valid {
+			published = post.CreatedAt
+		}
+		page.JSONLD = map[string]interface{}{
+			"@context":         "https://schema.org",
+			"@type":            "BlogPosting",
+			"headline":         post.Title,
+			"description":      description,
+			"url":              page.URL,
+			"mainEntityOfPage": page.URL,
+			"image":            page.Image,
+			"keywords":         strings.Join(post.Tags, ", "),
+			"datePublished":    formatTime(published),
+			"dateModified":     formatTime(post.UpdatedAt),
+			"author":           author,
+		}
+		return page, nil
+
+	case spa.KindProject:
+		project, err := s.DB.GetProjectBySlug(ctx, r.Slug)
+		if err == sql.ErrNoRows {
+			return s.movedPage(ctx, r, s.DB.ResolveProjectSlugRedirect)
+		} else if err != nil {
+			return spa.Page{}, err
+		}
+		if project.Status == "draft" {
+			return spa.Page{}, spa.ErrNotFound
+		}
+
+		description := project.Summary.String
+		if description == "" {
+			description = project.Description.String
+		}
+		if description == "" {
+			description = spa.Excerpt(project.Content.String, descriptionLength)
+		}
+		title := project.Title
+		if project.Emoji.String != "" {
+			title = project.Emoji.String + " " + title
+		}
+		page := spa.Page{
+			Title:       title + " | " + name,
+			Description: description,
+			URL:         feed.ProjectURL(site, url.PathEscape(project.Slug)),
+			Image:       site + APIPrefix + "/og/projects/" + url.PathEscape(project.Slug) + ".png",
+			Type:        "website",
+		}
+		ld := map[string]interface{}{
+			"@context":     "https://schema.org",
+			"@type":        "CreativeWork",
+			"name":         project.Title,
+			"description":  description,
+			"url":          page.URL,
+			"image":        page.Image,
+			"keywords":     strings.Join(project.Tags, ", "),
+			"dateCreated":  formatTime(project.CreatedAt),
+			"dateModified": formatTime(project.UpdatedAt),
+			"author":       author,
+		}
+		if project.RepoUrl.String != "" {
+			ld["@type"] = "SoftwareSourceCode"
+			ld["codeRepository"] = project.RepoUrl.String
+		}
+		page.JSONLD = ld
+		return page, nil
+	}
+	return spa.Page{}, spa.ErrNotFound
+}
+
+// movedPage redirects a former slug to the page under its current one.
+func (s *Server) movedPage(ctx context.Context, r spa.Route, resolve func(context.Context, string) (string, error)) (spa.Page, error) {
+	canonical, err := resolve(ctx, r.Slug)
+	if err == sql.ErrNoRows {
+		return spa.Page{}, spa.ErrNotFound
+	} else if err != nil {
+		return spa.Page{}, err
+	}
+	return spa.Page{Redirect: spa.PagePath(spa.Route{Kind: r.Kind, Slug: canonical})}, nil
+}
+
+func formatTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
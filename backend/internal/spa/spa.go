@@ -0,0 +1,347 @@
+// Package spa serves the built client: its static files, and index.html for
+// every client route. Hashed build assets are cached for good; everything
+// else is revalidated on each use. For the routes of a single post or project, the
+// <head> of index.html is rewritten with the record's title, description,
+// Open Graph and Twitter tags and JSON-LD, so crawlers and link unfurlers
+// that do not run JavaScript see them.
+package spa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"html"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/httpcache"
+)
+
+// ErrNotFound is returned by a PageFunc for a slug that has no public page.
+var ErrNotFound = errors.New("spa: page not found")
+
+// Route is a client route with a page of its own.
+type Route struct {
+	// Kind is "post" for /blog/{slug} or "project" for /projects/{slug}.
+	Kind string
+	Slug string
+}
+
+// Route kinds.
+const (
+	KindPost    = "post"
+	KindProject = "project"
+)
+
+// Page is the metadata injected into index.html for a route.
+type Page struct {
+	Title       string
+	Description string
+	// URL is the canonical, absolute URL of the page.
+	URL string
+	// Image is an absolute URL of a 1200×630 preview image.
+	Image string
+	// Type is the og:type, e.g. article or website.
+	Type string
+	// JSONLD is encoded into a application/ld+json script when not nil.
+	JSONLD interface{}
+	// Redirect, when set, is the path the route moved to. Nothing else is
+	// used.
+	Redirect string
+}
+
+// PageFunc loads the page of a route, or returns ErrNotFound.
+type PageFunc func(ctx context.Context, r Route) (Page, error)
+
+// assetsDir is where Vite writes the bundles and imported files. Their names
+// carry a content hash, so a URL there never changes meaning.
+const assetsDir = "assets/"
+
+const (
+	immutable   = "public, max-age=31536000, immutable"
+	revalidated = "no-cache"
+)
+
+// Handler serves the files of a built client.
+type Handler struct {
+	fsys  fs.FS
+	pages PageFunc
+	// etags caches the ETag of unhashed files by name
+	etags sync.Map
+}
+
+type fileTag struct {
+	size    int64
+	modTime time.Time
+	etag    string
+}
+
+// New returns a Handler serving fsys, the client's dist directory. pages
+// may be nil to serve index.html unchanged.
+func New(fsys fs.FS, pages PageFunc) *Handler {
+	return &Handler{fsys: fsys, pages: pages}
+}
+
+// ParseRoute returns the route of a post or project page.
+func ParseRoute(p string) (Route, bool) {
+	dir, slug := path.Split(strings.TrimSuffix(p, "/"))
+	if slug == "" {
+		return Route{}, false
+	}
+	switch dir {
+	case "/blog/":
+		return Route{Kind: KindPost, Slug: slug}, true
+	case "/projects/":
+		return Route{Kind: KindProject, Slug: slug}, true
+	}
+	return Route{}, false
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if hidden(name) {
+		http.NotFound(w, r)
+		return
+	}
+	if name != "" && name != "index.html" {
+		if info, err := fs.Stat(h.fsys, name); err == nil && !info.IsDir() {
+			h.serveFile(w, r, name, info)
+			return
+		}
+		// A missing asset is a 404, not the app shell, which a browser would
+		// fail to run as a script. A page navigation always gets the shell,
+		// even for a client route with a dot in it.
+		if strings.HasPrefix(name, assetsDir) || (path.Ext(name) != "" && !acceptsHTML(r)) {
+			http.NotFound(w, r)
+			return
+		}
+	}
+	h.serveIndex(w, r)
+}
+
+// hidden reports whether a path has a dotfile in it, such as the .gitkeep of
+// an empty embedded build.
+func hidden(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// serveFile writes a static file. Hashed assets are immutable; other files
+// get an ETag from their content, as embedded files have no modification
+// time to validate against.
+func (h *Handler) serveFile(w http.ResponseWriter, r *http.Request, name string, info fs.FileInfo) {
+	f, err := h.fsys.Open(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, "failed to read file", http.StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+
+	if strings.HasPrefix(name, assetsDir) {
+		w.Header().Set("Cache-Control", immutable)
+	} else {
+		etag, err := h.etag(name, info, content)
+		if err != nil {
+			http.Error(w, "failed to read file", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Cache-Control", revalidated)
+		w.Header().Set("ETag", etag)
+	}
+	http.ServeContent(w, r, name, info.ModTime(), content)
+}
+
+// etag returns the ETag of an unhashed file, hashing it on first use and
+// again whenever its size or modification time change on disk.
+func (h *Handler) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	if v, ok := h.etags.Load(name); ok {
+		if t := v.(fileTag); t.size == info.Size() && t.modTime.Equal(info.ModTime()) {
+			return t.etag, nil
+		}
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := httpcache.ETag(string(data))
+	h.etags.Store(name, fileTag{size: info.Size(), modTime: info.ModTime(), etag: etag})
+	return etag, nil
+}
+
+// serveIndex writes index.html, with the page's tags when the path is a
+// post or project route. An unknown post or project is answered with the
+// unchanged shell and a 404, so the client can render its own not-found view.
+func (h *Handler) serveIndex(w http.ResponseWriter, r *http.Request) {
+	index, err := fs.ReadFile(h.fsys, "index.html")
+	if err != nil {
+		http.Error(w, "client not built", http.StatusNotFound)
+		return
+	}
+
+	status := http.StatusOK
+	if route, ok := ParseRoute(r.URL.Path); ok && h.pages != nil {
+		page, err := h.pages(r.Context(), route)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			status = http.StatusNotFound
+		case err != nil:
+			// Still serve the app; the client loads the data itself
+			log.Printf("Failed to load page metadata for %s: %v", r.URL.Path, err)
+		case page.Redirect != "":
+			target := page.Redirect
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+			return
+		default:
+			index = Inject(index, page)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// Always revalidate, so a deploy is picked up at once
+	w.Header().Set("Cache-Control", revalidated)
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(index)
+	}
+}
+
+var (
+	titleTag = regexp.MustCompile(`(?is)<title>.*?</title>`)
+	// The tags Inject replaces, wherever their attributes are
+	pageTags = regexp.MustCompile(`(?is)<meta\s[^>]*?\b(?:name|property)\s*=\s*["'](?:description|og:[^"']*|twitter:[^"']*)["'][^>]*>\s*` +
+		`|<link\s[^>]*?\brel\s*=\s*["']canonical["'][^>]*>\s*`)
+	headEnd = regexp.MustCompile(`(?i)</head>`)
+)
+
+// Inject replaces the title, description, canonical link, Open Graph and
+// Twitter tags of an HTML document with those of page, and adds its JSON-LD.
+func Inject(doc []byte, page Page) []byte {
+	doc = titleTag.ReplaceAllLiteral(doc, nil)
+	doc = pageTags.ReplaceAllLiteral(doc, nil)
+
+	var b bytes.Buffer
+	b.WriteString("<title>" + html.EscapeString(page.Title) + "</title>\n")
+	meta := func(attr, key, value string) {
+		if value != "" {
+			b.WriteString(`<meta ` + attr + `="` + key + `" content="` + html.EscapeString(value) + `" />` + "\n")
+		}
+	}
+	meta("name", "description", page.Description)
+	if page.URL != "" {
+		b.WriteString(`<link rel="canonical" href="` + html.EscapeString(page.URL) + `" />` + "\n")
+	}
+	meta("property", "og:title", page.Title)
+	meta("property", "og:description", page.Description)
+	meta("property", "og:type", page.Type)
+	meta("property", "og:url", page.URL)
+	if page.Image != "" {
+		meta("property", "og:image", page.Image)
+		meta("property", "og:image:width", "1200")
+		meta("property", "og:image:height", "630")
+		meta("name", "twitter:card", "summary_large_image")
+	} else {
+		meta("name", "twitter:card", "summary")
+	}
+	meta("name", "twitter:title", page.Title)
+	meta("name", "twitter:description", page.Description)
+	meta("name", "twitter:image", page.Image)
+	if page.JSONLD != nil {
+		// json.Marshal escapes <, > and &, so the data cannot close the script
+		if data, err := json.Marshal(page.JSONLD); err == nil {
+			b.WriteString(`<script type="application/ld+json">` + string(data) + "</script>\n")
+		}
+	}
+
+	loc := headEnd.FindIndex(doc)
+	if loc == nil {
+		return append(b.Bytes(), doc...)
+	}
+	out := make([]byte, 0, len(doc)+b.Len())
+	out = append(out, doc[:loc[0]]...)
+	out = append(out, b.Bytes()...)
+	return append(out, doc[loc[0]:]...)
+}
+
+// PagePath returns the client path of a route, e.g. /blog/hello.
+func PagePath(r Route) string {
+	if r.Kind == KindPost {
+		return "/blog/" + url.PathEscape(r.Slug)
+	}
+	return "/projects/" + url.PathEscape(r.Slug)
+}
+
+var (
+	mdImage  = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	mdLink   = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	mdMarks  = strings.NewReplacer("#", "", "*", "", "_", "", "`", "", ">", "", "~", "")
+	mdIgnore = regexp.MustCompile(`(?m)^\s*(?:import|export)\s.*$|^\s*</?[A-Za-z][^>]*>\s*$`)
+)
+
+// Excerpt returns the opening of Markdown or MDX content as plain text, cut
+// at a word boundary to at most max runes.
+func Excerpt(content string, max int) string {
+	// Drop fenced code
+	var kept []string
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			kept = append(kept, line)
+		}
+	}
+	text := mdIgnore.ReplaceAllString(strings.Join(kept, "\n"), "")
+	text = mdImage.ReplaceAllString(text, "")
+	text = mdLink.ReplaceAllString(text, "$1")
+	text = strings.Join(strings.Fields(mdMarks.Replace(text)), " ")
+
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	cut := string(runes[:max-1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
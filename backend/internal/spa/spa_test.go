@@ -0,0 +1,212 @@
+package spa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const indexHTML = `<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8" />
+    <title>onnwee</title>
+    <meta
+      name="description"
+      content="Portfolio."
+    />
+    <meta property="og:title" content="onnwee" />
+    <meta property="og:image" content="https://onnwee.github.io/preview.png" />
+    <meta name="twitter:card" content="summary_large_image" />
+    <link rel="icon" href="/favicon.svg" />
+  </head>
+  <body><div id="root"></div></body>
+</html>
+`
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":           {Data: []byte(indexHTML)},
+		"assets/app-1a2b3c.js": {Data: []byte("console.log(1)")},
+		"favicon.svg":          {Data: []byte("<svg/>")},
+		".gitkeep":             {Data: []byte{}},
+	}
+}
+
+func testPages(_ context.Context, r Route) (Page, error) {
+	switch {
+	case r.Kind == KindPost && r.Slug == "hello":
+		return Page{
+			Title:       `Hello "world" <3 | onnwee`,
+			Description: "A first post",
+			URL:         "https://example.com/blog/hello",
+			Image:       "https://example.com/api/og/posts/hello.png",
+			Type:        "article",
+			JSONLD:      map[string]string{"@type": "BlogPosting", "headline": "</script><script>alert(1)"},
+		}, nil
+	case r.Kind == KindProject && r.Slug == "old":
+		return Page{Redirect: "/projects/new"}, nil
+	case r.Slug == "broken":
+		return Page{}, errors.New("db down")
+	}
+	return Page{}, ErrNotFound
+}
+
+func get(h http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		path string
+		want Route
+		ok   bool
+	}{
+		{"/blog/hello", Route{KindPost, "hello"}, true},
+		{"/blog/hello/", Route{KindPost, "hello"}, true},
+		{"/projects/onnwee", Route{KindProject, "onnwee"}, true},
+		{"/projects", Route{}, false},
+		{"/projects/", Route{}, false},
+		{"/blog/a/b", Route{}, false},
+		{"/about", Route{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseRoute(tt.path)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseRoute(%q) = %+v, %v", tt.path, got, ok)
+		}
+	}
+}
+
+func TestInjectedPage(t *testing.T) {
+	rec := get(New(testFS(), testPages), "/blog/hello")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`<title>Hello &#34;world&#34; &lt;3 | onnwee</title>`,
+		`<meta name="description" content="A first post" />`,
+		`<link rel="canonical" href="https://example.com/blog/hello" />`,
+		`<meta property="og:type" content="article" />`,
+		`<meta property="og:image" content="https://example.com/api/og/posts/hello.png" />`,
+		`<meta name="twitter:card" content="summary_large_image" />`,
+		`<script type="application/ld+json">`,
+		`<link rel="icon" href="/favicon.svg" />`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body lacks %s", want)
+		}
+	}
+	for _, gone := range []string{"Portfolio.", "preview.png", "<title>onnwee</title>", "</script><script>"} {
+		if strings.Contains(body, gone) {
+			t.Errorf("body still contains %s", gone)
+		}
+	}
+	if strings.Count(body, "twitter:card") != 1 {
+		t.Error("twitter:card is duplicated")
+	}
+	if strings.Index(body, "application/ld+json") > strings.Index(body, "</head>") {
+		t.Error("tags were not added to the head")
+	}
+}
+
+func TestFallback(t *testing.T) {
+	h := New(testFS(), testPages)
+
+	tests := []struct {
+		path   string
+		status int
+		shell  bool
+	}{
+		{"/", http.StatusOK, true},
+		{"/about", http.StatusOK, true},
+		{"/admin/projects", http.StatusOK, true},
+		{"/blog/missing", http.StatusNotFound, true},
+		{"/blog/broken", http.StatusOK, true},
+		{"/assets/missing-123.js", http.StatusNotFound, false},
+		{"/robots.txt", http.StatusNotFound, false},
+		{"/.gitkeep", http.StatusNotFound, false},
+	}
+	for _, tt := range tests {
+		rec := get(h, tt.path)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.path, rec.Code, tt.status)
+		}
+		if shell := strings.Contains(rec.Body.String(), "Portfolio."); shell != tt.shell {
+			t.Errorf("%s: served the unchanged shell = %v, want %v", tt.path, shell, tt.shell)
+		}
+		if tt.shell && rec.Header().Get("Cache-Control") != "no-cache" {
+			t.Errorf("%s: Cache-Control = %q", tt.path, rec.Header().Get("Cache-Control"))
+		}
+	}
+
+	rec := get(h, "/assets/app-1a2b3c.js")
+	if rec.Code != http.StatusOK || rec.Body.String() != "console.log(1)" {
+		t.Errorf("asset: %d %q", rec.Code, rec.Body.String())
+	}
+
+	// A page navigation gets the shell even with a dot in the path, but never
+	// for a missing bundle
+	navigate := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := navigate("/notes/v1.2"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Portfolio.") {
+		t.Errorf("navigation with a dot: %d", rec.Code)
+	}
+	if rec := navigate("/assets/missing-123.js"); rec.Code != http.StatusNotFound {
+		t.Errorf("navigation to a missing asset: %d", rec.Code)
+	}
+
+	rec = get(h, "/projects/old?ref=x")
+	if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/projects/new?ref=x" {
+		t.Errorf("former slug: %d to %q", rec.Code, rec.Header().Get("Location"))
+	}
+}
+
+func TestCaching(t *testing.T) {
+	h := New(testFS(), nil)
+
+	rec := get(h, "/assets/app-1a2b3c.js")
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=31536000, immutable" {
+		t.Errorf("hashed asset: Cache-Control = %q", got)
+	}
+
+	rec = get(h, "/favicon.svg")
+	etag := rec.Header().Get("ETag")
+	if got := rec.Header().Get("Cache-Control"); got != "no-cache" || etag == "" {
+		t.Fatalf("unhashed file: Cache-Control = %q, ETag = %q", got, etag)
+	}
+	if rec.Body.String() != "<svg/>" {
+		t.Errorf("unhashed file: body = %q", rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/favicon.svg", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("revalidation: status = %d, want 304", rec.Code)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	content := "import Callout from '../Callout'\n\n# Hello\n\nThis is **bold** and [a link](https://x.y).\n\n```go\nfunc main() {}\n```\n\n![img](a.png) More text here."
+	if got, want := Excerpt(content, 200), "Hello This is bold and a link. More text here."; got != want {
+		t.Errorf("Excerpt = %q, want %q", got, want)
+	}
+	if got := Excerpt("one two three four five", 12); got != "one two…" {
+		t.Errorf("truncated Excerpt = %q", got)
+	}
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSlugLen keeps generated slugs readable in URLs.
+const maxSlugLen = 80
+
+// Slugify turns a title into a URL slug: lowercase ASCII letters and digits
+// separated by single hyphens. Accents are stripped; other characters become
+// separators.
+func Slugify(title string) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue // combining accent left over from decomposition
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			b.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’':
+			continue // "Don't" -> "dont"
+		default:
+			pendingDash = true
+		}
+	}
+	s := b.String()
+	if len(s) > maxSlugLen {
+		s = strings.TrimRight(s[:maxSlugLen], "-")
+	}
+	return s
+}
+
+// UniqueSlug returns base, or base-2, base-3, ... for the first candidate
+// that taken reports as free.
+func UniqueSlug(ctx context.Context, base string, taken func(context.Context, string) (bool, error)) (string, error) {
+	for n := 1; n <= 100; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+		used, err := taken(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no free slug for %q", base)
+}
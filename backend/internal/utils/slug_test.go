@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Go 1.24: What's New?  ", "go-1-24-whats-new"},
+		{"Crème brûlée", "creme-brulee"},
+		{"multiple---dashes__here", "multiple-dashes-here"},
+		{"日本語", ""},
+		{"", ""},
+		{strings.Repeat("ab ", 50), strings.TrimRight(strings.Repeat("ab-", 27), "-")},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.title); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	used := map[string]bool{"post": true, "post-2": true, "other": false}
+	taken := func(_ context.Context, s string) (bool, error) { return used[s], nil }
+
+	tests := []struct {
+		base string
+		want string
+	}{
+		{"post", "post-3"},
+		{"other", "other"},
+		{"fresh", "fresh"},
+	}
+	for _, tt := range tests {
+		got, err := UniqueSlug(context.Background(), tt.base, taken)
+		if err != nil {
+			t.Errorf("UniqueSlug(%q) error: %v", tt.base, err)
+		} else if got != tt.want {
+			t.Errorf("UniqueSlug(%q) = %q, want %q", tt.base, got, tt.want)
+		}
+	}
+
+	boom := errors.New("boom")
+	if _, err := UniqueSlug(context.Background(), "x", func(context.Context, string) (bool, error) { return false, boom }); !errors.Is(err, boom) {
+		t.Errorf("UniqueSlug error = %v, want %v", err, boom)
+	}
+}
@@ -0,0 +1,6 @@
+-- Track when a post was published, independently of when the row was created.
+-- Populated from the MDX frontmatter `date` by cmd/content.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+-- Backfill existing published posts with their creation time
+UPDATE posts SET published_at = created_at WHERE published_at IS NULL AND is_draft IS NOT TRUE;
@@ -0,0 +1,24 @@
+-- Reader comments on posts, threaded via parent_id and held for moderation.
+CREATE TABLE IF NOT EXISTS comments (
+  id SERIAL PRIMARY KEY,
+  post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+  parent_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+  -- Either an authenticated user or a self-declared author name/email
+  user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+  author_name TEXT,
+  author_email TEXT,
+  body TEXT NOT NULL,
+  body_html TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'spam', 'deleted')),
+  -- Heuristics that flagged the comment, for moderators
+  spam_reasons TEXT[] NOT NULL DEFAULT '{}',
+  -- SHA-256 of the client IP, used for per-IP rate limiting
+  ip_hash TEXT,
+  user_agent TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_post_status ON comments(post_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_status_created ON comments(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_ip_hash_created ON comments(ip_hash, created_at);
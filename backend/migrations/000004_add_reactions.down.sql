@@ -0,0 +1,3 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS reaction_counts;
+ALTER TABLE posts DROP COLUMN IF EXISTS reaction_counts;
+DROP TABLE IF EXISTS reactions;
@@ -0,0 +1,21 @@
+-- Anonymous reactions on posts and projects, one per visitor and kind.
+CREATE TABLE IF NOT EXISTS reactions (
+  id SERIAL PRIMARY KEY,
+  post_id INTEGER REFERENCES posts(id) ON DELETE CASCADE,
+  project_id INTEGER REFERENCES projects(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('like', 'fire', 'heart')),
+  -- Salted SHA-256 of the analytics session ID, or of IP + user agent
+  visitor_hash TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  CHECK ((post_id IS NULL) <> (project_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_post_visitor
+  ON reactions(post_id, kind, visitor_hash) WHERE post_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_project_visitor
+  ON reactions(project_id, kind, visitor_hash) WHERE project_id IS NOT NULL;
+
+-- Denormalized per-kind counts, e.g. {"like": 3, "fire": 1}, kept in step with
+-- the reactions table inside the same transaction.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reaction_counts JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS reaction_counts JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,15 @@
+-- Former slugs of posts and projects, so old links keep resolving after a rename.
+CREATE TABLE IF NOT EXISTS slug_redirects (
+  id SERIAL PRIMARY KEY,
+  old_slug TEXT NOT NULL,
+  post_id INTEGER REFERENCES posts(id) ON DELETE CASCADE,
+  project_id INTEGER REFERENCES projects(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  CHECK ((post_id IS NULL) <> (project_id IS NULL))
+);
+
+-- An old slug points at one post and one project at most
+CREATE UNIQUE INDEX IF NOT EXISTS idx_slug_redirects_post
+  ON slug_redirects(old_slug) WHERE post_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_slug_redirects_project
+  ON slug_redirects(old_slug) WHERE project_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS not_found_paths;
+DROP TABLE IF EXISTS redirects;
@@ -0,0 +1,23 @@
+-- Admin-managed redirects, matched before routing. A source ending in /*
+-- matches every path under it; :splat in the target is replaced by the rest.
+CREATE TABLE IF NOT EXISTS redirects (
+  id SERIAL PRIMARY KEY,
+  source TEXT UNIQUE NOT NULL,
+  target TEXT NOT NULL,
+  status_code INTEGER NOT NULL DEFAULT 301 CHECK (status_code IN (301, 302, 307, 308)),
+  hit_count BIGINT NOT NULL DEFAULT 0,
+  last_hit_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- 404s aggregated per path, so broken inbound links can be found and fixed.
+CREATE TABLE IF NOT EXISTS not_found_paths (
+  path TEXT PRIMARY KEY,
+  hits BIGINT NOT NULL DEFAULT 0,
+  last_referrer TEXT,
+  first_seen_at TIMESTAMPTZ DEFAULT NOW(),
+  last_seen_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_not_found_paths_hits ON not_found_paths(hits DESC);
@@ -0,0 +1,18 @@
+-- Multi-part post series with an ordered membership. A post belongs to one
+-- series at most.
+CREATE TABLE IF NOT EXISTS series (
+  id SERIAL PRIMARY KEY,
+  title TEXT NOT NULL,
+  slug TEXT UNIQUE NOT NULL,
+  description TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS series_posts (
+  series_id INTEGER NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+  post_id INTEGER NOT NULL UNIQUE REFERENCES posts(id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  PRIMARY KEY (series_id, post_id),
+  UNIQUE (series_id, position)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_page_views_session_viewed;
+DROP TABLE IF EXISTS related_overrides;
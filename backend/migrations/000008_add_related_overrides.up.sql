@@ -0,0 +1,15 @@
+-- Admin pins and exclusions applied on top of computed related content.
+CREATE TABLE IF NOT EXISTS related_overrides (
+  id SERIAL PRIMARY KEY,
+  source_type TEXT NOT NULL CHECK (source_type IN ('post', 'project')),
+  source_id INTEGER NOT NULL,
+  target_type TEXT NOT NULL CHECK (target_type IN ('post', 'project')),
+  target_id INTEGER NOT NULL,
+  action TEXT NOT NULL CHECK (action IN ('pin', 'exclude')),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (source_type, source_id, target_type, target_id)
+);
+
+-- Co-visitation scans page views grouped by session
+CREATE INDEX IF NOT EXISTS idx_page_views_session_viewed
+  ON page_views(session_id, viewed_at) WHERE session_id IS NOT NULL;
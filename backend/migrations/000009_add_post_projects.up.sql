@@ -0,0 +1,11 @@
+-- Posts that write about projects. Links are attached by an admin ('manual')
+-- or found from /projects/{slug} URLs in the post body ('detected').
+CREATE TABLE IF NOT EXISTS post_projects (
+  post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+  project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  source TEXT NOT NULL DEFAULT 'manual' CHECK (source IN ('manual', 'detected')),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (post_id, project_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_projects_project ON post_projects(project_id);
@@ -0,0 +1,18 @@
+-- Dated changelog entries for a project. body_html is rendered from the
+-- Markdown body on write.
+CREATE TABLE IF NOT EXISTS project_updates (
+  id SERIAL PRIMARY KEY,
+  project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  title TEXT NOT NULL,
+  body TEXT NOT NULL DEFAULT '',
+  body_html TEXT NOT NULL DEFAULT '',
+  version TEXT,
+  link TEXT,
+  published_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_updates_project_published
+  ON project_updates (project_id, published_at DESC);
+CREATE INDEX IF NOT EXISTS idx_project_updates_published ON project_updates (published_at DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_projects_listing;
+ALTER TABLE projects
+  DROP COLUMN IF EXISTS sort_order,
+  DROP COLUMN IF EXISTS featured,
+  DROP COLUMN IF EXISTS status;
@@ -0,0 +1,9 @@
+-- Draft projects are hidden from public listings; featured projects and a
+-- manual sort_order decide the listing order.
+ALTER TABLE projects
+  ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'published', 'archived')),
+  ADD COLUMN IF NOT EXISTS featured BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN IF NOT EXISTS sort_order INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_projects_listing ON projects (featured DESC, sort_order, created_at DESC);
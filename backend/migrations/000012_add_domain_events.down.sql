@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS domain_events_notify ON domain_events;
+DROP FUNCTION IF EXISTS notify_domain_event();
+DROP TABLE IF EXISTS domain_events;
@@ -0,0 +1,25 @@
+-- Outbox of domain events (post.published, project.updated, ...). Each row is
+-- inserted in the transaction of the change it describes, and announced on the
+-- domain_events channel when that commits; listeners read the rows after the
+-- last ID they handled, so a missed notification is caught up on.
+CREATE TABLE IF NOT EXISTS domain_events (
+  id BIGSERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}',
+  origin TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_events_created_at ON domain_events (created_at);
+
+CREATE OR REPLACE FUNCTION notify_domain_event() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('domain_events', NEW.id::text);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS domain_events_notify ON domain_events;
+CREATE TRIGGER domain_events_notify
+  AFTER INSERT ON domain_events
+  FOR EACH ROW EXECUTE FUNCTION notify_domain_event();
@@ -0,0 +1,17 @@
+-- Uploaded images. key names the metadata-free original in media storage;
+-- variants lists the resized WebP/JPEG renditions stored next to it.
+CREATE TABLE IF NOT EXISTS media (
+  id SERIAL PRIMARY KEY,
+  key TEXT UNIQUE NOT NULL,
+  filename TEXT NOT NULL,
+  content_type TEXT NOT NULL,
+  size_bytes BIGINT NOT NULL,
+  width INTEGER NOT NULL,
+  height INTEGER NOT NULL,
+  alt_text TEXT NOT NULL DEFAULT '',
+  variants JSONB NOT NULL DEFAULT '[]',
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_created_at ON media (created_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS image_meta;
+ALTER TABLE posts DROP COLUMN IF EXISTS cover_image_meta;
+ALTER TABLE posts DROP COLUMN IF EXISTS cover_image;
@@ -0,0 +1,6 @@
+-- Layout and blur-up metadata for project images and post covers. The JSON
+-- records the URL it was computed for, so a changed image is detected as
+-- stale: {src, width, height, blurhash, placeholder, dominant_color}.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS cover_image TEXT NOT NULL DEFAULT '';
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS cover_image_meta JSONB;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS image_meta JSONB;
@@ -61,3 +61,28 @@ func GetUserIDFromContext(ctx context.Context) (int32, bool) {
 	userID, ok := ctx.Value(userIDContextKey).(int32)
 	return userID, ok
 }
+
+// OptionalAuth adds the user ID to the context when the request carries a
+// valid session cookie, and otherwise lets the request through anonymously.
+func OptionalAuth(queries *db.Queries) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie(sessionCookieName)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			sessionID, err := uuid.Parse(cookie.Value)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			session, err := queries.GetValidSession(r.Context(), sessionID)
+			if err == nil && session.UserID.Valid {
+				ctx := context.WithValue(r.Context(), userIDContextKey, session.UserID.Int32)
+				r = r.WithContext(ctx)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
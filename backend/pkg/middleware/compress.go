@@ -0,0 +1,308 @@
+package middleware
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressMinSize is the smallest body worth compressing; below it the
+// framing overhead eats the savings.
+const compressMinSize = 1024
+
+// encoder is the part of the gzip, brotli and zstd writers Compress uses.
+type encoder interface {
+	io.Writer
+	Flush() error
+	Close() error
+	Reset(io.Writer)
+}
+
+type encoding struct {
+	name string
+	pool sync.Pool
+}
+
+// encodings lists the supported content codings in order of preference,
+// used when the client weights several of them equally.
+var encodings = []*encoding{
+	{name: "zstd", pool: sync.Pool{New: func() interface{} {
+		// A small window keeps memory per response down and stays within
+		// what browsers accept
+		enc, _ := zstd.NewWriter(nil,
+			zstd.WithEncoderLevel(zstd.SpeedDefault),
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithWindowSize(1<<20))
+		return enc
+	}}},
+	{name: "br", pool: sync.Pool{New: func() interface{} {
+		return brotli.NewWriterLevel(nil, 5)
+	}}},
+	{name: "gzip", pool: sync.Pool{New: func() interface{} {
+		enc, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return enc
+	}}},
+}
+
+// Compress encodes responses with zstd, brotli or gzip, whichever the
+// client's Accept-Encoding weights highest. Bodies shorter than
+// compressMinSize, types that are already compressed (images, archives,
+// fonts) and responses that set their own Content-Encoding pass through
+// unchanged. Flushing a response commits to compression straight away, so
+// streams are encoded and flushed chunk by chunk.
+//
+// A strong validator must differ between content codings, so an encoded
+// response's ETag gets the coding appended ("…-gzip"). The suffix is
+// stripped from If-None-Match and If-Match before the handler compares them.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch := r.Header.Get("If-None-Match")
+		r = stripEncodingTags(r)
+		enc := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if enc == nil || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{ResponseWriter: w, encoding: enc, ifNoneMatch: ifNoneMatch}
+		next.ServeHTTP(cw, r)
+		cw.close()
+	})
+}
+
+// encodingTags removes the coding suffix from the entity tags in a header.
+var encodingTags = strings.NewReplacer(`-zstd"`, `"`, `-br"`, `"`, `-gzip"`, `"`)
+
+// stripEncodingTags returns r with the coding suffixes Compress adds removed
+// from its conditional headers.
+func stripEncodingTags(r *http.Request) *http.Request {
+	var clone *http.Request
+	for _, name := range []string{"If-None-Match", "If-Match"} {
+		v := r.Header.Get(name)
+		if v == "" {
+			continue
+		}
+		if stripped := encodingTags.Replace(v); stripped != v {
+			if clone == nil {
+				clone = r.Clone(r.Context())
+			}
+			clone.Header.Set(name, stripped)
+		}
+	}
+	if clone == nil {
+		return r
+	}
+	return clone
+}
+
+// encodedETag appends a content coding to an entity tag.
+func encodedETag(etag, coding string) string {
+	if !strings.HasSuffix(etag, `"`) || len(etag) < 2 {
+		return etag
+	}
+	return etag[:len(etag)-1] + "-" + coding + `"`
+}
+
+// negotiateEncoding picks the encoding the Accept-Encoding header weights
+// highest, or nil if it accepts none of them.
+func negotiateEncoding(header string) *encoding {
+	if header == "" {
+		return nil
+	}
+	weights := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "x-gzip" {
+			name = "gzip"
+		}
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if ok && strings.EqualFold(k, "q") {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if name != "" {
+			weights[name] = q
+		}
+	}
+
+	var best *encoding
+	bestQ := 0.0
+	for _, e := range encodings {
+		q, ok := weights[e.name]
+		if !ok {
+			q = weights["*"]
+		}
+		if q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+	return best
+}
+
+// compressible reports whether a Content-Type is worth compressing.
+func compressible(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mt, "text/"),
+		strings.HasSuffix(mt, "+json"),
+		strings.HasSuffix(mt, "+xml"):
+		return true
+	}
+	switch mt {
+	case "application/json", "application/x-ndjson", "application/javascript",
+		"application/xml", "application/wasm", "font/ttf", "font/otf":
+		return true
+	}
+	return false
+}
+
+// compressWriter holds the start of the body back until it knows whether to
+// compress: once compressMinSize bytes arrive, on Flush, or when the handler
+// returns.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding *encoding
+	// ifNoneMatch is the request's header before the suffixes were stripped
+	ifNoneMatch string
+	status      int
+	buf         []byte
+	decided     bool
+	enc         encoder
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code >= 100 && code < 200 {
+		// Informational responses go out as they are
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.decided {
+		if w.enc != nil {
+			return w.enc.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= compressMinSize {
+		if err := w.decide(false); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush sends everything written so far, compressing it if the response is
+// compressible at all.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+	if w.enc != nil {
+		if err := w.enc.Flush(); err != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide writes the header, choosing between compressing and passing the
+// body through, then the buffered body. stream is true when the handler
+// flushed, in which case the size threshold does not apply.
+func (w *compressWriter) decide(stream bool) error {
+	w.decided = true
+	h := w.Header()
+	if h.Get("Content-Type") == "" && len(w.buf) > 0 {
+		// The compressed bytes would defeat net/http's sniffing
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	eligible := h.Get("Content-Encoding") == "" &&
+		compressible(h.Get("Content-Type")) &&
+		!strings.Contains(h.Get("Cache-Control"), "no-transform") &&
+		w.status >= http.StatusOK &&
+		w.status != http.StatusNoContent &&
+		w.status != http.StatusPartialContent &&
+		w.status != http.StatusNotModified
+	if eligible {
+		h.Add("Vary", "Accept-Encoding")
+	}
+	if etag := h.Get("ETag"); etag != "" && w.status == http.StatusNotModified {
+		// Confirm the encoded representation the client holds, if that is
+		// the one it asked about
+		if tagged := encodedETag(etag, w.encoding.name); strings.Contains(w.ifNoneMatch, tagged) {
+			h.Set("ETag", tagged)
+		}
+	}
+	if eligible && (stream || len(w.buf) >= compressMinSize) {
+		if etag := h.Get("ETag"); etag != "" {
+			h.Set("ETag", encodedETag(etag, w.encoding.name))
+		}
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", w.encoding.name)
+		w.enc = w.encoding.pool.Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close finishes the response once the handler has returned.
+func (w *compressWriter) close() {
+	if !w.decided && w.status != 0 {
+		_ = w.decide(false)
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+		w.encoding.pool.Put(w.enc)
+		w.enc = nil
+	}
+}
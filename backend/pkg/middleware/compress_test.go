@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"x-gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"gzip, deflate, br, zstd", "zstd"},
+		{"br;q=0.5, gzip;q=0.8", "gzip"},
+		{"zstd;q=0, br;q=0, gzip", "gzip"},
+		{"*", "zstd"},
+		{"*;q=0.5, br", "br"},
+		{"gzip;q=0", ""},
+		{"GZIP; Q=0.9", "gzip"},
+	}
+	for _, tt := range tests {
+		got := ""
+		if e := negotiateEncoding(tt.header); e != nil {
+			got = e.name
+		}
+		if got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func decode(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+	var r io.Reader
+	switch encoding {
+	case "gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("gzip: %v", err)
+		}
+		r = zr
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(body))
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("zstd: %v", err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return string(body)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decode %s: %v", encoding, err)
+	}
+	return string(out)
+}
+
+func TestCompress(t *testing.T) {
+	large := `{"items":[` + strings.Repeat(`{"title":"hello"},`, 200) + `{}]}`
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		encoded     string
+		body        string
+		want        string // expected Content-Encoding
+	}{
+		{"gzip", "gzip", "application/json", "", large, "gzip"},
+		{"brotli", "br", "application/json", "", large, "br"},
+		{"zstd", "zstd", "application/json", "", large, "zstd"},
+		{"below threshold", "gzip", "application/json", "", `{"ok":true}`, ""},
+		{"no accept-encoding", "", "application/json", "", large, ""},
+		{"already compressed type", "gzip", "image/png", "", large, ""},
+		{"already encoded", "gzip", "application/json", "identity", large, "identity"},
+		{"sniffed type", "gzip", "", "", "<html>" + large, "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Compress(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				if tt.encoded != "" {
+					w.Header().Set("Content-Encoding", tt.encoded)
+				}
+				w.Header().Set("Content-Length", "1")
+				// Several writes straddling the threshold
+				for i := 0; i < len(tt.body); i += 100 {
+					_, _ = w.Write([]byte(tt.body[i:min(i+100, len(tt.body))]))
+				}
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Encoding", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			got := rec.Header().Get("Content-Encoding")
+			if got != tt.want {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.want)
+			}
+			if tt.want != "" && tt.want != "identity" {
+				if rec.Header().Get("Content-Length") != "" {
+					t.Errorf("Content-Length kept on a compressed response")
+				}
+				if rec.Header().Get("Vary") != "Accept-Encoding" {
+					t.Errorf("Vary = %q, want Accept-Encoding", rec.Header().Get("Vary"))
+				}
+			}
+			if body := decode(t, got, rec.Body.Bytes()); body != tt.body {
+				t.Errorf("body mismatch: got %d bytes, want %d", len(body), len(tt.body))
+			}
+		})
+	}
+}
+
+func TestCompressETag(t *testing.T) {
+	large := strings.Repeat("x", 2*compressMinSize)
+	h := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(large))
+	}))
+	serve := func(accept, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", accept)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if got := serve("", "").Header().Get("ETag"); got != `"abc"` {
+		t.Errorf("identity ETag = %q", got)
+	}
+	rec := serve("gzip", "")
+	if got := rec.Header().Get("ETag"); got != `"abc-gzip"` {
+		t.Fatalf("gzip ETag = %q, want the coding appended", got)
+	}
+
+	// The encoded tag revalidates, and the 304 names it
+	rec = serve("gzip", `"abc-gzip"`)
+	if rec.Code != http.StatusNotModified || rec.Header().Get("ETag") != `"abc-gzip"` {
+		t.Errorf("revalidating gzip: %d, ETag %q", rec.Code, rec.Header().Get("ETag"))
+	}
+	rec = serve("gzip", `"abc"`)
+	if rec.Code != http.StatusNotModified || rec.Header().Get("ETag") != `"abc"` {
+		t.Errorf("revalidating identity: %d, ETag %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestCompressKeepsStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"not found", http.StatusNotFound, strings.Repeat("x", 2000), "gzip"},
+		{"no content", http.StatusNoContent, "", ""},
+		{"not modified", http.StatusNotModified, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var captured *responseWriter
+			h := Compress(Metrics(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				captured = w.(*responseWriter)
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})))
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if captured.statusCode != tt.status {
+				t.Errorf("Metrics captured %d, want %d", captured.statusCode, tt.status)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.want {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressStreaming(t *testing.T) {
+	chunks := []string{"data: one\n\n", "data: two\n\n"}
+	var flushed []int
+	var rec *httptest.ResponseRecorder
+	h := Compress(Metrics(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, c := range chunks {
+			_, _ = w.Write([]byte(c))
+			if err := http.NewResponseController(w).Flush(); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+			// Each flush reaches the client before the next chunk is written
+			flushed = append(flushed, rec.Body.Len())
+		}
+	})))
+	req := httptest.NewRequest("GET", "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if !rec.Flushed {
+		t.Error("flush did not reach the underlying writer")
+	}
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", rec.Header().Get("Content-Encoding"))
+	}
+	if len(flushed) != 2 || flushed[0] == 0 || flushed[1] <= flushed[0] {
+		t.Errorf("body sizes after each flush = %v, want growing", flushed)
+	}
+	if got := decode(t, "gzip", rec.Body.Bytes()); got != strings.Join(chunks, "") {
+		t.Errorf("body = %q", got)
+	}
+}
@@ -12,38 +12,105 @@ type visitor struct {
 	tokens   int
 }
 
-var (
-	visitors     = make(map[string]*visitor)
-	visitorsLock sync.Mutex
-	maxRequests  = 60              // per window
-	window       = 1 * time.Minute // window duration
+const (
+	maxRequests = 60              // per window
+	window      = 1 * time.Minute // window duration
 )
 
-func RateLimit(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := r.Header.Get("X-Client-IP")
-		if ip == "" {
-			ip = r.RemoteAddr
+// RateLimitOptions configures a RateLimiter.
+type RateLimitOptions struct {
+	// BlockKey maps an IP address to the key Block takes. It defaults to
+	// the address itself; a salted hash lets instances share blocks without
+	// sharing addresses.
+	BlockKey func(ip string) string
+	// RateLimited, when set, is called once a visitor runs out of requests,
+	// with the time its window ends. It runs in its own goroutine, so it may
+	// block.
+	RateLimited func(ip string, until time.Time)
+}
+
+// RateLimiter allows each visitor maxRequests per window. Each RateLimiter
+// counts its own requests.
+type RateLimiter struct {
+	opts RateLimitOptions
+
+	mu       sync.Mutex
+	visitors map[string]*visitor
+	// blocked holds the visitors other instances rate limited, by BlockKey
+	blocked map[string]time.Time
+}
+
+// NewRateLimiter creates a RateLimiter.
+func NewRateLimiter(opts RateLimitOptions) *RateLimiter {
+	if opts.BlockKey == nil {
+		opts.BlockKey = func(ip string) string { return ip }
+	}
+	return &RateLimiter{
+		opts:     opts,
+		visitors: make(map[string]*visitor),
+		blocked:  make(map[string]time.Time),
+	}
+}
+
+// Block rejects requests from the visitor with the given BlockKey until the
+// given time, e.g. because another instance rate limited it.
+func (l *RateLimiter) Block(key string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for k, t := range l.blocked {
+		if !now.Before(t) {
+			delete(l.blocked, k)
 		}
+	}
+	if until.After(l.blocked[key]) {
+		l.blocked[key] = until
+	}
+}
+
+// Allow takes one of the requests of the visitor at ip, reporting false
+// when it has none left.
+func (l *RateLimiter) Allow(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v, exists := l.visitors[ip]
+	now := time.Now()
 
-		visitorsLock.Lock()
-		v, exists := visitors[ip]
-		now := time.Now()
-
-		if !exists || now.Sub(v.lastSeen) > window {
-			v = &visitor{tokens: maxRequests - 1, lastSeen: now}
-			visitors[ip] = v
-		} else {
-			if v.tokens <= 0 {
-				visitorsLock.Unlock()
-				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
-				return
-			}
-			v.tokens--
-			v.lastSeen = now
+	if len(l.blocked) > 0 {
+		if until, ok := l.blocked[l.opts.BlockKey(ip)]; ok && now.Before(until) {
+			return false
 		}
-		visitorsLock.Unlock()
+	}
+	if !exists || now.Sub(v.lastSeen) > window {
+		l.visitors[ip] = &visitor{tokens: maxRequests - 1, lastSeen: now}
+		return true
+	}
+	if v.tokens <= 0 {
+		return false
+	}
+	v.tokens--
+	v.lastSeen = now
+	if v.tokens == 0 && l.opts.RateLimited != nil {
+		go l.opts.RateLimited(ip, now.Add(window))
+	}
+	return true
+}
 
+// RateLimit answers 429 to visitors that ran out of requests.
+func (l *RateLimiter) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.Allow(visitorIP(r)) {
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
 		next.ServeHTTP(w, r)
 	})
 }
+
+// visitorIP is the address set by RealIP, or the connection's.
+func visitorIP(r *http.Request) string {
+	if ip := r.Header.Get("X-Client-IP"); ip != "" {
+		return ip
+	}
+	return r.RemoteAddr
+}
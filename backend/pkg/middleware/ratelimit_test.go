@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limited := make(chan string, 1)
+	l := NewRateLimiter(RateLimitOptions{
+		BlockKey:    func(ip string) string { return "key:" + ip },
+		RateLimited: func(ip string, _ time.Time) { limited <- ip },
+	})
+	handler := l.RateLimit(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	get := func(ip string) int {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Client-IP", ip)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for i := 0; i < maxRequests; i++ {
+		if code := get("1.2.3.4"); code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, code)
+		}
+	}
+	if code := get("1.2.3.4"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 once the window is used up, got %d", code)
+	}
+	select {
+	case ip := <-limited:
+		if ip != "1.2.3.4" {
+			t.Errorf("Expected RateLimited for 1.2.3.4, got %s", ip)
+		}
+	case <-time.After(time.Second):
+		t.Error("RateLimited was not called")
+	}
+
+	l.Block("key:5.6.7.8", time.Now().Add(time.Minute))
+	if code := get("5.6.7.8"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 for a blocked visitor, got %d", code)
+	}
+	if code := get("9.9.9.9"); code != http.StatusOK {
+		t.Errorf("Expected 200 for another visitor, got %d", code)
+	}
+
+	other := NewRateLimiter(RateLimitOptions{})
+	if code := func() int {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Client-IP", "1.2.3.4")
+		w := httptest.NewRecorder()
+		other.RateLimit(http.NotFoundHandler()).ServeHTTP(w, req)
+		return w.Code
+	}(); code != http.StatusNotFound {
+		t.Errorf("Expected a separate limiter to have its own budget, got %d", code)
+	}
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := NewRateLimiter(RateLimitOptions{})
+	for i := 0; i < maxRequests; i++ {
+		if !l.Allow("1.2.3.4") {
+			t.Fatalf("request %d: expected Allow", i+1)
+		}
+	}
+	if l.Allow("1.2.3.4") {
+		t.Error("Expected Allow to refuse once the window is used up")
+	}
+	if !l.Allow("5.6.7.8") {
+		t.Error("Expected another visitor to be allowed")
+	}
+}
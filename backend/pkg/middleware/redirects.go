@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/onnwee/onnwee.github.io/backend/internal/db"
+	"github.com/onnwee/onnwee.github.io/backend/internal/redirects"
+)
+
+const (
+	// trackQueueSize bounds the redirect hits and 404s waiting to be
+	// written. When the queue is full, further ones are dropped, so a burst
+	// of scanner traffic cannot pile up goroutines or hold DB connections.
+	trackQueueSize = 1024
+	// trackTimeout bounds each write.
+	trackTimeout = 5 * time.Second
+	// maxNotFoundPaths caps the distinct paths in not_found_paths; hits on
+	// paths already tracked are still counted.
+	maxNotFoundPaths = 10000
+	// notFoundRetention is how long a path is kept after its last 404.
+	notFoundRetention = 30 * 24 * time.Hour
+)
+
+// tracker writes hit counters from a single goroutine.
+type tracker struct {
+	queries *db.Queries
+	jobs    chan func(ctx context.Context) error
+}
+
+var (
+	trackers   = map[*db.Queries]*tracker{}
+	trackersMu sync.Mutex
+)
+
+// trackerFor returns the tracker of queries, starting it on first use.
+func trackerFor(queries *db.Queries) *tracker {
+	trackersMu.Lock()
+	defer trackersMu.Unlock()
+	t, ok := trackers[queries]
+	if !ok {
+		t = &tracker{queries: queries, jobs: make(chan func(ctx context.Context) error, trackQueueSize)}
+		trackers[queries] = t
+		go t.run()
+	}
+	return t
+}
+
+func (t *tracker) run() {
+	prune := time.NewTicker(time.Hour)
+	defer prune.Stop()
+	for {
+		select {
+		case job := <-t.jobs:
+			t.do(job)
+		case <-prune.C:
+			t.do(func(ctx context.Context) error {
+				cutoff := sql.NullTime{Time: time.Now().Add(-notFoundRetention), Valid: true}
+				_, err := t.queries.DeleteNotFoundPathsSeenBefore(ctx, cutoff)
+				return err
+			})
+		}
+	}
+}
+
+func (t *tracker) do(job func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), trackTimeout)
+	defer cancel()
+	if err := job(ctx); err != nil {
+		log.Printf("Failed to record hit: %v", err)
+	}
+}
+
+// enqueue queues a write, dropping it when the queue is full.
+func (t *tracker) enqueue(job func(ctx context.Context) error) {
+	select {
+	case t.jobs <- job:
+	default:
+	}
+}
+
+// Redirects answers GET and HEAD requests matching an admin-managed redirect
+// rule before they reach the router. Hits are counted while the visitor has
+// requests left in writes; the redirect is answered either way.
+func Redirects(queries *db.Queries, m *redirects.Matcher, writes *RateLimiter) Middleware {
+	t := trackerFor(queries)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rule, location, ok := m.Match(r.Context(), r.URL.Path, r.URL.RawQuery)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Counted in the background; the request context ends with the response
+			if writes.Allow(visitorIP(r)) {
+				t.enqueue(func(ctx context.Context) error { return queries.IncrementRedirectHits(ctx, rule.ID) })
+			}
+
+			http.Redirect(w, r, location, rule.StatusCode)
+		})
+	}
+}
+
+// TrackNotFound records GETs that end in a 404, aggregated per path, while
+// the visitor has requests left in writes.
+func TrackNotFound(queries *db.Queries, writes *RateLimiter) Middleware {
+	t := trackerFor(queries)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrapped, r)
+
+			if wrapped.statusCode == http.StatusNotFound && writes.Allow(visitorIP(r)) {
+				params := db.RecordNotFoundParams{
+					Path:         r.URL.Path,
+					LastReferrer: sql.NullString{String: r.Referer(), Valid: r.Referer() != ""},
+					MaxPaths:     maxNotFoundPaths,
+				}
+				t.enqueue(func(ctx context.Context) error { return queries.RecordNotFound(ctx, params) })
+			}
+		})
+	}
+}
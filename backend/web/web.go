@@ -0,0 +1,27 @@
+// Package web embeds the built client. `make client` builds it into
+// web/dist; without that, the binary embeds only a placeholder and serves
+// the API alone.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:dist
+var dist embed.FS
+
+// Dist returns the embedded build, rooted at its index.html.
+func Dist() fs.FS {
+	sub, err := fs.Sub(dist, "dist")
+	if err != nil {
+		panic(err) // dist is a valid, embedded directory
+	}
+	return sub
+}
+
+// Built reports whether fsys holds a client build.
+func Built(fsys fs.FS) bool {
+	info, err := fs.Stat(fsys, "index.html")
+	return err == nil && !info.IsDir()
+}
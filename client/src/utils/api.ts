@@ -32,7 +32,13 @@ export type ApiPost = {
   updated_at?: string
 }
 
-const base = '/api'
+const base = import.meta.env.VITE_API_BASE_URL || '/api'
+
+// Static mode reads the files written by `go run ./cmd/export` (e.g. on GitHub
+// Pages): every resource is a .json file and posts are pre-paginated.
+const isStatic = import.meta.env.VITE_API_STATIC === 'true'
+
+const resource = (path: string) => (isStatic ? `${base}${path}.json` : `${base}${path}`)
 
 async function json<T>(res: Response): Promise<T> {
   if (!res.ok) {
@@ -44,9 +50,9 @@ async function json<T>(res: Response): Promise<T> {
 
 // Projects
 export const ProjectsApi = {
-  list: () => fetch(`${base}/projects`).then(json<ApiProject[]>),
+  list: () => fetch(resource('/projects')).then(json<ApiProject[]>),
   getBySlug: (slug: string) =>
-    fetch(`${base}/projects/${encodeURIComponent(slug)}`).then(json<ApiProject>),
+    fetch(resource(`/projects/${encodeURIComponent(slug)}`)).then(json<ApiProject>),
   create: (payload: Partial<ApiProject>) =>
     fetch(`${base}/projects`, {
       method: 'POST',
@@ -67,10 +73,16 @@ export const ProjectsApi = {
 
 // Posts
 export const PostsApi = {
+  // In static mode pages are fixed at export time (-page-size, default 10) and
+  // a page past the end is empty.
   list: (limit = 10, offset = 0) =>
-    fetch(`${base}/posts?limit=${limit}&offset=${offset}`).then(json<ApiPost[]>),
+    isStatic
+      ? fetch(resource(`/posts/page/${Math.floor(offset / limit) + 1}`)).then(res =>
+          res.status === 404 ? [] : json<ApiPost[]>(res)
+        )
+      : fetch(`${base}/posts?limit=${limit}&offset=${offset}`).then(json<ApiPost[]>),
   getBySlug: (slug: string) =>
-    fetch(`${base}/posts/${encodeURIComponent(slug)}`).then(json<ApiPost>),
+    fetch(resource(`/posts/${encodeURIComponent(slug)}`)).then(json<ApiPost>),
   create: (payload: Partial<ApiPost>) =>
     fetch(`${base}/posts`, {
       method: 'POST',
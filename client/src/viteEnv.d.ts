@@ -2,6 +2,8 @@
 
 interface ImportMetaEnv {
   readonly VITE_ADMIN_TOKEN?: string
+  readonly VITE_API_BASE_URL?: string
+  readonly VITE_API_STATIC?: string
 }
 
 // eslint-disable-next-line no-unused-vars